@@ -5,15 +5,19 @@ package main
 import (
 	"flag"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
 
 	"github.com/alexcatdad/catscan/internal/config"
+	"github.com/alexcatdad/catscan/internal/logging"
 	"github.com/alexcatdad/catscan/internal/server"
 )
 
 var (
-	testMode = flag.Bool("test", false, "Enable test mode (use fixture data)")
+	testMode       = flag.Bool("test", false, "Enable test mode (use fixture data)")
+	githubFixtures = flag.String("github-fixtures", "", "Read GitHub data from this fixture directory instead of gh")
+	configFile     = flag.String("config", "", "Read and write config from this file instead of the default location")
+	validateOnly   = flag.Bool("validate", false, "Check the config for problems and exit without starting the server")
 )
 
 func main() {
@@ -22,27 +26,54 @@ func main() {
 	// Check for test mode
 	if *testMode || os.Getenv("CATSCAN_TEST") == "1" {
 		if err := runTestMode(); err != nil {
-			log.Fatalf("Test mode failed: %v", err)
+			fatal("test mode failed", err)
 		}
 		return
 	}
 
 	// Normal mode
+	if *configFile != "" {
+		config.SetPath(*configFile)
+	}
+	if *validateOnly {
+		if err := config.Check(); err != nil {
+			fmt.Fprintf(os.Stderr, "invalid config: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println("config is valid")
+		return
+	}
 	cfg, err := config.Load()
 	if err != nil {
-		log.Fatalf("Failed to load config: %v", err)
+		fatal("failed to load config", err)
+	}
+	logging.Setup(&cfg)
+	if *githubFixtures != "" {
+		cfg.GitHubFixtureDir = *githubFixtures
 	}
 
 	srv, err := server.NewServer(&cfg)
 	if err != nil {
-		log.Fatalf("Failed to create server: %v", err)
+		fatal("failed to create server", err)
+	}
+
+	// Pick up edits to config.json without a restart. Skipped when flags
+	// override the file, since a reload would drop the override.
+	if *githubFixtures == "" {
+		srv.WatchConfig()
 	}
 
 	if err := srv.Start(); err != nil {
-		log.Fatalf("Server error: %v", err)
+		fatal("server error", err)
 	}
 }
 
+// fatal logs err with msg and exits.
+func fatal(msg string, err error) {
+	slog.Error(msg, "err", err)
+	os.Exit(1)
+}
+
 // runTestMode starts the server in test mode with fixture data.
 func runTestMode() error {
 	// Create test config
@@ -54,6 +85,7 @@ func runTestMode() error {
 		GitHubIntervalSeconds: 60,
 		StaleDays:            90,
 		AbandonedDays:        365,
+		GitHubFixtureDir:     getFixturePath("github"),
 		Notifications: config.NotificationConfig{
 			ActionsChanged: true,
 			NewRelease:     true,
@@ -71,7 +103,7 @@ func runTestMode() error {
 		return fmt.Errorf("failed to create test server: %w", err)
 	}
 
-	log.Printf("Starting CatScan in test mode on port %d", cfg.Port)
+	slog.Info("starting CatScan in test mode", "port", cfg.Port)
 	return srv.Start()
 }
 
@@ -1,6 +1,6 @@
 // API client for the CatScan backend.
 
-import type { Config, FilterOptions, Health, Repo, SortOptions } from "./types";
+import type { Config, FilterOptions, Health, Repo, RepoStats, SortOptions } from "./types";
 
 const API_BASE = "/api";
 
@@ -14,9 +14,23 @@ export class APIError extends Error {
 	}
 }
 
+// localStorage key holding the API token when the backend sets authToken.
+export const AUTH_TOKEN_KEY = "catscan.authToken";
+
+// The stored API token, or null when none is set.
+export function getAuthToken(): string | null {
+	return localStorage.getItem(AUTH_TOKEN_KEY);
+}
+
 // Helper to make fetch requests and throw on non-2xx status codes.
+// Sends the stored API token, if any, as a bearer token.
 async function fetchJSON<T>(url: string, options?: RequestInit): Promise<T> {
-	const response = await fetch(url, options);
+	const headers = new Headers(options?.headers);
+	const token = getAuthToken();
+	if (token) {
+		headers.set("Authorization", `Bearer ${token}`);
+	}
+	const response = await fetch(url, { ...options, headers });
 
 	if (!response.ok) {
 		let message = `HTTP ${response.status}`;
@@ -50,6 +64,27 @@ export async function getRepos(filters?: FilterOptions, sort?: SortOptions): Pro
 	if (filters?.language) {
 		params.set("language", filters.language);
 	}
+	if (filters?.isNew !== undefined) {
+		params.set("isNew", String(filters.isNew));
+	}
+	if (filters?.dirty !== undefined) {
+		params.set("dirty", String(filters.dirty));
+	}
+	if (filters?.hasReadme !== undefined) {
+		params.set("hasReadme", String(filters.hasReadme));
+	}
+	if (filters?.hasLicense !== undefined) {
+		params.set("hasLicense", String(filters.hasLicense));
+	}
+	if (filters?.topic) {
+		params.set("topic", filters.topic);
+	}
+	if (filters?.tag) {
+		params.set("tag", filters.tag);
+	}
+	if (filters?.includeHidden) {
+		params.set("includeHidden", "true");
+	}
 	if (sort?.field) {
 		params.set("sort", sort.field);
 		params.set("order", sort.order);
@@ -64,6 +99,23 @@ export async function getRepo(name: string): Promise<Repo> {
 	return fetchJSON<Repo>(`${API_BASE}/repos/${encodeURIComponent(name)}`);
 }
 
+// Get the uncommitted diff of a cloned repo.
+export async function getRepoDiff(
+	name: string,
+): Promise<{ repo: string; diff: string; truncated: boolean }> {
+	return fetchJSON<{ repo: string; diff: string; truncated: boolean }>(
+		`${API_BASE}/repos/${encodeURIComponent(name)}/diff`,
+	);
+}
+
+// Remove a repo from the cache. It reappears on the next poll only if it
+// still exists locally or on GitHub.
+export async function deleteRepo(name: string): Promise<{ repo: string; status: string }> {
+	return fetchJSON<{ repo: string; status: string }>(`${API_BASE}/repos/${encodeURIComponent(name)}`, {
+		method: "DELETE",
+	});
+}
+
 // Start cloning a repo.
 export async function cloneRepo(name: string): Promise<{ status: string }> {
 	return fetchJSON<{ status: string }>(`${API_BASE}/repos/${encodeURIComponent(name)}/clone`, {
@@ -71,6 +123,76 @@ export async function cloneRepo(name: string): Promise<{ status: string }> {
 	});
 }
 
+// Dismiss a repo from the default list, or bring it back.
+export async function setRepoHidden(name: string, hidden: boolean): Promise<{ repo: string; hidden: boolean }> {
+	return fetchJSON<{ repo: string; hidden: boolean }>(
+		`${API_BASE}/repos/${encodeURIComponent(name)}/${hidden ? "hide" : "unhide"}`,
+		{
+			method: "POST",
+		},
+	);
+}
+
+// Replace a repo's user tags and note.
+export async function setRepoState(
+	name: string,
+	state: { tags: string[]; note: string },
+): Promise<{ repo: string; tags: string[] | null; note: string }> {
+	return fetchJSON<{ repo: string; tags: string[] | null; note: string }>(
+		`${API_BASE}/repos/${encodeURIComponent(name)}/state`,
+		{
+			method: "PUT",
+			headers: {
+				"Content-Type": "application/json",
+			},
+			body: JSON.stringify(state),
+		},
+	);
+}
+
+// BulkCloneResult lists the repos queued for cloning and those skipped
+// because they were already cloned.
+export interface BulkCloneResult {
+	queued: string[];
+	skipped: string[];
+}
+
+// Clone several repos at once, or every uncloned repo with all=true.
+export async function bulkClone(request: { names?: string[]; all?: boolean }): Promise<BulkCloneResult> {
+	return fetchJSON<BulkCloneResult>(`${API_BASE}/clone`, {
+		method: "POST",
+		headers: { "Content-Type": "application/json" },
+		body: JSON.stringify(request),
+	});
+}
+
+// CloneRecord is a clone in progress or recently finished.
+export interface CloneRecord {
+	repo: string;
+	state: "queued" | "started" | "completed" | "error";
+	startedAt: string;
+	finishedAt?: string;
+	error?: string;
+}
+
+// List clones in progress, then recently finished clones, newest first.
+export async function getClones(): Promise<CloneRecord[]> {
+	return fetchJSON<CloneRecord[]>(`${API_BASE}/clones`);
+}
+
+// Acknowledge every repo's current CI status, muting Actions notifications
+// until a status changes.
+export async function acknowledgeAllActions(): Promise<{ acknowledged: number }> {
+	return fetchJSON<{ acknowledged: number }>(`${API_BASE}/actions/acknowledge-all`, {
+		method: "POST",
+	});
+}
+
+// Get aggregate repo stats.
+export async function getStats(): Promise<RepoStats> {
+	return fetchJSON<RepoStats>(`${API_BASE}/stats`);
+}
+
 // Get the current config.
 export async function getConfig(): Promise<Config> {
 	return fetchJSON<Config>(`${API_BASE}/config`);
@@ -87,6 +209,11 @@ export async function updateConfig(config: Config): Promise<Config> {
 	});
 }
 
+// Get the backend version and instance name.
+export async function getVersion(): Promise<{ version: string; instanceName: string }> {
+	return fetchJSON<{ version: string; instanceName: string }>(`${API_BASE}/version`);
+}
+
 // Get health status.
 export async function getHealth(): Promise<Health> {
 	return fetchJSON<Health>(`${API_BASE}/health`);
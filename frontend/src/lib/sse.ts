@@ -1,12 +1,23 @@
 // SSE client for real-time updates from the CatScan backend.
 
-import type { Repo, SSEEventType } from "./types";
+import type {
+	ChangeEntry,
+	PollErrorsData,
+	RateLimitedData,
+	Repo,
+	RepoRemovedData,
+	ReposSnapshotData,
+	ScanPathErrorData,
+	SSEEventType,
+} from "./types";
+import { getAuthToken } from "./api";
 
 // Event handlers for SSE events.
 export interface SSEHandlers {
-	onConnected?: (clientId: string) => void;
+	onConnected?: (clientId: string, instanceName: string) => void;
 	onReposUpdated?: (repos: Repo[]) => void;
 	onGitHubUpdated?: (repos: Repo[]) => void;
+	onRepoChanged?: (repo: Repo) => void;
 	onActionsChanged?: (data: {
 		repo: string;
 		oldStatus: string;
@@ -27,6 +38,10 @@ export interface SSEHandlers {
 		state: string;
 		error?: string;
 	}) => void;
+	onRepoRemoved?: (data: RepoRemovedData) => void;
+	onRateLimited?: (data: RateLimitedData) => void;
+	onPollErrors?: (data: PollErrorsData) => void;
+	onScanPathError?: (data: ScanPathErrorData) => void;
 	onHeartbeat?: () => void;
 	onError?: (data: { type: string; error: string }) => void;
 }
@@ -51,6 +66,7 @@ export class SSEClient {
 	private reconnectDelay = DEFAULT_RECONNECT.baseDelay;
 	private reconnectConfig: ReconnectConfig;
 	private handshakeReceived = false;
+	private snapshotRepos: Repo[] = [];
 
 	constructor(
 		private url: string,
@@ -84,11 +100,18 @@ export class SSEClient {
 		const eventTypes: SSEEventType[] = [
 			"connected",
 			"repos_updated",
+			"repos_snapshot",
 			"github_updated",
+			"repo_changed",
+			"changes",
 			"actions_changed",
 			"new_release",
 			"pr_opened",
 			"clone_progress",
+			"repo_removed",
+			"rate_limited",
+			"poll_errors",
+			"scan_path_error",
 			"heartbeat",
 			"error",
 		];
@@ -147,7 +170,7 @@ export class SSEClient {
 			switch (eventType) {
 				case "connected":
 					this.handshakeReceived = true;
-					this.handlers.onConnected?.(data.clientId);
+					this.handlers.onConnected?.(data.clientId, data.instanceName ?? "");
 					break;
 				case "repos_updated":
 				case "github_updated":
@@ -160,6 +183,38 @@ export class SSEClient {
 						}
 					}
 					break;
+				case "repo_changed":
+					// One repo changed; the full list is only sent when repos come or go
+					this.handlers.onRepoChanged?.(data as Repo);
+					break;
+				case "repos_snapshot": {
+					// Initial list arrives in pages; render each page as it lands
+					const snapshot = data as ReposSnapshotData;
+					if (snapshot.page === 1) {
+						this.snapshotRepos = [];
+					}
+					this.snapshotRepos = this.snapshotRepos.concat(snapshot.repos ?? []);
+					this.handlers.onReposUpdated?.(this.snapshotRepos);
+					break;
+				}
+				case "changes":
+					// One batched event per poll cycle; dispatch each entry by type
+					if (Array.isArray(data)) {
+						for (const change of data as ChangeEntry[]) {
+							switch (change.type) {
+								case "actions_changed":
+									this.handlers.onActionsChanged?.(change);
+									break;
+								case "new_release":
+									this.handlers.onNewRelease?.(change);
+									break;
+								case "pr_opened":
+									this.handlers.onPROpened?.(change);
+									break;
+							}
+						}
+					}
+					break;
 				case "actions_changed":
 					this.handlers.onActionsChanged?.(data);
 					break;
@@ -172,6 +227,18 @@ export class SSEClient {
 				case "clone_progress":
 					this.handlers.onCloneProgress?.(data);
 					break;
+				case "repo_removed":
+					this.handlers.onRepoRemoved?.(data as RepoRemovedData);
+					break;
+				case "rate_limited":
+					this.handlers.onRateLimited?.(data as RateLimitedData);
+					break;
+				case "poll_errors":
+					this.handlers.onPollErrors?.(data as PollErrorsData);
+					break;
+				case "scan_path_error":
+					this.handlers.onScanPathError?.(data as ScanPathErrorData);
+					break;
 				case "heartbeat":
 					this.handlers.onHeartbeat?.();
 					break;
@@ -186,8 +253,19 @@ export class SSEClient {
 }
 
 // Create and connect an SSE client for the CatScan events endpoint.
-export function createSSEClient(handlers: SSEHandlers): SSEClient {
-	const client = new SSEClient("/api/events", handlers);
+// When events is given, the server only sends those event types.
+export function createSSEClient(handlers: SSEHandlers, events?: SSEEventType[]): SSEClient {
+	const params = new URLSearchParams();
+	// EventSource can't send headers, so the token goes in the query
+	const token = getAuthToken();
+	if (token) {
+		params.set("token", token);
+	}
+	if (events?.length) {
+		params.set("events", events.join(","));
+	}
+	const query = params.toString();
+	const client = new SSEClient(query ? `/api/events?${query}` : "/api/events", handlers);
 	client.connect();
 	return client;
 }
@@ -166,6 +166,9 @@ function setupSSE(): void {
 			_repos = newRepos;
 			_loading = false;
 		},
+		onRepoChanged: (changed) => {
+			_repos = _repos.map((repo) => (repo.Name === changed.Name ? changed : repo));
+		},
 		onActionsChanged: (data) => {
 			_repos = _repos.map((repo) =>
 				repo.Name === data.repo ? { ...repo, ActionsStatus: data.newStatus as any } : repo
@@ -183,6 +186,9 @@ function setupSSE(): void {
 				repo.Name === data.repo ? { ...repo, OpenPRs: data.newCount } : repo
 			);
 		},
+		onRepoRemoved: (data) => {
+			_repos = _repos.filter((repo) => repo.Name !== data.repo);
+		},
 		onCloneProgress: (data) => {
 			if (data.state === "started" || data.state === "cloning") {
 				_cloneInProgress = new Set(_cloneInProgress).add(data.repo);
@@ -257,13 +263,15 @@ export function clearSelection(): void {
 }
 
 export async function cloneSelected(): Promise<void> {
-	for (const repoName of clonableSelected()) {
-		try {
-			await api.cloneRepo(repoName);
-		} catch (err) {
-			if (err instanceof api.APIError) {
-				_error = `Failed to start cloning ${repoName}: ${err.message}`;
-			}
+	const names = clonableSelected();
+	if (names.length === 0) {
+		return;
+	}
+	try {
+		await api.bulkClone({ names });
+	} catch (err) {
+		if (err instanceof api.APIError) {
+			_error = `Failed to start cloning: ${err.message}`;
 		}
 	}
 }
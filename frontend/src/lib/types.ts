@@ -1,7 +1,7 @@
 // TypeScript types matching the Go backend API responses.
 
 // Lifecycle represents the lifecycle status of a repository.
-export type Lifecycle = "ongoing" | "maintenance" | "stale" | "abandoned";
+export type Lifecycle = "ongoing" | "maintenance" | "stale" | "abandoned" | "archived";
 
 // ActionsStatus represents the CI/CD status from GitHub Actions.
 export type ActionsStatus = "none" | "passing" | "failing";
@@ -26,7 +26,9 @@ export interface CompletenessInfo {
 	HasProjectJson: boolean;
 	HasClaudeMd: boolean;
 	HasAgentsMd: boolean;
-	[key: string]: boolean;
+	// Percentage of applicable flags set.
+	Score: number;
+	[key: string]: boolean | number;
 }
 
 // Repo represents a unified repository from local git and GitHub.
@@ -35,6 +37,7 @@ export interface Repo {
 	Name: string;
 	Description: string;
 	Visibility: Visibility;
+	Owner?: string;
 	HomepageURL: string;
 	Topics: string[];
 	Language: string;
@@ -47,6 +50,8 @@ export interface Repo {
 	LocalLastCommit: string;
 
 	// GitHub metadata
+	DefaultBranch?: string;
+	CreatedAt?: string;
 	GitHubLastPush: string;
 	OpenPRs: number;
 	ActionsStatus: ActionsStatus;
@@ -60,6 +65,17 @@ export interface Repo {
 
 	// Lifecycle classification
 	Lifecycle: Lifecycle;
+
+	// Created within the configured new-repo window
+	IsNew: boolean;
+
+	// Dismissed by the user or auto-hidden as long abandoned; only listed
+	// with includeHidden
+	Hidden?: boolean;
+
+	// User annotations from PUT /api/repos/:name/state
+	Tags?: string[];
+	Note?: string;
 }
 
 // Config represents the CatScan configuration.
@@ -72,6 +88,7 @@ export interface Config {
 	githubIntervalSeconds: number;
 	staleDays: number;
 	abandonedDays: number;
+	newRepoDays: number;
 	notifications: NotificationsConfig;
 }
 
@@ -84,25 +101,53 @@ export interface NotificationsConfig {
 	error: boolean;
 }
 
+// PollError is the most recent failure of a poll.
+export interface PollError {
+	Error: string;
+	At: string;
+}
+
 // Health represents the health check response.
 export interface Health {
+	// Configured instanceName; empty when unset.
+	InstanceName: string;
 	Uptime: string;
 	LastLocalPoll: string;
 	LastGitHubPoll: string;
+	// Most recent failure of each poll; null once a poll succeeds.
+	LastLocalPollError: PollError | null;
+	LastGitHubPollError: PollError | null;
 	TotalRepos: number;
 	GhAvailable: boolean;
 	GhAuthenticated: boolean;
+	// Account gh is logged in to; empty when not authenticated.
+	GhLogin: string;
+	GhHost: string;
+	GhMissingScopes: string[];
+	GitHubHealthy: boolean;
+	GitHubFailures: number;
+	// Connected SSE clients and the configured limit, 0 when unlimited.
+	SSEClients: number;
+	SSEMaxClients: number;
+	Warnings: string[];
 }
 
 // SSE event types from the backend.
 export type SSEEventType =
 	| "connected"
 	| "repos_updated"
+	| "repos_snapshot"
 	| "github_updated"
+	| "repo_changed"
+	| "changes"
 	| "actions_changed"
 	| "new_release"
 	| "pr_opened"
 	| "clone_progress"
+	| "repo_removed"
+	| "rate_limited"
+	| "poll_errors"
+	| "scan_path_error"
 	| "heartbeat"
 	| "error";
 
@@ -112,6 +157,20 @@ export interface SSEEvent {
 	data: unknown;
 }
 
+// ReposSnapshotData represents one page of the initial repo list.
+export interface ReposSnapshotData {
+	page: number;
+	total: number;
+	repos: Repo[];
+}
+
+// ChangeEntry represents one change in a batched "changes" event.
+// The type field names the change; the remaining fields match its payload.
+export type ChangeEntry =
+	| { type: "actions_changed"; repo: string; oldStatus: string; newStatus: string }
+	| { type: "new_release"; repo: string; tagName: string; released: string }
+	| { type: "pr_opened"; repo: string; oldCount: number; newCount: number };
+
 // CloneProgressData represents clone progress event data.
 export interface CloneProgressData {
 	repo: string;
@@ -119,6 +178,41 @@ export interface CloneProgressData {
 	error?: string;
 }
 
+// RepoRemovedData represents repo_removed event data.
+export interface RepoRemovedData {
+	repo: string;
+}
+
+// RateLimitedData represents rate_limited event data.
+// resetAt is present only when GitHub reported when the limit resets.
+export interface RateLimitedData {
+	error: string;
+	resumeAt: string;
+	resetAt?: string;
+}
+
+// RepoPollError is one per-repo failure in a poll cycle.
+export interface RepoPollError {
+	repo: string;
+	stage: string;
+	error: string;
+}
+
+// PollErrorsData represents poll_errors event data. An empty errors list
+// clears the failures previously reported for the source.
+export interface PollErrorsData {
+	source: "local" | "github";
+	errors: RepoPollError[];
+}
+
+// ScanPathErrorData represents scan_path_error event data, sent when the
+// configured scanPath can't be scanned.
+export interface ScanPathErrorData {
+	path: string;
+	reason: "not_a_directory" | "permission_denied" | "unreadable";
+	error: string;
+}
+
 // ErrorEventData represents error event data.
 export interface ErrorEventData {
 	type: string;
@@ -131,11 +225,21 @@ export interface FilterOptions {
 	visibility?: string;
 	cloned?: boolean;
 	language?: string;
+	isNew?: boolean;
+	dirty?: boolean;
+	hasReadme?: boolean;
+	hasLicense?: boolean;
+	// Matches any repo whose topics include this one.
+	topic?: string;
+	// Also list dismissed and auto-hidden repos.
+	includeHidden?: boolean;
+	// Matches any repo with this user tag; a comma list matches any of them.
+	tag?: string;
 }
 
 // Sort options for the repo list.
 export interface SortOptions {
-	field: "name" | "lastUpdate" | "lifecycle";
+	field: "name" | "lastUpdate" | "lifecycle" | "lifecyclePriority" | "createdAt" | "openPRs" | "dirty" | "completeness";
 	order: "asc" | "desc";
 }
 
@@ -150,3 +254,15 @@ export interface SummaryStats {
 	stale: number;
 	abandoned: number;
 }
+
+// Aggregate counts from GET /api/stats.
+export interface RepoStats {
+	total: number;
+	cloned: number;
+	notCloned: number;
+	dirty: number;
+	withOpenPRs: number;
+	byLifecycle: Record<string, number>;
+	byLanguage: Record<string, number>;
+	byVisibility: Record<string, number>;
+}
@@ -1,18 +1,29 @@
 // Package cache handles persistent storage of repository data and user state.
 //
-// cache.json stores the full list of Repo objects and is rebuilt on each poll cycle.
-// state.json stores persistent user state like last-seen release tags.
-// Both files are stored in ~/.config/catscan/ and written atomically.
+// cache.json stores the full list of Repo objects, wrapped in a versioned
+// envelope, and is rebuilt on each poll cycle. Single-repo changes go through
+// an in-memory copy that is flushed to disk after a short delay.
+// cache.prev.json holds the previous valid cache.json for recovery from a corrupt write.
+// state.json stores persistent user state like last-seen release tags, with
+// timestamped copies in state-backups/ for recovery from corruption.
+// Both files are stored in the config directory (see config.Dir) and written
+// atomically.
 package cache
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"sync"
+	"time"
 
+	"github.com/alexcatdad/catscan/internal/config"
 	"github.com/alexcatdad/catscan/internal/model"
 )
 
@@ -22,7 +33,10 @@ var (
 )
 
 // SetCachePath sets a custom cache path for testing.
+// Unflushed repo updates for the previous path are discarded.
 func SetCachePath(path string) {
+	discardPending()
+
 	testPathMu.Lock()
 	defer testPathMu.Unlock()
 	testCachePath = path
@@ -39,7 +53,8 @@ func GetCachePath() string {
 	return path
 }
 
-// cacheDir returns the CatScan cache directory path (~/.config/catscan/).
+// cacheDir returns the CatScan cache directory path, shared with the config
+// file (see config.Dir).
 func cacheDir() (string, error) {
 	testPathMu.RLock()
 	if testCachePath != "" {
@@ -48,13 +63,7 @@ func cacheDir() (string, error) {
 	}
 	testPathMu.RUnlock()
 
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return "", fmt.Errorf("getting home directory: %w", err)
-	}
-
-	cacheDir := filepath.Join(homeDir, ".config", "catscan")
-	return cacheDir, nil
+	return config.Dir()
 }
 
 // cachePath returns the full path to cache.json.
@@ -73,6 +82,16 @@ func cachePath() (string, error) {
 	return filepath.Join(dir, "cache.json"), nil
 }
 
+// prevCachePath returns the full path to cache.prev.json, the rollback
+// snapshot kept alongside cache.json.
+func prevCachePath() (string, error) {
+	path, err := cachePath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(path), "cache.prev.json"), nil
+}
+
 // statePath returns the full path to state.json.
 func statePath() (string, error) {
 	testPathMu.RLock()
@@ -89,6 +108,15 @@ func statePath() (string, error) {
 	return filepath.Join(dir, "state.json"), nil
 }
 
+// stateBackupDir returns the directory holding timestamped state.json backups.
+func stateBackupDir() (string, error) {
+	path, err := statePath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(path), "state-backups"), nil
+}
+
 // ensureCacheDir creates the cache directory if it doesn't exist.
 func ensureCacheDir() error {
 	dir, err := cacheDir()
@@ -137,17 +165,87 @@ func writeAtomic(path string, data []byte) error {
 	return nil
 }
 
+// CacheSchemaVersion is the cache.json format version written by WriteRepos.
+// Bump it, and teach decodeRepos to migrate the old version, whenever a
+// change to model.Repo would misread existing caches.
+const CacheSchemaVersion = 1
+
+// ErrCacheSchemaTooNew is returned when cache.json was written by a newer
+// CatScan with a schema this version cannot read.
+var ErrCacheSchemaTooNew = errors.New("cache schema version is newer than supported")
+
+// cacheEnvelope is the on-disk format of cache.json.
+type cacheEnvelope struct {
+	SchemaVersion int          `json:"schemaVersion"`
+	Repos         []model.Repo `json:"repos"`
+}
+
+// decodeRepos parses cache.json data in either the versioned envelope or
+// the legacy bare-array format. legacy reports whether the data was in the
+// legacy format and should be rewritten.
+func decodeRepos(data []byte) (repos []model.Repo, legacy bool, err error) {
+	// Before versioning, cache.json was a bare array of repos
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &repos); err != nil {
+			return nil, false, err
+		}
+		return repos, true, nil
+	}
+
+	var env cacheEnvelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, false, err
+	}
+	if env.SchemaVersion > CacheSchemaVersion {
+		return nil, false, fmt.Errorf("%w: file has version %d, this build reads up to %d", ErrCacheSchemaTooNew, env.SchemaVersion, CacheSchemaVersion)
+	}
+	if env.SchemaVersion < 1 {
+		return nil, false, fmt.Errorf("cache envelope missing schemaVersion")
+	}
+	if env.Repos == nil {
+		env.Repos = []model.Repo{}
+	}
+	return env.Repos, false, nil
+}
+
 // RepoState stores persistent user state per repository.
 type RepoState map[string]*RepoStateEntry
 
 // RepoStateEntry holds state data for a single repository.
 type RepoStateEntry struct {
-	LastSeenReleaseTag string `json:"lastSeenReleaseTag"`
+	LastSeenReleaseTag string    `json:"lastSeenReleaseTag"`
+	SnoozeUntil        time.Time `json:"snoozeUntil,omitzero"`
+
+	// AckedActionsStatus is the Actions status the user acknowledged.
+	// Changes to this status don't notify.
+	AckedActionsStatus string `json:"ackedActionsStatus,omitempty"`
+
+	// Hidden dismisses the repo from the default repo list.
+	Hidden bool `json:"hidden,omitempty"`
+
+	// AutoHiddenAt is when the repo was auto-hidden as long abandoned.
+	// Zero means the repo is not hidden.
+	AutoHiddenAt time.Time `json:"autoHiddenAt,omitzero"`
+
+	// Tags and Note are the user's own annotations on the repo.
+	Tags []string `json:"tags,omitempty"`
+	Note string   `json:"note,omitempty"`
 }
 
-// ReadRepos reads the full repo list from cache.json.
+// ReadRepos reads the full repo list from cache.json, including updates
+// from UpdateRepo and RemoveRepo that haven't been flushed yet.
 // If the file doesn't exist or is empty, returns an empty slice.
+// A legacy bare-array cache is migrated to the current schema in place.
+// A cache written with a newer schema returns ErrCacheSchemaTooNew.
 func ReadRepos() ([]model.Repo, error) {
+	if repos := pendingRepos(); repos != nil {
+		return repos, nil
+	}
+	return readReposFile()
+}
+
+// readReposFile reads the repo list from cache.json on disk.
+func readReposFile() ([]model.Repo, error) {
 	cachePath, err := cachePath()
 	if err != nil {
 		return nil, err
@@ -167,18 +265,88 @@ func ReadRepos() ([]model.Repo, error) {
 		return []model.Repo{}, nil
 	}
 
-	var repos []model.Repo
-	if err := json.Unmarshal(data, &repos); err != nil {
-		return nil, fmt.Errorf("parsing cache JSON: %w", err)
+	repos, legacy, err := decodeRepos(data)
+	if errors.Is(err, ErrCacheSchemaTooNew) {
+		// The snapshot is from the same newer build; don't guess at it
+		return nil, err
+	}
+	if err != nil {
+		// A crash mid-write can leave a partial file; fall back to the snapshot
+		recovered, prevErr := readPrevRepos()
+		if prevErr != nil {
+			return nil, fmt.Errorf("parsing cache JSON: %w", err)
+		}
+		slog.Warn("recovered from corrupt cache using cache.prev.json", "err", err, "repos", len(recovered))
+		return recovered, nil
+	}
+
+	if legacy {
+		// Migration failure only delays the rewrite to the next poll
+		if err := writeReposFile(repos); err != nil {
+			slog.Error("cache migration failed", "schemaVersion", CacheSchemaVersion, "err", err)
+		} else {
+			slog.Info("migrated cache", "schemaVersion", CacheSchemaVersion)
+		}
 	}
 
 	return repos, nil
 }
 
-// WriteRepos writes the full repo list to cache.json.
+// readPrevRepos reads the repo list from the cache.prev.json snapshot.
+func readPrevRepos() ([]model.Repo, error) {
+	path, err := prevCachePath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading cache snapshot: %w", err)
+	}
+
+	repos, _, err := decodeRepos(data)
+	if err != nil {
+		return nil, fmt.Errorf("parsing cache snapshot JSON: %w", err)
+	}
+
+	return repos, nil
+}
+
+// snapshotCache copies the current cache.json to cache.prev.json if it is
+// valid JSON, so a corrupt primary never replaces a good snapshot.
+func snapshotCache(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("reading cache file: %w", err)
+	}
+	if len(data) == 0 || !json.Valid(data) {
+		return nil
+	}
+
+	prevPath, err := prevCachePath()
+	if err != nil {
+		return err
+	}
+	return writeAtomic(prevPath, data)
+}
+
+// WriteRepos writes the full repo list to cache.json, replacing any
+// unflushed updates from UpdateRepo and RemoveRepo.
 // The cache directory is created if it doesn't exist.
 // Write is atomic (temp file + rename).
 func WriteRepos(repos []model.Repo) error {
+	pending.mu.Lock()
+	defer pending.mu.Unlock()
+
+	pending.clear()
+	return writeReposFile(repos)
+}
+
+// writeReposFile writes the repo list to cache.json on disk.
+func writeReposFile(repos []model.Repo) error {
 	if err := ensureCacheDir(); err != nil {
 		return err
 	}
@@ -188,12 +356,22 @@ func WriteRepos(repos []model.Repo) error {
 		return err
 	}
 
+	if repos == nil {
+		repos = []model.Repo{}
+	}
+
 	// Marshal with indentation for readability
-	data, err := json.MarshalIndent(repos, "", "  ")
+	data, err := json.MarshalIndent(cacheEnvelope{SchemaVersion: CacheSchemaVersion, Repos: repos}, "", "  ")
 	if err != nil {
 		return fmt.Errorf("marshaling cache JSON: %w", err)
 	}
 
+	// Keep the current cache as the rollback snapshot. Failure here only
+	// loses recoverability, so it doesn't block the write.
+	if err := snapshotCache(path); err != nil {
+		slog.Error("cache snapshot failed", "err", err)
+	}
+
 	if err := writeAtomic(path, data); err != nil {
 		return fmt.Errorf("writing cache atomically: %w", err)
 	}
@@ -201,6 +379,147 @@ func WriteRepos(repos []model.Repo) error {
 	return nil
 }
 
+// flushDelay is how long UpdateRepo and RemoveRepo wait for further
+// changes before writing cache.json, so a burst of single-repo changes
+// costs one write.
+const flushDelay = 500 * time.Millisecond
+
+// pendingCache holds the repo list with single-repo changes not yet
+// written to cache.json, indexed by repo name.
+type pendingCache struct {
+	mu    sync.Mutex
+	repos []model.Repo // nil when nothing is pending
+	index map[string]int
+	timer *time.Timer
+}
+
+// pending is the process-wide set of unflushed repo changes.
+var pending pendingCache
+
+// load reads cache.json into the pending list if nothing is pending.
+// Callers must hold c.mu.
+func (c *pendingCache) load() error {
+	if c.repos != nil {
+		return nil
+	}
+	repos, err := readReposFile()
+	if err != nil {
+		return err
+	}
+	c.repos = repos
+	c.reindex()
+	return nil
+}
+
+// reindex rebuilds the name index. Callers must hold c.mu.
+func (c *pendingCache) reindex() {
+	c.index = make(map[string]int, len(c.repos))
+	for i, repo := range c.repos {
+		c.index[repo.Name] = i
+	}
+}
+
+// schedule arms the debounced flush. Callers must hold c.mu.
+func (c *pendingCache) schedule() {
+	if c.timer != nil {
+		c.timer.Reset(flushDelay)
+		return
+	}
+	c.timer = time.AfterFunc(flushDelay, func() {
+		if err := Flush(); err != nil {
+			slog.Error("cache flush failed", "err", err)
+		}
+	})
+}
+
+// clear drops the pending list and stops any scheduled flush.
+// Callers must hold c.mu.
+func (c *pendingCache) clear() {
+	if c.timer != nil {
+		c.timer.Stop()
+		c.timer = nil
+	}
+	c.repos = nil
+	c.index = nil
+}
+
+// pendingRepos returns a copy of the unflushed repo list, or nil if
+// nothing is pending.
+func pendingRepos() []model.Repo {
+	pending.mu.Lock()
+	defer pending.mu.Unlock()
+	if pending.repos == nil {
+		return nil
+	}
+	return slices.Clone(pending.repos)
+}
+
+// discardPending drops unflushed changes without writing them.
+func discardPending() {
+	pending.mu.Lock()
+	defer pending.mu.Unlock()
+	pending.clear()
+}
+
+// UpdateRepo replaces the cached repo with the same name, or appends it if
+// it isn't cached yet. The change is visible to ReadRepos immediately and
+// written to cache.json after a short delay.
+func UpdateRepo(repo model.Repo) error {
+	pending.mu.Lock()
+	defer pending.mu.Unlock()
+
+	if err := pending.load(); err != nil {
+		return err
+	}
+	if i, ok := pending.index[repo.Name]; ok {
+		pending.repos[i] = repo
+	} else {
+		pending.index[repo.Name] = len(pending.repos)
+		pending.repos = append(pending.repos, repo)
+	}
+	pending.schedule()
+	return nil
+}
+
+// RemoveRepo removes the named repo from the cache. Removing a repo that
+// isn't cached is a no-op. Like UpdateRepo, the write is deferred.
+func RemoveRepo(name string) error {
+	pending.mu.Lock()
+	defer pending.mu.Unlock()
+
+	if err := pending.load(); err != nil {
+		return err
+	}
+	i, ok := pending.index[name]
+	if !ok {
+		return nil
+	}
+	pending.repos = slices.Delete(pending.repos, i, i+1)
+	pending.reindex()
+	pending.schedule()
+	return nil
+}
+
+// Flush writes any pending UpdateRepo and RemoveRepo changes to cache.json
+// now. On failure the changes stay pending.
+func Flush() error {
+	pending.mu.Lock()
+	defer pending.mu.Unlock()
+
+	if pending.timer != nil {
+		pending.timer.Stop()
+		pending.timer = nil
+	}
+	if pending.repos == nil {
+		return nil
+	}
+	if err := writeReposFile(pending.repos); err != nil {
+		return err
+	}
+	pending.clear()
+	return nil
+}
+
 // ReadState reads the persistent user state from state.json.
 // If the file doesn't exist or is empty, returns an empty state map.
 func ReadState() (RepoState, error) {
@@ -225,7 +544,13 @@ func ReadState() (RepoState, error) {
 
 	var state RepoState
 	if err := json.Unmarshal(data, &state); err != nil {
-		return nil, fmt.Errorf("parsing state JSON: %w", err)
+		// Fall back to the newest backup that parses
+		recovered, name, backupErr := readStateBackup()
+		if backupErr != nil {
+			return nil, fmt.Errorf("parsing state JSON: %w", err)
+		}
+		slog.Warn("recovered from corrupt state using backup", "backup", name, "err", err)
+		return recovered, nil
 	}
 
 	// Handle null map
@@ -236,6 +561,113 @@ func ReadState() (RepoState, error) {
 	return state, nil
 }
 
+// stateBackupTimeFormat names backups so they sort oldest to newest.
+const stateBackupTimeFormat = "20060102T150405.000000000Z"
+
+// BackupState copies state.json into a timestamped file in the
+// state-backups directory and deletes all but the newest keep backups.
+// A missing, empty, or unparsable state.json is not backed up, so a
+// corrupt primary never displaces a good backup.
+func BackupState(keep int) error {
+	path, err := statePath()
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("reading state file: %w", err)
+	}
+	var state RepoState
+	if len(data) == 0 || json.Unmarshal(data, &state) != nil {
+		return nil
+	}
+
+	dir, err := stateBackupDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("creating state backup directory: %w", err)
+	}
+
+	name := "state-" + time.Now().UTC().Format(stateBackupTimeFormat) + ".json"
+	if err := writeAtomic(filepath.Join(dir, name), data); err != nil {
+		return fmt.Errorf("writing state backup: %w", err)
+	}
+
+	backups, err := listStateBackups()
+	if err != nil {
+		return err
+	}
+	for len(backups) > keep {
+		if err := os.Remove(filepath.Join(dir, backups[0])); err != nil {
+			return fmt.Errorf("pruning state backup: %w", err)
+		}
+		backups = backups[1:]
+	}
+
+	return nil
+}
+
+// listStateBackups returns the names of state backups, oldest first.
+func listStateBackups() ([]string, error) {
+	dir, err := stateBackupDir()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading state backup directory: %w", err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() && strings.HasPrefix(name, "state-") && strings.HasSuffix(name, ".json") {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+// readStateBackup returns the state from the newest backup that parses,
+// along with that backup's file name.
+func readStateBackup() (RepoState, string, error) {
+	dir, err := stateBackupDir()
+	if err != nil {
+		return nil, "", err
+	}
+	backups, err := listStateBackups()
+	if err != nil {
+		return nil, "", err
+	}
+
+	for i := len(backups) - 1; i >= 0; i-- {
+		data, err := os.ReadFile(filepath.Join(dir, backups[i]))
+		if err != nil {
+			continue
+		}
+		var state RepoState
+		if err := json.Unmarshal(data, &state); err != nil {
+			continue
+		}
+		if state == nil {
+			state = RepoState{}
+		}
+		return state, backups[i], nil
+	}
+	return nil, "", errors.New("no usable state backup")
+}
+
 // WriteState writes the persistent user state to state.json.
 // The cache directory is created if it doesn't exist.
 // Write is atomic (temp file + rename).
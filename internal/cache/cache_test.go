@@ -1,7 +1,13 @@
 package cache_test
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
@@ -9,6 +15,13 @@ import (
 	"github.com/alexcatdad/catscan/internal/model"
 )
 
+// TestMain clears XDG_CONFIG_HOME so tests that point HOME at a temp
+// directory never touch the real config directory.
+func TestMain(m *testing.M) {
+	os.Unsetenv("XDG_CONFIG_HOME")
+	os.Exit(m.Run())
+}
+
 // TestReadReposWhenFileDoesntExist tests that ReadRepos returns empty list
 // when the cache file doesn't exist.
 func TestReadReposWhenFileDoesntExist(t *testing.T) {
@@ -116,6 +129,24 @@ func TestWriteAndReadReposRoundTrip(t *testing.T) {
 	}
 }
 
+// TestCachePathHonorsXDGConfigHome tests that cache.json resolves under
+// XDG_CONFIG_HOME when set and under ~/.config otherwise.
+func TestCachePathHonorsXDGConfigHome(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	t.Setenv("XDG_CONFIG_HOME", "")
+	if got, want := cache.GetCachePath(), filepath.Join(home, ".config", "catscan", "cache.json"); got != want {
+		t.Errorf("GetCachePath() = %s, want %s", got, want)
+	}
+
+	xdg := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+	if got, want := cache.GetCachePath(), filepath.Join(xdg, "catscan", "cache.json"); got != want {
+		t.Errorf("GetCachePath() = %s, want %s", got, want)
+	}
+}
+
 // TestReadStateWhenFileDoesntExist tests that ReadState returns empty map
 // when the state file doesn't exist.
 func TestReadStateWhenFileDoesntExist(t *testing.T) {
@@ -187,6 +218,123 @@ func TestWriteAndReadStateRoundTrip(t *testing.T) {
 	}
 }
 
+// TestStateAnnotationsRoundTrip tests that user tags and notes survive a
+// write and read, and that state written before they existed still loads.
+func TestStateAnnotationsRoundTrip(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// Override home directory
+	originalHome := os.Getenv("HOME")
+	t.Cleanup(func() {
+		os.Setenv("HOME", originalHome)
+	})
+	os.Setenv("HOME", tmpDir)
+
+	testState := cache.RepoState{
+		"catscan": &cache.RepoStateEntry{
+			LastSeenReleaseTag: "v1.0.0",
+			Tags:               []string{"migrate-to-v2", "work"},
+			Note:               "Move the poller to the new API",
+		},
+	}
+	if err := cache.WriteState(testState); err != nil {
+		t.Fatalf("WriteState() failed: %v", err)
+	}
+
+	loaded, err := cache.ReadState()
+	if err != nil {
+		t.Fatalf("ReadState() failed: %v", err)
+	}
+	entry := loaded["catscan"]
+	if entry == nil {
+		t.Fatal("catscan entry missing")
+	}
+	if !slices.Equal(entry.Tags, []string{"migrate-to-v2", "work"}) {
+		t.Errorf("Tags = %v, want [migrate-to-v2 work]", entry.Tags)
+	}
+	if entry.Note != "Move the poller to the new API" {
+		t.Errorf("Note = %q, want %q", entry.Note, "Move the poller to the new API")
+	}
+
+	// A state file from before annotations existed
+	statePath := filepath.Join(tmpDir, ".config", "catscan", "state.json")
+	legacy := `{"dotfiles":{"lastSeenReleaseTag":"v0.1.0"}}`
+	if err := os.WriteFile(statePath, []byte(legacy), 0o644); err != nil {
+		t.Fatalf("Failed to write legacy state: %v", err)
+	}
+	loaded, err = cache.ReadState()
+	if err != nil {
+		t.Fatalf("ReadState() on legacy state failed: %v", err)
+	}
+	entry = loaded["dotfiles"]
+	if entry == nil || entry.LastSeenReleaseTag != "v0.1.0" {
+		t.Fatalf("dotfiles entry = %+v, want release tag v0.1.0", entry)
+	}
+	if entry.Tags != nil || entry.Note != "" {
+		t.Errorf("legacy entry annotations = %v, %q, want none", entry.Tags, entry.Note)
+	}
+}
+
+// TestReadStateRecoversFromBackup tests that a corrupt state.json is
+// recovered from the newest backup, and that old backups are pruned.
+func TestReadStateRecoversFromBackup(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// Override home directory
+	originalHome := os.Getenv("HOME")
+	t.Cleanup(func() {
+		os.Setenv("HOME", originalHome)
+	})
+	os.Setenv("HOME", tmpDir)
+
+	snoozeUntil := time.Date(2030, 1, 2, 0, 0, 0, 0, time.UTC)
+	for _, tag := range []string{"v1.0.0", "v1.1.0", "v1.2.0"} {
+		testState := cache.RepoState{
+			"repo1": &cache.RepoStateEntry{LastSeenReleaseTag: tag, SnoozeUntil: snoozeUntil},
+		}
+		if err := cache.WriteState(testState); err != nil {
+			t.Fatalf("WriteState() failed: %v", err)
+		}
+		if err := cache.BackupState(2); err != nil {
+			t.Fatalf("BackupState() failed: %v", err)
+		}
+	}
+
+	backupDir := filepath.Join(tmpDir, ".config", "catscan", "state-backups")
+	entries, err := os.ReadDir(backupDir)
+	if err != nil {
+		t.Fatalf("Failed to read backup dir: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("len(backups) = %d, want 2", len(entries))
+	}
+
+	statePath := filepath.Join(tmpDir, ".config", "catscan", "state.json")
+	if err := os.WriteFile(statePath, []byte(`{"repo1": {"lastSeen`), 0o644); err != nil {
+		t.Fatalf("Failed to corrupt state: %v", err)
+	}
+
+	// A corrupt primary must not displace the good backups
+	if err := cache.BackupState(2); err != nil {
+		t.Fatalf("BackupState() failed: %v", err)
+	}
+
+	loaded, err := cache.ReadState()
+	if err != nil {
+		t.Fatalf("ReadState() failed: %v", err)
+	}
+	entry := loaded["repo1"]
+	if entry == nil {
+		t.Fatal("repo1 missing from recovered state")
+	}
+	if entry.LastSeenReleaseTag != "v1.2.0" {
+		t.Errorf("LastSeenReleaseTag = %s, want v1.2.0", entry.LastSeenReleaseTag)
+	}
+	if !entry.SnoozeUntil.Equal(snoozeUntil) {
+		t.Errorf("SnoozeUntil = %v, want %v", entry.SnoozeUntil, snoozeUntil)
+	}
+}
+
 // TestAtomicWriteDoesntCorruptExistingData tests that atomic writes
 // don't corrupt existing data if the write fails partway through.
 func TestAtomicWriteDoesntCorruptExistingData(t *testing.T) {
@@ -248,14 +396,10 @@ func TestEmptyFileHandling(t *testing.T) {
 	tmpDir := t.TempDir()
 
 	// Override home directory
-	originalHome := os.Getenv("HOME")
-	t.Cleanup(func() {
-		os.Setenv("HOME", originalHome)
-	})
-	os.Setenv("HOME", tmpDir)
+	t.Setenv("HOME", tmpDir)
 
-	// Create cache directory and write empty files
-	configDir := ".config/catscan"
+	// Create cache directory under the temp home and write empty files
+	configDir := filepath.Join(tmpDir, ".config", "catscan")
 	if err := os.MkdirAll(configDir, 0o755); err != nil {
 		t.Fatalf("Failed to create config dir: %v", err)
 	}
@@ -288,3 +432,295 @@ func TestEmptyFileHandling(t *testing.T) {
 		t.Errorf("len(state) = %d, want 0", len(state))
 	}
 }
+
+// TestReadReposRecoversFromCorruptCache tests that a half-written cache.json
+// falls back to the cache.prev.json snapshot.
+func TestReadReposRecoversFromCorruptCache(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// Override home directory
+	originalHome := os.Getenv("HOME")
+	t.Cleanup(func() {
+		os.Setenv("HOME", originalHome)
+	})
+	os.Setenv("HOME", tmpDir)
+
+	// Two writes leave the first list in the snapshot
+	good := []model.Repo{{Name: "good-repo", Visibility: model.VisibilityPublic}}
+	if err := cache.WriteRepos(good); err != nil {
+		t.Fatalf("WriteRepos() failed: %v", err)
+	}
+	if err := cache.WriteRepos([]model.Repo{{Name: "newer-repo"}}); err != nil {
+		t.Fatalf("WriteRepos() failed: %v", err)
+	}
+
+	// Simulate a crash mid-write of the primary
+	cachePath := filepath.Join(tmpDir, ".config", "catscan", "cache.json")
+	if err := os.WriteFile(cachePath, []byte(`[{"Name": "half-writ`), 0o644); err != nil {
+		t.Fatalf("Failed to corrupt cache: %v", err)
+	}
+
+	loaded, err := cache.ReadRepos()
+	if err != nil {
+		t.Fatalf("ReadRepos() failed: %v", err)
+	}
+	if len(loaded) != 1 || loaded[0].Name != "good-repo" {
+		t.Errorf("loaded = %v, want [good-repo] from snapshot", loaded)
+	}
+
+	// Without a valid snapshot the parse error is returned
+	if err := os.Remove(filepath.Join(tmpDir, ".config", "catscan", "cache.prev.json")); err != nil {
+		t.Fatalf("Failed to remove snapshot: %v", err)
+	}
+	if _, err := cache.ReadRepos(); err == nil {
+		t.Error("ReadRepos() with corrupt cache and no snapshot returned nil error")
+	}
+}
+
+// TestReadReposLegacyBareArray tests that a pre-versioning cache.json holding
+// a bare array is read and migrated to the envelope in place.
+func TestReadReposLegacyBareArray(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// Override home directory
+	originalHome := os.Getenv("HOME")
+	t.Cleanup(func() {
+		os.Setenv("HOME", originalHome)
+	})
+	os.Setenv("HOME", tmpDir)
+
+	cacheDir := filepath.Join(tmpDir, ".config", "catscan")
+	if err := os.MkdirAll(cacheDir, 0o755); err != nil {
+		t.Fatalf("Failed to create cache dir: %v", err)
+	}
+	cachePath := filepath.Join(cacheDir, "cache.json")
+	legacy := `[{"Name": "legacy-repo", "Visibility": "public", "OpenPRs": 2}]`
+	if err := os.WriteFile(cachePath, []byte(legacy), 0o644); err != nil {
+		t.Fatalf("Failed to write legacy cache: %v", err)
+	}
+
+	repos, err := cache.ReadRepos()
+	if err != nil {
+		t.Fatalf("ReadRepos() failed: %v", err)
+	}
+	if len(repos) != 1 || repos[0].Name != "legacy-repo" || repos[0].OpenPRs != 2 {
+		t.Errorf("repos = %+v, want [legacy-repo with 2 PRs]", repos)
+	}
+
+	// The file now holds the current envelope
+	data, err := os.ReadFile(cachePath)
+	if err != nil {
+		t.Fatalf("Failed to read migrated cache: %v", err)
+	}
+	var env struct {
+		SchemaVersion int          `json:"schemaVersion"`
+		Repos         []model.Repo `json:"repos"`
+	}
+	if err := json.Unmarshal(data, &env); err != nil {
+		t.Fatalf("migrated cache is not an envelope: %v (%s)", err, data)
+	}
+	if env.SchemaVersion != cache.CacheSchemaVersion || len(env.Repos) != 1 {
+		t.Errorf("migrated envelope = version %d with %d repos, want version %d with 1", env.SchemaVersion, len(env.Repos), cache.CacheSchemaVersion)
+	}
+}
+
+// TestReadReposEnvelope tests reading a cache.json in the versioned envelope.
+func TestReadReposEnvelope(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// Override home directory
+	originalHome := os.Getenv("HOME")
+	t.Cleanup(func() {
+		os.Setenv("HOME", originalHome)
+	})
+	os.Setenv("HOME", tmpDir)
+
+	cacheDir := filepath.Join(tmpDir, ".config", "catscan")
+	if err := os.MkdirAll(cacheDir, 0o755); err != nil {
+		t.Fatalf("Failed to create cache dir: %v", err)
+	}
+	envelope := `{"schemaVersion": 1, "repos": [{"Name": "a"}, {"Name": "b"}]}`
+	if err := os.WriteFile(filepath.Join(cacheDir, "cache.json"), []byte(envelope), 0o644); err != nil {
+		t.Fatalf("Failed to write cache: %v", err)
+	}
+
+	repos, err := cache.ReadRepos()
+	if err != nil {
+		t.Fatalf("ReadRepos() failed: %v", err)
+	}
+	if len(repos) != 2 || repos[0].Name != "a" || repos[1].Name != "b" {
+		t.Errorf("repos = %+v, want [a b]", repos)
+	}
+}
+
+// TestReadReposNewerSchema tests that a cache written by a newer schema
+// returns ErrCacheSchemaTooNew rather than being misread or replaced.
+func TestReadReposNewerSchema(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// Override home directory
+	originalHome := os.Getenv("HOME")
+	t.Cleanup(func() {
+		os.Setenv("HOME", originalHome)
+	})
+	os.Setenv("HOME", tmpDir)
+
+	// A valid snapshot must not be used in place of the newer file
+	if err := cache.WriteRepos([]model.Repo{{Name: "old-repo"}}); err != nil {
+		t.Fatalf("WriteRepos() failed: %v", err)
+	}
+	if err := cache.WriteRepos([]model.Repo{{Name: "old-repo"}}); err != nil {
+		t.Fatalf("WriteRepos() failed: %v", err)
+	}
+
+	future := fmt.Sprintf(`{"schemaVersion": %d, "repos": []}`, cache.CacheSchemaVersion+1)
+	cachePath := filepath.Join(tmpDir, ".config", "catscan", "cache.json")
+	if err := os.WriteFile(cachePath, []byte(future), 0o644); err != nil {
+		t.Fatalf("Failed to write cache: %v", err)
+	}
+
+	_, err := cache.ReadRepos()
+	if !errors.Is(err, cache.ErrCacheSchemaTooNew) {
+		t.Fatalf("ReadRepos() error = %v, want ErrCacheSchemaTooNew", err)
+	}
+	if !strings.Contains(err.Error(), fmt.Sprintf("version %d", cache.CacheSchemaVersion+1)) {
+		t.Errorf("error %q does not name the file's version", err)
+	}
+}
+
+// readCacheFile decodes cache.json from disk, bypassing unflushed updates.
+func readCacheFile(t *testing.T, home string) []model.Repo {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(home, ".config", "catscan", "cache.json"))
+	if err != nil {
+		t.Fatalf("Failed to read cache file: %v", err)
+	}
+	var env struct {
+		Repos []model.Repo `json:"repos"`
+	}
+	if err := json.Unmarshal(data, &env); err != nil {
+		t.Fatalf("Failed to parse cache file: %v", err)
+	}
+	return env.Repos
+}
+
+func TestUpdateRepo(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// Override home directory
+	originalHome := os.Getenv("HOME")
+	t.Cleanup(func() {
+		os.Setenv("HOME", originalHome)
+	})
+	os.Setenv("HOME", tmpDir)
+
+	if err := cache.WriteRepos([]model.Repo{{Name: "a"}, {Name: "b"}}); err != nil {
+		t.Fatalf("WriteRepos() failed: %v", err)
+	}
+
+	if err := cache.UpdateRepo(model.Repo{Name: "a", Description: "updated"}); err != nil {
+		t.Fatalf("UpdateRepo() failed: %v", err)
+	}
+	if err := cache.UpdateRepo(model.Repo{Name: "c"}); err != nil {
+		t.Fatalf("UpdateRepo() failed: %v", err)
+	}
+
+	// Updates are visible before the flush
+	repos, err := cache.ReadRepos()
+	if err != nil {
+		t.Fatalf("ReadRepos() failed: %v", err)
+	}
+	if len(repos) != 3 || repos[0].Description != "updated" || repos[2].Name != "c" {
+		t.Errorf("ReadRepos() = %+v, want [a(updated) b c]", repos)
+	}
+
+	if err := cache.Flush(); err != nil {
+		t.Fatalf("Flush() failed: %v", err)
+	}
+	onDisk := readCacheFile(t, tmpDir)
+	if len(onDisk) != 3 || onDisk[0].Description != "updated" || onDisk[2].Name != "c" {
+		t.Errorf("cache.json = %+v, want [a(updated) b c]", onDisk)
+	}
+}
+
+func TestRemoveRepo(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// Override home directory
+	originalHome := os.Getenv("HOME")
+	t.Cleanup(func() {
+		os.Setenv("HOME", originalHome)
+	})
+	os.Setenv("HOME", tmpDir)
+
+	if err := cache.WriteRepos([]model.Repo{{Name: "a"}, {Name: "b"}, {Name: "c"}}); err != nil {
+		t.Fatalf("WriteRepos() failed: %v", err)
+	}
+
+	if err := cache.RemoveRepo("b"); err != nil {
+		t.Fatalf("RemoveRepo() failed: %v", err)
+	}
+	if err := cache.RemoveRepo("missing"); err != nil {
+		t.Fatalf("RemoveRepo() of an uncached repo failed: %v", err)
+	}
+	// The index must follow the shifted positions
+	if err := cache.UpdateRepo(model.Repo{Name: "c", Description: "updated"}); err != nil {
+		t.Fatalf("UpdateRepo() failed: %v", err)
+	}
+	if err := cache.Flush(); err != nil {
+		t.Fatalf("Flush() failed: %v", err)
+	}
+
+	onDisk := readCacheFile(t, tmpDir)
+	if len(onDisk) != 2 || onDisk[0].Name != "a" || onDisk[1].Name != "c" || onDisk[1].Description != "updated" {
+		t.Errorf("cache.json = %+v, want [a c(updated)]", onDisk)
+	}
+}
+
+func TestFlushPreservesUnrelatedRepos(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// Override home directory
+	originalHome := os.Getenv("HOME")
+	t.Cleanup(func() {
+		os.Setenv("HOME", originalHome)
+	})
+	os.Setenv("HOME", tmpDir)
+
+	original := make([]model.Repo, 50)
+	for i := range original {
+		original[i] = model.Repo{
+			Name:        fmt.Sprintf("repo-%02d", i),
+			Description: fmt.Sprintf("description %d", i),
+			Topics:      []string{"go"},
+			OpenPRs:     i,
+		}
+	}
+	if err := cache.WriteRepos(original); err != nil {
+		t.Fatalf("WriteRepos() failed: %v", err)
+	}
+
+	if err := cache.UpdateRepo(model.Repo{Name: "repo-25", Description: "changed"}); err != nil {
+		t.Fatalf("UpdateRepo() failed: %v", err)
+	}
+	if err := cache.Flush(); err != nil {
+		t.Fatalf("Flush() failed: %v", err)
+	}
+
+	onDisk := readCacheFile(t, tmpDir)
+	if len(onDisk) != len(original) {
+		t.Fatalf("cache.json has %d repos, want %d", len(onDisk), len(original))
+	}
+	for i, repo := range onDisk {
+		if i == 25 {
+			if repo.Description != "changed" {
+				t.Errorf("repo-25 Description = %q, want %q", repo.Description, "changed")
+			}
+			continue
+		}
+		want := original[i]
+		if repo.Name != want.Name || repo.Description != want.Description || repo.OpenPRs != want.OpenPRs || len(repo.Topics) != 1 {
+			t.Errorf("repo %d = %+v, want %+v", i, repo, want)
+		}
+	}
+}
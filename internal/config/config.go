@@ -1,6 +1,7 @@
 // Package config handles loading and saving CatScan configuration.
 //
-// The config file is stored at ~/.config/catscan/config.json and contains
+// The config file is stored at $XDG_CONFIG_HOME/catscan/config.json
+// (~/.config/catscan/config.json when XDG_CONFIG_HOME is unset) and contains
 // settings for scan paths, GitHub owner, polling intervals, lifecycle thresholds,
 // and notification preferences.
 package config
@@ -9,8 +10,15 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 )
 
 // NotificationConfig holds per-event-type notification toggles.
@@ -42,9 +50,276 @@ type Config struct {
 	GitHubIntervalSeconds   int                `json:"githubIntervalSeconds"`
 	StaleDays               int                `json:"staleDays"`
 	AbandonedDays           int                `json:"abandonedDays"`
+	HeartbeatSeconds        int                `json:"heartbeatSeconds"`
 	Notifications           NotificationConfig `json:"notifications"`
+
+	// DirtyIsOngoing classifies repos with uncommitted or recently committed
+	// local work as ongoing, regardless of how long since the last push.
+	DirtyIsOngoing bool `json:"dirtyIsOngoing"`
+
+	// ReadOnly disables endpoints that change repositories on GitHub,
+	// such as archiving.
+	ReadOnly bool `json:"readOnly"`
+
+	// MatchLocalByContent matches a local clone whose directory name is not
+	// a GitHub repo by its origin remote or .catscan-repo marker instead.
+	MatchLocalByContent bool `json:"matchLocalByContent"`
+
+	// IncludeAllOrgs also lists the repos of every organization the gh user
+	// is a member of, alongside GitHubOwner's repos.
+	IncludeAllOrgs bool `json:"includeAllOrgs"`
+
+	// AllowNetworkGit enables git operations that contact remotes during
+	// local polls (e.g. detecting unpushed tags). Off by default.
+	AllowNetworkGit bool `json:"allowNetworkGit"`
+
+	// SSEHistorySize is the number of recent events kept so reconnecting
+	// clients can resume via Last-Event-ID. Zero uses the default.
+	SSEHistorySize int `json:"sseHistorySize"`
+
+	// SSEClientBufferSize is the number of events queued per SSE client; a
+	// client that falls this far behind is dropped. Zero uses the default.
+	SSEClientBufferSize int `json:"sseClientBufferSize"`
+
+	// SSEMaxClients caps concurrent SSE connections; further connections
+	// get 503. Zero means no limit.
+	SSEMaxClients int `json:"sseMaxClients"`
+
+	// SortTiebreaker is the secondary sort field used when repos tie on
+	// the requested sort field. Empty means SortTiebreakerName.
+	SortTiebreaker string `json:"sortTiebreaker"`
+
+	// LifecyclePriority orders lifecycles for sort=lifecyclePriority, first
+	// to last. Lifecycles not listed sort after listed ones. Empty uses
+	// DefaultLifecyclePriority.
+	LifecyclePriority []string `json:"lifecyclePriority,omitempty"`
+
+	// TrackedRepos, when non-empty, restricts polling to these repos, each
+	// given as a bare name or owner/name. Empty tracks every repo.
+	TrackedRepos []string `json:"trackedRepos,omitempty"`
+
+	// GitHubFixtureDir, when set, reads GitHub data from JSON fixture files
+	// in this directory instead of calling the gh CLI. It comes from the
+	// -github-fixtures flag and is never read from or saved to the file.
+	GitHubFixtureDir string `json:"-"`
+
+	// CoalesceMillis delays repo list broadcasts by this window and sends
+	// only the latest snapshot. Zero broadcasts every snapshot immediately.
+	CoalesceMillis int `json:"coalesceMillis"`
+
+	// GitHubFailureThreshold is the number of consecutive failed GitHub polls
+	// before health reports GitHub as unhealthy, so a one-off failure doesn't
+	// alarm. Zero uses DefaultGitHubFailureThreshold.
+	GitHubFailureThreshold int `json:"githubFailureThreshold"`
+
+	// NewRepoDays is how many days after creation on GitHub a repo is
+	// flagged as new. Zero uses DefaultNewRepoDays.
+	NewRepoDays int `json:"newRepoDays"`
+
+	// CloneStateIntervalSeconds runs a fast clone-state reconcile (a directory
+	// stat per repo, no git commands) between full local polls. Zero disables it.
+	CloneStateIntervalSeconds int `json:"cloneStateIntervalSeconds"`
+
+	// MaxReposPerScanPath caps how many repos discovery collects from the
+	// scan path, so one pathological directory can't dominate a poll.
+	// Zero means no cap.
+	MaxReposPerScanPath int `json:"maxReposPerScanPath"`
+
+	// StateBackupIntervalMinutes copies state.json into a timestamped backup
+	// on this interval, used to recover if state.json is corrupted.
+	// Zero disables backups.
+	StateBackupIntervalMinutes int `json:"stateBackupIntervalMinutes"`
+
+	// StateBackupKeep is how many state backups to keep.
+	// Zero uses DefaultStateBackupKeep.
+	StateBackupKeep int `json:"stateBackupKeep"`
+
+	// AutoHideAbandonedDays hides uncloned abandoned repos whose last push
+	// is at least this many days old. Hidden repos are left out of the repo
+	// list unless requested. Zero disables auto-hiding.
+	AutoHideAbandonedDays int `json:"autoHideAbandonedDays"`
+
+	// CloneDepth is the default shallow clone depth. Zero clones full history.
+	CloneDepth int `json:"cloneDepth"`
+
+	// CloneProtocol selects the clone URL scheme: "https" (default) or "ssh".
+	CloneProtocol string `json:"cloneProtocol"`
+
+	// NotificationMode selects where notifications are delivered:
+	// "desktop" (default), "webhook", or "both".
+	NotificationMode string `json:"notificationMode"`
+
+	// WebhookURL receives a JSON POST for each notification when
+	// NotificationMode is "webhook" or "both".
+	WebhookURL string `json:"webhookUrl,omitempty"`
+
+	// InstanceName identifies this CatScan instance to clients, e.g. "work"
+	// or "personal", when several run side by side.
+	InstanceName string `json:"instanceName,omitempty"`
+
+	// BindHost is the IP address the server listens on. Empty means
+	// DefaultBindHost, reachable only from this machine.
+	BindHost string `json:"bindHost,omitempty"`
+
+	// AuthToken, when set, is required as "Authorization: Bearer <token>"
+	// on every /api/ request. Empty leaves the API open.
+	AuthToken string `json:"authToken,omitempty"`
+
+	// NotificationLinkTarget selects where clicking a notification leads:
+	// "github" (default) opens the repo on GitHub, "dashboard" opens it in
+	// the local dashboard.
+	NotificationLinkTarget string `json:"notificationLinkTarget"`
+
+	// LogLevel is the minimum level logged: "debug", "info" (default),
+	// "warn", or "error".
+	LogLevel string `json:"logLevel,omitempty"`
+
+	// LogFormat selects the log output: "text" (default) or "json" for
+	// one JSON object per line.
+	LogFormat string `json:"logFormat,omitempty"`
+}
+
+// Log levels.
+const (
+	LogLevelDebug = "debug"
+	LogLevelInfo  = "info"
+	LogLevelWarn  = "warn"
+	LogLevelError = "error"
+)
+
+// IsValidLogLevel returns true if v is an accepted logLevel value.
+// Empty is accepted and means LogLevelInfo.
+func IsValidLogLevel(v string) bool {
+	switch v {
+	case "", LogLevelDebug, LogLevelInfo, LogLevelWarn, LogLevelError:
+		return true
+	default:
+		return false
+	}
 }
 
+// Log formats.
+const (
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+)
+
+// IsValidLogFormat returns true if v is an accepted logFormat value.
+// Empty is accepted and means LogFormatText.
+func IsValidLogFormat(v string) bool {
+	switch v {
+	case "", LogFormatText, LogFormatJSON:
+		return true
+	default:
+		return false
+	}
+}
+
+// Notification link targets.
+const (
+	NotificationLinkGitHub    = "github"
+	NotificationLinkDashboard = "dashboard"
+)
+
+// IsValidNotificationLinkTarget returns true if v is an accepted
+// notificationLinkTarget value. Empty is accepted and means NotificationLinkGitHub.
+func IsValidNotificationLinkTarget(v string) bool {
+	switch v {
+	case "", NotificationLinkGitHub, NotificationLinkDashboard:
+		return true
+	default:
+		return false
+	}
+}
+
+// Notification modes.
+const (
+	NotificationModeDesktop = "desktop"
+	NotificationModeWebhook = "webhook"
+	NotificationModeBoth    = "both"
+)
+
+// IsValidNotificationMode returns true if v is an accepted notificationMode value.
+// Empty is accepted and means NotificationModeDesktop.
+func IsValidNotificationMode(v string) bool {
+	switch v {
+	case "", NotificationModeDesktop, NotificationModeWebhook, NotificationModeBoth:
+		return true
+	default:
+		return false
+	}
+}
+
+// Sort tiebreaker values.
+const (
+	SortTiebreakerName       = "name"
+	SortTiebreakerLastUpdate = "lastUpdate"
+)
+
+// IsValidSortTiebreaker returns true if v is an accepted sortTiebreaker value.
+// Empty is accepted and means the default.
+func IsValidSortTiebreaker(v string) bool {
+	switch v {
+	case "", SortTiebreakerName, SortTiebreakerLastUpdate:
+		return true
+	default:
+		return false
+	}
+}
+
+// DefaultLifecyclePriority returns the lifecycle order used by
+// sort=lifecyclePriority when none is configured: lifecycles that need
+// attention first.
+func DefaultLifecyclePriority() []string {
+	return []string{"abandoned", "stale", "ongoing", "maintenance", "archived"}
+}
+
+// IsTracked reports whether TrackedRepos includes the repo, comparing names
+// case-insensitively. An empty owner means the owner is not known yet, so
+// only the name part of owner/name entries is compared.
+func (c *Config) IsTracked(owner, name string) bool {
+	if len(c.TrackedRepos) == 0 {
+		return true
+	}
+	for _, tracked := range c.TrackedRepos {
+		trackedOwner, trackedName, qualified := strings.Cut(tracked, "/")
+		if !qualified {
+			trackedName = trackedOwner
+		}
+		if !strings.EqualFold(trackedName, name) {
+			continue
+		}
+		if !qualified || owner == "" || strings.EqualFold(trackedOwner, owner) {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultBindHost is the loopback address the server listens on when no
+// BindHost is configured.
+const DefaultBindHost = "127.0.0.1"
+
+// DefaultHeartbeatSeconds is the SSE heartbeat interval used when none is configured.
+const DefaultHeartbeatSeconds = 30
+
+// DefaultGitHubFailureThreshold is the GitHub failure grace used when none is configured.
+const DefaultGitHubFailureThreshold = 3
+
+// DefaultNewRepoDays is the new-repo window used when none is configured.
+const DefaultNewRepoDays = 7
+
+// DefaultStateBackupKeep is the number of state backups kept when none is configured.
+const DefaultStateBackupKeep = 5
+
+// Minimum intervals accepted by config validation.
+const (
+	MinLocalIntervalSeconds  = 10
+	MinGitHubIntervalSeconds = 60
+	MinHeartbeatSeconds      = 5
+	MinCloneStateSeconds     = 2
+)
+
 // DefaultConfig returns a Config with sensible defaults.
 func DefaultConfig() (Config, error) {
 	homeDir, err := os.UserHomeDir()
@@ -53,19 +328,49 @@ func DefaultConfig() (Config, error) {
 	}
 
 	return Config{
-		ScanPath:              filepath.Join(homeDir, "REPOS", "alexcatdad"),
-		GitHubOwner:           "alexcatdad",
-		Port:                  7700,
-		LocalIntervalSeconds:  60,
-		GitHubIntervalSeconds: 300,
-		StaleDays:             30,
-		AbandonedDays:         90,
-		Notifications:         DefaultNotificationConfig(),
+		ScanPath:                   filepath.Join(homeDir, "REPOS", "alexcatdad"),
+		GitHubOwner:                "alexcatdad",
+		BindHost:                   DefaultBindHost,
+		Port:                       7700,
+		LocalIntervalSeconds:       60,
+		GitHubIntervalSeconds:      300,
+		StaleDays:                  30,
+		AbandonedDays:              90,
+		HeartbeatSeconds:           DefaultHeartbeatSeconds,
+		NewRepoDays:                DefaultNewRepoDays,
+		StateBackupIntervalMinutes: 60,
+		StateBackupKeep:            DefaultStateBackupKeep,
+		Notifications:              DefaultNotificationConfig(),
+		SSEHistorySize:             100,
+		SortTiebreaker:             SortTiebreakerName,
+		CloneProtocol:              "https",
+		NotificationMode:           NotificationModeDesktop,
+		NotificationLinkTarget:     NotificationLinkGitHub,
 	}, nil
 }
 
-// configDir returns the CatScan config directory path.
-func configDir() (string, error) {
+var (
+	pathOverride string
+	pathMu       sync.RWMutex
+)
+
+// SetPath makes Load, Save, and Watch use the config file at path instead
+// of config.json in Dir. An empty path restores the default. The cache and
+// state files stay in Dir.
+func SetPath(path string) {
+	pathMu.Lock()
+	defer pathMu.Unlock()
+	pathOverride = path
+}
+
+// Dir returns the CatScan config directory: catscan under $XDG_CONFIG_HOME,
+// or ~/.config/catscan when it is unset. Following the XDG spec, a relative
+// XDG_CONFIG_HOME is ignored. The cache and state files live here too.
+func Dir() (string, error) {
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" && filepath.IsAbs(xdg) {
+		return filepath.Join(xdg, "catscan"), nil
+	}
+
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		return "", fmt.Errorf("getting home directory: %w", err)
@@ -77,7 +382,14 @@ func configDir() (string, error) {
 
 // configPath returns the full path to the config file.
 func configPath() (string, error) {
-	dir, err := configDir()
+	pathMu.RLock()
+	override := pathOverride
+	pathMu.RUnlock()
+	if override != "" {
+		return override, nil
+	}
+
+	dir, err := Dir()
 	if err != nil {
 		return "", err
 	}
@@ -86,10 +398,11 @@ func configPath() (string, error) {
 
 // ensureConfigDir creates the config directory if it doesn't exist.
 func ensureConfigDir() error {
-	dir, err := configDir()
+	cfgPath, err := configPath()
 	if err != nil {
 		return err
 	}
+	dir := filepath.Dir(cfgPath)
 
 	// Check if directory exists
 	info, err := os.Stat(dir)
@@ -134,41 +447,186 @@ func expandTilde(path string) (string, error) {
 	return filepath.Join(homeDir, path[2:]), nil
 }
 
-// Load loads the config from ~/.config/catscan/config.json.
-// If the file doesn't exist, returns default config.
+// Load loads the config from the config file (see SetPath and Dir).
+// If the file doesn't exist, returns default config. Environment overrides
+// (see envOverrides) are applied last, so precedence is env, then file,
+// then defaults. An invalid config is logged as a warning and still returned;
+// callers that must reject it call Validate.
 func Load() (Config, error) {
 	cfgPath, err := configPath()
 	if err != nil {
 		return Config{}, err
 	}
 
-	// Try to read the file
+	cfg, err := load(cfgPath)
+	if err != nil {
+		return Config{}, err
+	}
+
+	// Report problems without failing, so the CLI can still start and the
+	// config can be fixed from the dashboard
+	if err := Validate(&cfg); err != nil {
+		slog.Warn("config file has problems", "path", cfgPath, "err", err)
+	}
+
+	return cfg, nil
+}
+
+// Check loads the config file as Load does and returns its first problem,
+// for the -validate flag. Unlike Load, a missing file is an error rather
+// than the defaults, and nothing is logged.
+func Check() error {
+	cfgPath, err := configPath()
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(cfgPath); err != nil {
+		return fmt.Errorf("reading config file: %w", err)
+	}
+
+	cfg, err := load(cfgPath)
+	if err != nil {
+		return err
+	}
+	return Validate(&cfg)
+}
+
+// load reads the config file at cfgPath and applies environment overrides
+// and tilde expansion, without validating the result.
+func load(cfgPath string) (Config, error) {
+	cfg, err := loadFile(cfgPath)
+	if err != nil {
+		return Config{}, err
+	}
+
+	if err := applyEnvOverrides(&cfg); err != nil {
+		return Config{}, err
+	}
+
+	// Expand tilde in scan path
+	cfg.ScanPath, err = expandTilde(cfg.ScanPath)
+	if err != nil {
+		return Config{}, fmt.Errorf("expanding tilde in scanPath: %w", err)
+	}
+	return cfg, nil
+}
+
+// loadFile reads the config file at cfgPath without environment overrides,
+// returning the defaults when it doesn't exist.
+func loadFile(cfgPath string) (Config, error) {
 	data, err := os.ReadFile(cfgPath)
 	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			// File doesn't exist, return defaults
-			return DefaultConfig()
+		if !errors.Is(err, os.ErrNotExist) {
+			return Config{}, fmt.Errorf("reading config file: %w", err)
 		}
-		return Config{}, fmt.Errorf("reading config file: %w", err)
+		// File doesn't exist, start from defaults
+		return DefaultConfig()
 	}
 
-	// Parse JSON
 	var cfg Config
 	if err := json.Unmarshal(data, &cfg); err != nil {
 		return Config{}, fmt.Errorf("parsing config JSON: %w", err)
 	}
+	return cfg, nil
+}
 
-	// Expand tilde in scan path
-	cfg.ScanPath, err = expandTilde(cfg.ScanPath)
+// envOverrides lists the environment variables that override config
+// fields, each with a function returning a pointer to its field (a *string,
+// *int, or *bool).
+var envOverrides = []struct {
+	name  string
+	field func(cfg *Config) any
+}{
+	{"CATSCAN_SCAN_PATH", func(cfg *Config) any { return &cfg.ScanPath }},
+	{"CATSCAN_GITHUB_OWNER", func(cfg *Config) any { return &cfg.GitHubOwner }},
+	{"CATSCAN_BIND_HOST", func(cfg *Config) any { return &cfg.BindHost }},
+	{"CATSCAN_PORT", func(cfg *Config) any { return &cfg.Port }},
+	{"CATSCAN_LOCAL_INTERVAL_SECONDS", func(cfg *Config) any { return &cfg.LocalIntervalSeconds }},
+	{"CATSCAN_GITHUB_INTERVAL_SECONDS", func(cfg *Config) any { return &cfg.GitHubIntervalSeconds }},
+	{"CATSCAN_READ_ONLY", func(cfg *Config) any { return &cfg.ReadOnly }},
+	{"CATSCAN_AUTH_TOKEN", func(cfg *Config) any { return &cfg.AuthToken }},
+	{"CATSCAN_INSTANCE_NAME", func(cfg *Config) any { return &cfg.InstanceName }},
+	{"CATSCAN_LOG_LEVEL", func(cfg *Config) any { return &cfg.LogLevel }},
+	{"CATSCAN_LOG_FORMAT", func(cfg *Config) any { return &cfg.LogFormat }},
+}
+
+// applyEnvOverrides sets the config fields whose environment variables are
+// set and non-empty. Unset variables leave the field as loaded.
+func applyEnvOverrides(cfg *Config) error {
+	for _, override := range envOverrides {
+		value := os.Getenv(override.name)
+		if value == "" {
+			continue
+		}
+
+		var err error
+		switch field := override.field(cfg).(type) {
+		case *string:
+			*field = value
+		case *int:
+			err = parseEnvInt(value, field)
+		case *bool:
+			err = parseEnvBool(value, field)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", override.name, err)
+		}
+	}
+	return nil
+}
+
+// restoreEnvOverrides resets the fields whose environment variables are set
+// to their values in the config file at cfgPath, so values from the
+// environment are never saved. The file is only read when a variable is set.
+func restoreEnvOverrides(cfg *Config, cfgPath string) error {
+	var file *Config
+	for _, override := range envOverrides {
+		if os.Getenv(override.name) == "" {
+			continue
+		}
+		if file == nil {
+			loaded, err := loadFile(cfgPath)
+			if err != nil {
+				return err
+			}
+			file = &loaded
+		}
+		switch field := override.field(cfg).(type) {
+		case *string:
+			*field = *override.field(file).(*string)
+		case *int:
+			*field = *override.field(file).(*int)
+		case *bool:
+			*field = *override.field(file).(*bool)
+		}
+	}
+	return nil
+}
+
+// parseEnvInt parses an integer environment override into dst.
+func parseEnvInt(value string, dst *int) error {
+	n, err := strconv.Atoi(value)
 	if err != nil {
-		return Config{}, fmt.Errorf("expanding tilde in scanPath: %w", err)
+		return fmt.Errorf("invalid integer %q", value)
 	}
+	*dst = n
+	return nil
+}
 
-	return cfg, nil
+// parseEnvBool parses a boolean environment override ("true", "1", ...) into dst.
+func parseEnvBool(value string, dst *bool) error {
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return fmt.Errorf("invalid boolean %q", value)
+	}
+	*dst = b
+	return nil
 }
 
-// Save saves the config to ~/.config/catscan/config.json.
-// The config directory is created if it doesn't exist.
+// Save saves the config to the config file (see SetPath and Dir).
+// The config directory is created if it doesn't exist. Fields overridden
+// from the environment keep their file values (or defaults, without a
+// file), so environment values stay out of the file.
 func Save(cfg Config) error {
 	if err := ensureConfigDir(); err != nil {
 		return err
@@ -179,15 +637,22 @@ func Save(cfg Config) error {
 		return err
 	}
 
+	if err := restoreEnvOverrides(&cfg, cfgPath); err != nil {
+		return err
+	}
+
 	// Marshal to JSON with indentation
 	data, err := json.MarshalIndent(cfg, "", "  ")
 	if err != nil {
 		return fmt.Errorf("marshaling config JSON: %w", err)
 	}
 
-	// Write atomically: write to temp file, then rename
+	// Write atomically: write to temp file, then rename. The file can hold
+	// authToken, so only the owner may read it; a stale temp file is removed
+	// first since WriteFile keeps an existing file's mode.
 	tmpPath := cfgPath + ".tmp"
-	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
+	_ = os.Remove(tmpPath)
+	if err := os.WriteFile(tmpPath, data, 0o600); err != nil {
 		return fmt.Errorf("writing config temp file: %w", err)
 	}
 
@@ -200,3 +665,110 @@ func Save(cfg Config) error {
 
 	return nil
 }
+
+// Validate checks cfg for values the server can't run with and returns
+// the first problem found, or nil if cfg is valid.
+func Validate(cfg *Config) error {
+	if cfg.ScanPath == "" {
+		return fmt.Errorf("scanPath cannot be empty")
+	}
+	if cfg.Port < 1024 || cfg.Port > 65535 {
+		return fmt.Errorf("port must be between 1024 and 65535")
+	}
+	if cfg.BindHost != "" && cfg.BindHost != "localhost" && net.ParseIP(cfg.BindHost) == nil {
+		return fmt.Errorf("bindHost must be an IP address or localhost (got %q)", cfg.BindHost)
+	}
+	if cfg.LocalIntervalSeconds < MinLocalIntervalSeconds {
+		return fmt.Errorf("localIntervalSeconds must be at least %d (got %d)", MinLocalIntervalSeconds, cfg.LocalIntervalSeconds)
+	}
+	if cfg.GitHubIntervalSeconds < MinGitHubIntervalSeconds {
+		return fmt.Errorf("githubIntervalSeconds must be at least %d (got %d)", MinGitHubIntervalSeconds, cfg.GitHubIntervalSeconds)
+	}
+	if cfg.HeartbeatSeconds != 0 && cfg.HeartbeatSeconds < MinHeartbeatSeconds {
+		return fmt.Errorf("heartbeatSeconds must be at least %d (got %d)", MinHeartbeatSeconds, cfg.HeartbeatSeconds)
+	}
+	if cfg.CloneStateIntervalSeconds != 0 && cfg.CloneStateIntervalSeconds < MinCloneStateSeconds {
+		return fmt.Errorf("cloneStateIntervalSeconds must be 0 or at least %d (got %d)", MinCloneStateSeconds, cfg.CloneStateIntervalSeconds)
+	}
+	if cfg.GitHubFailureThreshold < 0 {
+		return fmt.Errorf("githubFailureThreshold cannot be negative")
+	}
+	if cfg.NewRepoDays < 0 {
+		return fmt.Errorf("newRepoDays cannot be negative")
+	}
+	if cfg.StateBackupIntervalMinutes < 0 {
+		return fmt.Errorf("stateBackupIntervalMinutes cannot be negative")
+	}
+	if cfg.StateBackupKeep < 0 {
+		return fmt.Errorf("stateBackupKeep cannot be negative")
+	}
+	if cfg.AutoHideAbandonedDays < 0 {
+		return fmt.Errorf("autoHideAbandonedDays cannot be negative")
+	}
+	if cfg.CloneDepth < 0 {
+		return fmt.Errorf("cloneDepth cannot be negative")
+	}
+	if cfg.CloneProtocol != "" && cfg.CloneProtocol != "https" && cfg.CloneProtocol != "ssh" {
+		return fmt.Errorf("cloneProtocol must be https or ssh")
+	}
+	if cfg.MaxReposPerScanPath < 0 {
+		return fmt.Errorf("maxReposPerScanPath cannot be negative")
+	}
+	if cfg.CoalesceMillis < 0 {
+		return fmt.Errorf("coalesceMillis cannot be negative")
+	}
+	if cfg.SSEClientBufferSize < 0 {
+		return fmt.Errorf("sseClientBufferSize cannot be negative")
+	}
+	if cfg.SSEMaxClients < 0 {
+		return fmt.Errorf("sseMaxClients cannot be negative")
+	}
+	if cfg.StaleDays < 1 {
+		return fmt.Errorf("staleDays must be at least 1")
+	}
+	if cfg.AbandonedDays < 1 {
+		return fmt.Errorf("abandonedDays must be at least 1")
+	}
+	if cfg.StaleDays >= cfg.AbandonedDays {
+		return fmt.Errorf("staleDays must be less than abandonedDays")
+	}
+	seenLifecycles := make(map[string]bool, len(cfg.LifecyclePriority))
+	for _, lc := range cfg.LifecyclePriority {
+		// The default order lists every lifecycle
+		if !slices.Contains(DefaultLifecyclePriority(), lc) {
+			return fmt.Errorf("lifecyclePriority contains unknown lifecycle %q", lc)
+		}
+		if seenLifecycles[lc] {
+			return fmt.Errorf("lifecyclePriority lists %q more than once", lc)
+		}
+		seenLifecycles[lc] = true
+	}
+	for _, tracked := range cfg.TrackedRepos {
+		owner, name, qualified := strings.Cut(tracked, "/")
+		if strings.TrimSpace(tracked) == "" || (qualified && (owner == "" || name == "" || strings.Contains(name, "/"))) {
+			return fmt.Errorf("trackedRepos entry %q must be a repo name or owner/name", tracked)
+		}
+	}
+	if !IsValidSortTiebreaker(cfg.SortTiebreaker) {
+		return fmt.Errorf("sortTiebreaker must be one of name, lastUpdate")
+	}
+	if !IsValidNotificationMode(cfg.NotificationMode) {
+		return fmt.Errorf("notificationMode must be one of desktop, webhook, both")
+	}
+	if !IsValidNotificationLinkTarget(cfg.NotificationLinkTarget) {
+		return fmt.Errorf("notificationLinkTarget must be one of github, dashboard")
+	}
+	if !IsValidLogLevel(cfg.LogLevel) {
+		return fmt.Errorf("logLevel must be one of debug, info, warn, error")
+	}
+	if !IsValidLogFormat(cfg.LogFormat) {
+		return fmt.Errorf("logFormat must be one of text, json")
+	}
+	if cfg.NotificationMode == NotificationModeWebhook || cfg.NotificationMode == NotificationModeBoth {
+		u, err := url.Parse(cfg.WebhookURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("webhookUrl must be an http or https URL when notificationMode is %s", cfg.NotificationMode)
+		}
+	}
+	return nil
+}
@@ -2,13 +2,22 @@ package config_test
 
 import (
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/alexcatdad/catscan/internal/config"
 )
 
+// TestMain clears XDG_CONFIG_HOME so tests that point HOME at a temp
+// directory never touch the real config directory.
+func TestMain(m *testing.M) {
+	os.Unsetenv("XDG_CONFIG_HOME")
+	os.Exit(m.Run())
+}
+
 // TestLoadReturnsDefaultsWhenFileDoesntExist tests that Load returns
 // default config when the config file doesn't exist.
 func TestLoadReturnsDefaultsWhenFileDoesntExist(t *testing.T) {
@@ -121,6 +130,69 @@ func TestLoadAndSaveRoundTrip(t *testing.T) {
 	}
 }
 
+// TestSaveOmitsFixtureDir tests that the -github-fixtures dir is never
+// written to the config file, so a dashboard save can't make later starts
+// serve fixture data.
+func TestSaveOmitsFixtureDir(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg, err := config.DefaultConfig()
+	if err != nil {
+		t.Fatalf("DefaultConfig() failed: %v", err)
+	}
+	cfg.GitHubFixtureDir = "/fixtures/github"
+	if err := config.Save(cfg); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	dir, err := config.Dir()
+	if err != nil {
+		t.Fatalf("Dir() failed: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "config.json"))
+	if err != nil {
+		t.Fatalf("reading config file: %v", err)
+	}
+	if strings.Contains(string(data), "fixtures") {
+		t.Errorf("config file contains the fixture dir:\n%s", data)
+	}
+
+	loaded, err := config.Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if loaded.GitHubFixtureDir != "" {
+		t.Errorf("GitHubFixtureDir = %q, want empty", loaded.GitHubFixtureDir)
+	}
+}
+
+// TestSaveIsOwnerOnly tests that the config file, which can hold the auth
+// token, is readable only by its owner.
+func TestSaveIsOwnerOnly(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg, err := config.DefaultConfig()
+	if err != nil {
+		t.Fatalf("DefaultConfig() failed: %v", err)
+	}
+	cfg.AuthToken = "s3cret"
+	if err := config.Save(cfg); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	dir, err := config.Dir()
+	if err != nil {
+		t.Fatalf("Dir() failed: %v", err)
+	}
+	info, err := os.Stat(filepath.Join(dir, "config.json"))
+	if err != nil {
+		t.Fatalf("Stat failed: %v", err)
+	}
+	if mode := info.Mode().Perm(); mode != 0o600 {
+		t.Errorf("config file mode = %o, want 600", mode)
+	}
+}
+
 // TestLoadFromValidFile tests loading from a valid config file.
 func TestLoadFromValidFile(t *testing.T) {
 	tmpDir := t.TempDir()
@@ -206,3 +278,529 @@ func TestExpandTilde(t *testing.T) {
 		// Covered by other tests
 	})
 }
+
+// TestIsTracked tests TrackedRepos matching by bare name and owner/name.
+func TestIsTracked(t *testing.T) {
+	cfg := config.Config{TrackedRepos: []string{"catscan", "org-a/Service"}}
+
+	tests := []struct {
+		owner, name string
+		want        bool
+	}{
+		{"alexcatdad", "catscan", true},
+		{"org-a", "catscan", true},
+		{"org-a", "service", true},
+		{"org-b", "service", false},
+		{"", "service", true},
+		{"alexcatdad", "dotfiles", false},
+		{"", "dotfiles", false},
+	}
+	for _, tt := range tests {
+		if got := cfg.IsTracked(tt.owner, tt.name); got != tt.want {
+			t.Errorf("IsTracked(%q, %q) = %v, want %v", tt.owner, tt.name, got, tt.want)
+		}
+	}
+
+	// An empty allowlist tracks everything
+	if !(&config.Config{}).IsTracked("anyone", "anything") {
+		t.Error("empty TrackedRepos should track every repo")
+	}
+}
+
+// TestDirHonorsXDGConfigHome tests that the config directory follows
+// XDG_CONFIG_HOME when it is set to an absolute path and falls back to
+// ~/.config otherwise.
+func TestDirHonorsXDGConfigHome(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	tests := []struct {
+		name string
+		xdg  string
+		want string
+	}{
+		{name: "unset", xdg: "", want: filepath.Join(home, ".config", "catscan")},
+		{name: "absolute", xdg: "/srv/conf", want: filepath.Join("/srv/conf", "catscan")},
+		{name: "relative ignored", xdg: "conf", want: filepath.Join(home, ".config", "catscan")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("XDG_CONFIG_HOME", tt.xdg)
+			got, err := config.Dir()
+			if err != nil {
+				t.Fatalf("Dir() failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Dir() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	// Save writes the config file under XDG_CONFIG_HOME
+	xdg := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+	cfg, err := config.DefaultConfig()
+	if err != nil {
+		t.Fatalf("DefaultConfig() failed: %v", err)
+	}
+	if err := config.Save(cfg); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(xdg, "catscan", "config.json")); err != nil {
+		t.Errorf("config.json not written under XDG_CONFIG_HOME: %v", err)
+	}
+}
+
+// TestSetPathLoadsCustomFile tests that SetPath points Load and Save at a
+// custom file, leaving the default location untouched, and that an empty
+// path restores the default.
+func TestSetPathLoadsCustomFile(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	defer config.SetPath("")
+
+	custom := filepath.Join(t.TempDir(), "profiles", "work.json")
+	if err := os.MkdirAll(filepath.Dir(custom), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(custom, []byte(`{"githubOwner": "work-org", "port": 7800}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	config.SetPath(custom)
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if cfg.GitHubOwner != "work-org" || cfg.Port != 7800 {
+		t.Errorf("Load() = owner %q port %d, want work-org 7800", cfg.GitHubOwner, cfg.Port)
+	}
+
+	cfg.Port = 7801
+	if err := config.Save(cfg); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+	if reloaded, err := config.Load(); err != nil || reloaded.Port != 7801 {
+		t.Errorf("Load() after Save() = port %d, err %v, want 7801", reloaded.Port, err)
+	}
+	if _, err := os.Stat(filepath.Join(home, ".config", "catscan", "config.json")); !os.IsNotExist(err) {
+		t.Errorf("default config.json was written: %v", err)
+	}
+
+	config.SetPath("")
+	cfg, err = config.Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if cfg.GitHubOwner != "alexcatdad" {
+		t.Errorf("after SetPath(\"\"): GitHubOwner = %q, want the default", cfg.GitHubOwner)
+	}
+}
+
+// TestEnvOverrides tests that CATSCAN_* environment variables win over the
+// config file and that unset variables leave the file values intact.
+func TestEnvOverrides(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	file := config.Config{
+		ScanPath:              "/file/path",
+		GitHubOwner:           "file-owner",
+		BindHost:              "127.0.0.1",
+		Port:                  7700,
+		LocalIntervalSeconds:  60,
+		GitHubIntervalSeconds: 300,
+		InstanceName:          "file",
+		LogLevel:              config.LogLevelInfo,
+	}
+	if err := config.Save(file); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	tests := []struct {
+		env   string
+		value string
+		got   func(cfg config.Config) any
+		want  any
+	}{
+		{"CATSCAN_SCAN_PATH", "/env/path", func(c config.Config) any { return c.ScanPath }, "/env/path"},
+		{"CATSCAN_GITHUB_OWNER", "env-owner", func(c config.Config) any { return c.GitHubOwner }, "env-owner"},
+		{"CATSCAN_BIND_HOST", "0.0.0.0", func(c config.Config) any { return c.BindHost }, "0.0.0.0"},
+		{"CATSCAN_PORT", "9000", func(c config.Config) any { return c.Port }, 9000},
+		{"CATSCAN_LOCAL_INTERVAL_SECONDS", "15", func(c config.Config) any { return c.LocalIntervalSeconds }, 15},
+		{"CATSCAN_GITHUB_INTERVAL_SECONDS", "900", func(c config.Config) any { return c.GitHubIntervalSeconds }, 900},
+		{"CATSCAN_READ_ONLY", "true", func(c config.Config) any { return c.ReadOnly }, true},
+		{"CATSCAN_AUTH_TOKEN", "s3cret", func(c config.Config) any { return c.AuthToken }, "s3cret"},
+		{"CATSCAN_INSTANCE_NAME", "env", func(c config.Config) any { return c.InstanceName }, "env"},
+		{"CATSCAN_LOG_LEVEL", "debug", func(c config.Config) any { return c.LogLevel }, "debug"},
+		{"CATSCAN_LOG_FORMAT", "json", func(c config.Config) any { return c.LogFormat }, "json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.env, func(t *testing.T) {
+			t.Setenv(tt.env, tt.value)
+			cfg, err := config.Load()
+			if err != nil {
+				t.Fatalf("Load() failed: %v", err)
+			}
+			if got := tt.got(cfg); got != tt.want {
+				t.Errorf("%s=%s: got %v, want %v", tt.env, tt.value, got, tt.want)
+			}
+		})
+	}
+
+	t.Run("unset", func(t *testing.T) {
+		cfg, err := config.Load()
+		if err != nil {
+			t.Fatalf("Load() failed: %v", err)
+		}
+		if cfg.ScanPath != file.ScanPath || cfg.GitHubOwner != file.GitHubOwner || cfg.Port != file.Port || cfg.InstanceName != file.InstanceName {
+			t.Errorf("Load() = %+v, want the file values", cfg)
+		}
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		t.Setenv("CATSCAN_PORT", "seventy")
+		if _, err := config.Load(); err == nil || !strings.Contains(err.Error(), "CATSCAN_PORT") {
+			t.Errorf("Load() error = %v, want an error naming CATSCAN_PORT", err)
+		}
+	})
+
+	t.Run("without a config file", func(t *testing.T) {
+		t.Setenv("HOME", t.TempDir())
+		t.Setenv("CATSCAN_PORT", "9100")
+		cfg, err := config.Load()
+		if err != nil {
+			t.Fatalf("Load() failed: %v", err)
+		}
+		if cfg.Port != 9100 || cfg.GitHubOwner != "alexcatdad" {
+			t.Errorf("Load() = port %d owner %q, want 9100 over the defaults", cfg.Port, cfg.GitHubOwner)
+		}
+	})
+}
+
+// TestSaveKeepsEnvOverridesOut tests that saving a loaded config writes the
+// file values of env-overridden fields, not the environment values.
+func TestSaveKeepsEnvOverridesOut(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	file, err := config.DefaultConfig()
+	if err != nil {
+		t.Fatalf("DefaultConfig() failed: %v", err)
+	}
+	file.Port = 7700
+	if err := config.Save(file); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	t.Setenv("CATSCAN_PORT", "9000")
+	t.Setenv("CATSCAN_AUTH_TOKEN", "s3cret")
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	cfg.StaleDays = 45
+	if err := config.Save(cfg); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	dir, err := config.Dir()
+	if err != nil {
+		t.Fatalf("Dir() failed: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "config.json"))
+	if err != nil {
+		t.Fatalf("reading config file: %v", err)
+	}
+	var saved config.Config
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatalf("parsing config file: %v", err)
+	}
+	if saved.Port != 7700 || saved.AuthToken != "" {
+		t.Errorf("saved port %d token %q, want the file's 7700 and no token", saved.Port, saved.AuthToken)
+	}
+	if saved.StaleDays != 45 {
+		t.Errorf("saved StaleDays = %d, want the edited 45", saved.StaleDays)
+	}
+}
+
+// TestValidate tests config validation.
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         config.Config
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name: "valid config",
+			cfg: config.Config{
+				ScanPath:              "/tmp/test",
+				Port:                  8080,
+				LocalIntervalSeconds:  30,
+				GitHubIntervalSeconds: 300,
+				StaleDays:             30,
+				AbandonedDays:         90,
+			},
+			wantErr: false,
+		},
+		{
+			name: "empty scan path",
+			cfg: config.Config{
+				ScanPath:              "",
+				Port:                  8080,
+				LocalIntervalSeconds:  30,
+				GitHubIntervalSeconds: 300,
+				StaleDays:             30,
+				AbandonedDays:         90,
+			},
+			wantErr:     true,
+			errContains: "scanPath",
+		},
+		{
+			name: "port too low",
+			cfg: config.Config{
+				ScanPath:              "/tmp/test",
+				Port:                  80,
+				LocalIntervalSeconds:  30,
+				GitHubIntervalSeconds: 300,
+				StaleDays:             30,
+				AbandonedDays:         90,
+			},
+			wantErr:     true,
+			errContains: "port",
+		},
+		{
+			name: "local interval too low",
+			cfg: config.Config{
+				ScanPath:              "/tmp/test",
+				Port:                  8080,
+				LocalIntervalSeconds:  5,
+				GitHubIntervalSeconds: 300,
+				StaleDays:             30,
+				AbandonedDays:         90,
+			},
+			wantErr:     true,
+			errContains: "localIntervalSeconds",
+		},
+		{
+			name: "GitHub interval too low",
+			cfg: config.Config{
+				ScanPath:              "/tmp/test",
+				Port:                  8080,
+				LocalIntervalSeconds:  30,
+				GitHubIntervalSeconds: 30,
+				StaleDays:             30,
+				AbandonedDays:         90,
+			},
+			wantErr:     true,
+			errContains: "githubIntervalSeconds",
+		},
+		{
+			name: "heartbeat too low",
+			cfg: config.Config{
+				ScanPath:              "/tmp/test",
+				Port:                  8080,
+				LocalIntervalSeconds:  30,
+				GitHubIntervalSeconds: 300,
+				HeartbeatSeconds:      2,
+				StaleDays:             30,
+				AbandonedDays:         90,
+			},
+			wantErr:     true,
+			errContains: "heartbeatSeconds",
+		},
+		{
+			name: "stale >= abandoned",
+			cfg: config.Config{
+				ScanPath:              "/tmp/test",
+				Port:                  8080,
+				LocalIntervalSeconds:  30,
+				GitHubIntervalSeconds: 300,
+				StaleDays:             90,
+				AbandonedDays:         90,
+			},
+			wantErr:     true,
+			errContains: "staleDays",
+		},
+		{
+			name: "unknown lifecycle in priority",
+			cfg: config.Config{
+				ScanPath:              "/tmp/test",
+				Port:                  8080,
+				LocalIntervalSeconds:  30,
+				GitHubIntervalSeconds: 300,
+				StaleDays:             30,
+				AbandonedDays:         90,
+				LifecyclePriority:     []string{"stale", "dormant"},
+			},
+			wantErr:     true,
+			errContains: "lifecyclePriority",
+		},
+		{
+			name: "malformed tracked repo",
+			cfg: config.Config{
+				ScanPath:              "/tmp/test",
+				Port:                  8080,
+				LocalIntervalSeconds:  30,
+				GitHubIntervalSeconds: 300,
+				StaleDays:             30,
+				AbandonedDays:         90,
+				TrackedRepos:          []string{"catscan", "org/"},
+			},
+			wantErr:     true,
+			errContains: "trackedRepos",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := config.Validate(&tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil && tt.errContains != "" {
+				if !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("error = %v, want contain %s", err, tt.errContains)
+				}
+			}
+		})
+	}
+}
+
+// TestValidateLoadedFile tests the -validate path: a config file with a bad
+// value loads but fails validation with an error naming the field.
+func TestValidateLoadedFile(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	defer config.SetPath("")
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	bad := `{"scanPath": "/tmp/repos", "port": 80, "localIntervalSeconds": 60, "githubIntervalSeconds": 300, "staleDays": 30, "abandonedDays": 90}`
+	if err := os.WriteFile(path, []byte(bad), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	config.SetPath(path)
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if err := config.Validate(&cfg); err == nil || !strings.Contains(err.Error(), "port") {
+		t.Errorf("Validate() = %v, want a port error", err)
+	}
+
+	cfg.Port = 7700
+	if err := config.Validate(&cfg); err != nil {
+		t.Errorf("Validate() after fixing port = %v, want nil", err)
+	}
+}
+
+// TestCheck tests that Check reports a missing file and a bad value as
+// errors, and accepts a valid file.
+func TestCheck(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	defer config.SetPath("")
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	config.SetPath(path)
+	if err := config.Check(); err == nil || !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Check() with no file = %v, want a not-exist error", err)
+	}
+
+	bad := `{"scanPath": "/tmp/repos", "port": 80, "localIntervalSeconds": 60, "githubIntervalSeconds": 300, "staleDays": 30, "abandonedDays": 90}`
+	if err := os.WriteFile(path, []byte(bad), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.Check(); err == nil || !strings.Contains(err.Error(), "port") {
+		t.Errorf("Check() = %v, want a port error", err)
+	}
+
+	good := strings.Replace(bad, `"port": 80`, `"port": 7700`, 1)
+	if err := os.WriteFile(path, []byte(good), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.Check(); err != nil {
+		t.Errorf("Check() = %v, want nil", err)
+	}
+}
+
+// TestValidateRules tests each validation rule by breaking one field of an
+// otherwise valid config.
+func TestValidateRules(t *testing.T) {
+	valid := func() config.Config {
+		return config.Config{
+			ScanPath:              "/tmp/repos",
+			Port:                  7700,
+			LocalIntervalSeconds:  60,
+			GitHubIntervalSeconds: 300,
+			StaleDays:             30,
+			AbandonedDays:         90,
+		}
+	}
+	if cfg := valid(); config.Validate(&cfg) != nil {
+		t.Fatalf("base config is invalid: %v", config.Validate(&cfg))
+	}
+
+	tests := []struct {
+		name        string
+		mutate      func(cfg *config.Config)
+		errContains string // empty means the config stays valid
+	}{
+		{"empty scanPath", func(c *config.Config) { c.ScanPath = "" }, "scanPath"},
+		{"port below 1024", func(c *config.Config) { c.Port = 80 }, "port"},
+		{"port above 65535", func(c *config.Config) { c.Port = 70000 }, "port"},
+		{"bindHost hostname", func(c *config.Config) { c.BindHost = "example.com" }, "bindHost"},
+		{"bindHost localhost", func(c *config.Config) { c.BindHost = "localhost" }, ""},
+		{"bindHost IPv6", func(c *config.Config) { c.BindHost = "::1" }, ""},
+		{"local interval too low", func(c *config.Config) { c.LocalIntervalSeconds = 5 }, "localIntervalSeconds"},
+		{"github interval too low", func(c *config.Config) { c.GitHubIntervalSeconds = 30 }, "githubIntervalSeconds"},
+		{"heartbeat too low", func(c *config.Config) { c.HeartbeatSeconds = 1 }, "heartbeatSeconds"},
+		{"heartbeat zero uses default", func(c *config.Config) { c.HeartbeatSeconds = 0 }, ""},
+		{"clone state interval too low", func(c *config.Config) { c.CloneStateIntervalSeconds = 1 }, "cloneStateIntervalSeconds"},
+		{"negative githubFailureThreshold", func(c *config.Config) { c.GitHubFailureThreshold = -1 }, "githubFailureThreshold"},
+		{"negative newRepoDays", func(c *config.Config) { c.NewRepoDays = -1 }, "newRepoDays"},
+		{"negative stateBackupIntervalMinutes", func(c *config.Config) { c.StateBackupIntervalMinutes = -1 }, "stateBackupIntervalMinutes"},
+		{"negative stateBackupKeep", func(c *config.Config) { c.StateBackupKeep = -1 }, "stateBackupKeep"},
+		{"negative autoHideAbandonedDays", func(c *config.Config) { c.AutoHideAbandonedDays = -1 }, "autoHideAbandonedDays"},
+		{"negative cloneDepth", func(c *config.Config) { c.CloneDepth = -1 }, "cloneDepth"},
+		{"unknown cloneProtocol", func(c *config.Config) { c.CloneProtocol = "git" }, "cloneProtocol"},
+		{"ssh cloneProtocol", func(c *config.Config) { c.CloneProtocol = "ssh" }, ""},
+		{"negative maxReposPerScanPath", func(c *config.Config) { c.MaxReposPerScanPath = -1 }, "maxReposPerScanPath"},
+		{"negative coalesceMillis", func(c *config.Config) { c.CoalesceMillis = -1 }, "coalesceMillis"},
+		{"negative sseClientBufferSize", func(c *config.Config) { c.SSEClientBufferSize = -1 }, "sseClientBufferSize"},
+		{"negative sseMaxClients", func(c *config.Config) { c.SSEMaxClients = -1 }, "sseMaxClients"},
+		{"staleDays below 1", func(c *config.Config) { c.StaleDays = 0 }, "staleDays"},
+		{"abandonedDays below 1", func(c *config.Config) { c.AbandonedDays = 0 }, "abandonedDays"},
+		{"staleDays not below abandonedDays", func(c *config.Config) { c.StaleDays = 90 }, "staleDays"},
+		{"unknown lifecycle", func(c *config.Config) { c.LifecyclePriority = []string{"dormant"} }, "lifecyclePriority"},
+		{"duplicate lifecycle", func(c *config.Config) { c.LifecyclePriority = []string{"stale", "stale"} }, "more than once"},
+		{"malformed trackedRepos", func(c *config.Config) { c.TrackedRepos = []string{"/catscan"} }, "trackedRepos"},
+		{"unknown sortTiebreaker", func(c *config.Config) { c.SortTiebreaker = "stars" }, "sortTiebreaker"},
+		{"unknown notificationMode", func(c *config.Config) { c.NotificationMode = "email" }, "notificationMode"},
+		{"unknown notificationLinkTarget", func(c *config.Config) { c.NotificationLinkTarget = "editor" }, "notificationLinkTarget"},
+		{"unknown logLevel", func(c *config.Config) { c.LogLevel = "trace" }, "logLevel"},
+		{"unknown logFormat", func(c *config.Config) { c.LogFormat = "xml" }, "logFormat"},
+		{"webhook mode without URL", func(c *config.Config) { c.NotificationMode = config.NotificationModeWebhook }, "webhookUrl"},
+		{"webhook mode with URL", func(c *config.Config) {
+			c.NotificationMode = config.NotificationModeBoth
+			c.WebhookURL = "https://hooks.example.com/catscan"
+		}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid()
+			tt.mutate(&cfg)
+			err := config.Validate(&cfg)
+			if tt.errContains == "" {
+				if err != nil {
+					t.Errorf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("Validate() = %v, want an error containing %q", err, tt.errContains)
+			}
+		})
+	}
+}
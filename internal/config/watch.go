@@ -0,0 +1,61 @@
+// Package config handles loading and saving CatScan configuration.
+//
+// The watch subpackage reloads the config file when it changes on disk.
+package config
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"time"
+)
+
+// DefaultWatchInterval is how often Watch checks the config file for changes.
+const DefaultWatchInterval = 2 * time.Second
+
+// Watch polls the config file every interval and calls onChange with the
+// reloaded config whenever the file's modification time or size changes.
+// A file that fails to load is logged and skipped until it changes again.
+// Blocks until ctx is cancelled.
+func Watch(ctx context.Context, interval time.Duration, onChange func(Config)) {
+	cfgPath, err := configPath()
+	if err != nil {
+		slog.Warn("config watch disabled", "err", err)
+		return
+	}
+
+	lastMod, lastSize := statConfig(cfgPath)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			mod, size := statConfig(cfgPath)
+			if mod.Equal(lastMod) && size == lastSize {
+				continue
+			}
+			lastMod, lastSize = mod, size
+
+			cfg, err := Load()
+			if err != nil {
+				slog.Warn("config reload failed", "err", err)
+				continue
+			}
+			onChange(cfg)
+		}
+	}
+}
+
+// statConfig returns the config file's modification time and size,
+// or zero values if it doesn't exist.
+func statConfig(path string) (time.Time, int64) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, 0
+	}
+	return info.ModTime(), info.Size()
+}
@@ -0,0 +1,42 @@
+// Package logging configures CatScan's leveled, structured logger.
+package logging
+
+import (
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/alexcatdad/catscan/internal/config"
+)
+
+// New returns a logger writing to w that drops records below level and
+// formats them as text or JSON. Unknown or empty values fall back to
+// info-level text.
+func New(w io.Writer, level, format string) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: parseLevel(level)}
+	if format == config.LogFormatJSON {
+		return slog.New(slog.NewJSONHandler(w, opts))
+	}
+	return slog.New(slog.NewTextHandler(w, opts))
+}
+
+// Setup makes a logger for cfg's logLevel and logFormat the default,
+// writing to stderr. Output from the standard log package goes through it
+// too.
+func Setup(cfg *config.Config) {
+	slog.SetDefault(New(os.Stderr, cfg.LogLevel, cfg.LogFormat))
+}
+
+// parseLevel maps a config logLevel value to its slog level.
+func parseLevel(level string) slog.Level {
+	switch level {
+	case config.LogLevelDebug:
+		return slog.LevelDebug
+	case config.LogLevelWarn:
+		return slog.LevelWarn
+	case config.LogLevelError:
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
@@ -0,0 +1,74 @@
+package logging_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/alexcatdad/catscan/internal/config"
+	"github.com/alexcatdad/catscan/internal/logging"
+)
+
+// TestLevelFiltering tests that records below the configured level are
+// dropped and that an empty level means info.
+func TestLevelFiltering(t *testing.T) {
+	tests := []struct {
+		level string
+		want  []string
+	}{
+		{level: "", want: []string{"info msg", "warn msg", "error msg"}},
+		{level: config.LogLevelDebug, want: []string{"debug msg", "info msg", "warn msg", "error msg"}},
+		{level: config.LogLevelWarn, want: []string{"warn msg", "error msg"}},
+		{level: config.LogLevelError, want: []string{"error msg"}},
+	}
+
+	for _, tt := range tests {
+		t.Run("level "+tt.level, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := logging.New(&buf, tt.level, config.LogFormatText)
+			logger.Debug("debug msg")
+			logger.Info("info msg")
+			logger.Warn("warn msg")
+			logger.Error("error msg")
+
+			lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			if len(lines) != len(tt.want) {
+				t.Fatalf("got %d lines, want %d:\n%s", len(lines), len(tt.want), buf.String())
+			}
+			for i, msg := range tt.want {
+				if !strings.Contains(lines[i], msg) {
+					t.Errorf("line %d = %q, want it to contain %q", i, lines[i], msg)
+				}
+			}
+		})
+	}
+}
+
+// TestJSONFormat tests that logFormat json emits one JSON object per
+// record with the level, message, and attributes as fields.
+func TestJSONFormat(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.New(&buf, config.LogLevelInfo, config.LogFormatJSON)
+	logger.Warn("github poll error", "repo", "catscan", "err", errors.New("rate limited"))
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("output is not a JSON object: %v\n%s", err, buf.String())
+	}
+	want := map[string]string{
+		"level": "WARN",
+		"msg":   "github poll error",
+		"repo":  "catscan",
+		"err":   "rate limited",
+	}
+	for key, value := range want {
+		if record[key] != value {
+			t.Errorf("%s = %v, want %q", key, record[key], value)
+		}
+	}
+	if _, ok := record["time"]; !ok {
+		t.Error("record missing time")
+	}
+}
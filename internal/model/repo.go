@@ -24,6 +24,10 @@ const (
 
 	// LifecycleAbandoned indicates no commits beyond abandoned threshold, no CI.
 	LifecycleAbandoned Lifecycle = "abandoned"
+
+	// LifecycleArchived indicates the repository is archived on GitHub.
+	// Takes precedence over all activity-based states.
+	LifecycleArchived Lifecycle = "archived"
 )
 
 // ActionsStatus represents the CI/CD status from GitHub Actions.
@@ -54,6 +58,9 @@ type CompletenessInfo struct {
 	HasProjectJson bool `json:"HasProjectJson"`
 	HasClaudeMd    bool `json:"HasClaudeMd"`
 	HasAgentsMd    bool `json:"HasAgentsMd"`
+
+	// Score is the percentage of applicable flags set (see CompletenessScore).
+	Score int `json:"Score"`
 }
 
 // Repo represents a unified view of a repository combining local git state
@@ -63,6 +70,9 @@ type Repo struct {
 	Name       string     `json:"Name"`
 	FullName   string     `json:"FullName"`
 	Visibility Visibility `json:"Visibility"`
+	// Owner is the org the repo was listed from; empty means the
+	// configured owner.
+	Owner string `json:"Owner,omitempty"`
 
 	// Clone state
 	Cloned    bool   `json:"Cloned"`
@@ -71,26 +81,54 @@ type Repo struct {
 	// Local git (cloned repos only)
 	Branch          string    `json:"Branch,omitempty"`
 	Dirty           bool      `json:"Dirty,omitempty"`
+	Untracked       int       `json:"Untracked,omitempty"`
+	Modified        int       `json:"Modified,omitempty"`
+	Staged          int       `json:"Staged,omitempty"`
 	LocalLastCommit time.Time `json:"LocalLastCommit,omitempty"`
+	Ahead           int       `json:"Ahead,omitempty"`
+	Behind          int       `json:"Behind,omitempty"`
+	UnpushedTags    []string  `json:"UnpushedTags,omitempty"`
 
 	// GitHub metadata
-	Description string   `json:"Description,omitempty"`
-	HomepageURL string   `json:"HomepageURL,omitempty"`
-	Language    string   `json:"Language,omitempty"`
-	Topics      []string `json:"Topics,omitempty"`
+	DefaultBranch  string   `json:"DefaultBranch,omitempty"`
+	Description    string   `json:"Description,omitempty"`
+	HomepageURL    string   `json:"HomepageURL,omitempty"`
+	Language       string   `json:"Language,omitempty"`
+	Topics         []string `json:"Topics,omitempty"`
+	Archived       bool     `json:"Archived,omitempty"`
+	Fork           bool     `json:"Fork,omitempty"`
+	Stars          int      `json:"Stars"`
+	Forks          int      `json:"Forks"`
+	HasWiki        bool     `json:"HasWiki"`
+	HasDiscussions bool     `json:"HasDiscussions"`
+
+	// Project metadata from .project.json
+	ProjectGroup  string `json:"ProjectGroup,omitempty"`
+	ProjectStatus string `json:"ProjectStatus,omitempty"`
 
 	// Completeness (nested for frontend consumption)
 	Completeness CompletenessInfo `json:"Completeness"`
 
 	// Activity
+	CreatedAt      time.Time     `json:"CreatedAt,omitzero"`
 	GitHubLastPush time.Time     `json:"GitHubLastPush"`
 	OpenPRs        int           `json:"OpenPRs"`
+	OpenIssues     int           `json:"OpenIssues"`
 	ActionsStatus  ActionsStatus `json:"ActionsStatus"`
 	LatestRelease  *ReleaseInfo  `json:"LatestRelease,omitempty"`
 	NewRelease     bool          `json:"NewRelease"`
 
+	// User state
+	SnoozeUntil time.Time `json:"SnoozeUntil,omitzero"`
+	Hidden      bool      `json:"Hidden,omitempty"`
+	Tags        []string  `json:"Tags,omitempty"`
+	Note        string    `json:"Note,omitempty"`
+
 	// Computed
-	Lifecycle Lifecycle `json:"Lifecycle"`
+	Lifecycle      Lifecycle `json:"Lifecycle"`
+	NeedsAttention bool      `json:"NeedsAttention"`
+	SuggestArchive bool      `json:"SuggestArchive"`
+	IsNew          bool      `json:"IsNew"`
 }
 
 // ReleaseInfo represents a GitHub release.
@@ -99,21 +137,41 @@ type ReleaseInfo struct {
 	PublishedAt time.Time `json:"PublishedAt"`
 }
 
-// LifecycleThresholds defines the day thresholds for lifecycle classification.
+// LifecycleThresholds defines the day thresholds and optional rules for
+// lifecycle classification, and the window for flagging new repos.
 type LifecycleThresholds struct {
 	StaleDays     int
 	AbandonedDays int
+
+	// DirtyIsOngoing treats uncommitted local changes, or a local commit
+	// within StaleDays, as ongoing work regardless of GitHub activity.
+	DirtyIsOngoing bool
+
+	// NewRepoDays is how many days after creation a repo is flagged IsNew.
+	NewRepoDays int
 }
 
-// ComputeLifecycle calculates the lifecycle status based on activity signals.
+// ComputeLifecycle calculates the lifecycle status based on activity signals,
+// measured against the current time in UTC.
 func (r *Repo) ComputeLifecycle(thresholds LifecycleThresholds) Lifecycle {
-	now := time.Now()
+	return r.ComputeLifecycleAt(thresholds, time.Now())
+}
+
+// ComputeLifecycleAt calculates the lifecycle status as of now.
+// All day counts are taken in UTC, so the result does not depend on the
+// host timezone or on the zone of the stored timestamps.
+func (r *Repo) ComputeLifecycleAt(thresholds LifecycleThresholds, now time.Time) Lifecycle {
+	now = now.UTC()
+
+	// Archived repos are read-only on GitHub, so age says nothing about them
+	if r.Archived {
+		return LifecycleArchived
+	}
 
 	// Check for ongoing indicators
 	// 1. Recent commits within stale threshold
 	if !r.GitHubLastPush.IsZero() {
-		daysSincePush := int(now.Sub(r.GitHubLastPush).Hours() / 24)
-		if daysSincePush < thresholds.StaleDays {
+		if daysSince(now, r.GitHubLastPush) < thresholds.StaleDays {
 			return LifecycleOngoing
 		}
 	}
@@ -128,9 +186,21 @@ func (r *Repo) ComputeLifecycle(thresholds LifecycleThresholds) Lifecycle {
 		return LifecycleOngoing
 	}
 
+	// 4. Local work in progress, when enabled
+	if thresholds.DirtyIsOngoing {
+		if r.Dirty {
+			return LifecycleOngoing
+		}
+		if !r.LocalLastCommit.IsZero() {
+			if daysSince(now, r.LocalLastCommit) < thresholds.StaleDays {
+				return LifecycleOngoing
+			}
+		}
+	}
+
 	// At this point, no ongoing indicators
 	if !r.GitHubLastPush.IsZero() {
-		daysSincePush := int(now.Sub(r.GitHubLastPush).Hours() / 24)
+		daysSincePush := daysSince(now, r.GitHubLastPush)
 
 		if daysSincePush >= thresholds.StaleDays && daysSincePush < thresholds.AbandonedDays {
 			return LifecycleStale
@@ -144,3 +214,72 @@ func (r *Repo) ComputeLifecycle(thresholds LifecycleThresholds) Lifecycle {
 	// No push data at all - treat as stale
 	return LifecycleStale
 }
+
+// daysSince returns the number of whole days elapsed from t to now,
+// with both times converted to UTC.
+func daysSince(now, t time.Time) int {
+	return int(now.UTC().Sub(t.UTC()).Hours() / 24)
+}
+
+// ComputeSuggestArchive reports whether the repo is a candidate for archiving:
+// abandoned, public, and not already archived. Lifecycle must be computed first.
+func (r *Repo) ComputeSuggestArchive() bool {
+	return r.Lifecycle == LifecycleAbandoned && r.Visibility == VisibilityPublic && !r.Archived
+}
+
+// ComputeAutoHide reports whether the repo should be auto-hidden: uncloned,
+// abandoned, and last pushed at least days ago. Lifecycle must be computed
+// first. A non-positive days never hides.
+func (r *Repo) ComputeAutoHide(days int) bool {
+	return r.ComputeAutoHideAt(days, time.Now())
+}
+
+// ComputeAutoHideAt reports whether the repo should be auto-hidden as of now.
+func (r *Repo) ComputeAutoHideAt(days int, now time.Time) bool {
+	if days <= 0 || r.Cloned || r.Lifecycle != LifecycleAbandoned || r.GitHubLastPush.IsZero() {
+		return false
+	}
+	return daysSince(now, r.GitHubLastPush) >= days
+}
+
+// ComputeIsNew reports whether the repo was created on GitHub within the
+// last newRepoDays days.
+func (r *Repo) ComputeIsNew(newRepoDays int) bool {
+	return r.ComputeIsNewAt(newRepoDays, time.Now())
+}
+
+// ComputeIsNewAt reports whether the repo was created within newRepoDays
+// days of now. Repos with no creation time are never new.
+func (r *Repo) ComputeIsNewAt(newRepoDays int, now time.Time) bool {
+	return !r.CreatedAt.IsZero() && daysSince(now, r.CreatedAt) < newRepoDays
+}
+
+// CompletenessScore returns the percentage (0-100) of applicable completeness
+// flags that are set. Applicable to every repo are a description, README,
+// license, topics, .project.json, and agent instructions (CLAUDE.md or
+// AGENTS.md, counted once). A homepage and GitHub Pages only make sense for
+// site repos, so they are not counted.
+func (r *Repo) CompletenessScore() int {
+	c := r.Completeness
+	applicable := []bool{
+		c.HasDescription,
+		c.HasReadme,
+		c.HasLicense,
+		c.HasTopics,
+		c.HasProjectJson,
+		c.HasClaudeMd || c.HasAgentsMd,
+	}
+	set := 0
+	for _, ok := range applicable {
+		if ok {
+			set++
+		}
+	}
+	return (set*100 + len(applicable)/2) / len(applicable)
+}
+
+// ComputeNeedsAttention reports whether the repo has local state the user
+// should act on, such as tags that were never pushed.
+func (r *Repo) ComputeNeedsAttention() bool {
+	return len(r.UnpushedTags) > 0
+}
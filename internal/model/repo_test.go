@@ -159,6 +159,53 @@ func TestLifecycleAbandoned(t *testing.T) {
 	}
 }
 
+// TestLifecycleDirtyIsOngoing tests that a dirty old repo is ongoing only
+// when the DirtyIsOngoing rule is enabled.
+func TestLifecycleDirtyIsOngoing(t *testing.T) {
+	repo := &model.Repo{
+		Name:            "test-repo",
+		GitHubLastPush:  time.Now().Add(-100 * 24 * time.Hour), // 100 days ago
+		LocalLastCommit: time.Now().Add(-100 * 24 * time.Hour),
+		Dirty:           true,
+		ActionsStatus:   model.ActionsStatusNone,
+	}
+
+	thresholds := model.LifecycleThresholds{
+		StaleDays:     30,
+		AbandonedDays: 90,
+	}
+
+	if lifecycle := repo.ComputeLifecycle(thresholds); lifecycle != model.LifecycleAbandoned {
+		t.Errorf("rule disabled: lifecycle = %s, want %s", lifecycle, model.LifecycleAbandoned)
+	}
+
+	thresholds.DirtyIsOngoing = true
+	if lifecycle := repo.ComputeLifecycle(thresholds); lifecycle != model.LifecycleOngoing {
+		t.Errorf("rule enabled: lifecycle = %s, want %s", lifecycle, model.LifecycleOngoing)
+	}
+}
+
+// TestLifecycleArchived tests that an old archived repo is classified
+// archived, not abandoned.
+func TestLifecycleArchived(t *testing.T) {
+	repo := &model.Repo{
+		Name:           "test-repo",
+		GitHubLastPush: time.Now().Add(-400 * 24 * time.Hour), // 400 days ago
+		ActionsStatus:  model.ActionsStatusNone,
+		Archived:       true,
+	}
+
+	thresholds := model.LifecycleThresholds{
+		StaleDays:     30,
+		AbandonedDays: 90,
+	}
+
+	lifecycle := repo.ComputeLifecycle(thresholds)
+	if lifecycle != model.LifecycleArchived {
+		t.Errorf("lifecycle = %s, want %s", lifecycle, model.LifecycleArchived)
+	}
+}
+
 // TestLifecycleNoPushData tests that a repo with no push data is treated as stale.
 func TestLifecycleNoPushData(t *testing.T) {
 	repo := &model.Repo{
@@ -251,3 +298,165 @@ func TestLifecycleAtThresholdBoundaries(t *testing.T) {
 		}
 	})
 }
+
+// TestNeedsAttentionUnpushedTags tests that unpushed tags flag a repo for attention.
+func TestNeedsAttentionUnpushedTags(t *testing.T) {
+	repo := &model.Repo{Name: "test-repo"}
+	if repo.ComputeNeedsAttention() {
+		t.Error("NeedsAttention = true, want false with no unpushed tags")
+	}
+
+	repo.UnpushedTags = []string{"v1.1.0"}
+	if !repo.ComputeNeedsAttention() {
+		t.Error("NeedsAttention = false, want true with unpushed tags")
+	}
+}
+
+// TestSuggestArchive tests that only abandoned, public, unarchived repos are suggested.
+func TestSuggestArchive(t *testing.T) {
+	tests := []struct {
+		name string
+		repo model.Repo
+		want bool
+	}{
+		{
+			name: "abandoned public",
+			repo: model.Repo{Lifecycle: model.LifecycleAbandoned, Visibility: model.VisibilityPublic},
+			want: true,
+		},
+		{
+			name: "already archived",
+			repo: model.Repo{Lifecycle: model.LifecycleAbandoned, Visibility: model.VisibilityPublic, Archived: true},
+			want: false,
+		},
+		{
+			name: "private",
+			repo: model.Repo{Lifecycle: model.LifecycleAbandoned, Visibility: model.VisibilityPrivate},
+			want: false,
+		},
+		{
+			name: "stale",
+			repo: model.Repo{Lifecycle: model.LifecycleStale, Visibility: model.VisibilityPublic},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.repo.ComputeSuggestArchive(); got != tt.want {
+				t.Errorf("ComputeSuggestArchive() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestIsNew tests that repos are flagged new only within the window after creation.
+func TestIsNew(t *testing.T) {
+	now := time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		createdAt time.Time
+		want      bool
+	}{
+		{name: "created today", createdAt: now.Add(-time.Hour), want: true},
+		{name: "inside window", createdAt: now.AddDate(0, 0, -6), want: true},
+		{name: "at window edge", createdAt: now.AddDate(0, 0, -7), want: false},
+		{name: "old repo", createdAt: now.AddDate(-1, 0, 0), want: false},
+		{name: "unknown creation time", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := model.Repo{CreatedAt: tt.createdAt}
+			if got := repo.ComputeIsNewAt(7, now); got != tt.want {
+				t.Errorf("ComputeIsNewAt(7) = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestCompletenessScore tests the percentage of applicable completeness flags.
+func TestCompletenessScore(t *testing.T) {
+	tests := []struct {
+		name         string
+		completeness model.CompletenessInfo
+		want         int
+	}{
+		{name: "nothing", want: 0},
+		{
+			name: "everything",
+			completeness: model.CompletenessInfo{
+				HasDescription: true, HasReadme: true, HasLicense: true,
+				HasTopics: true, HasProjectJson: true, HasClaudeMd: true, HasAgentsMd: true,
+			},
+			want: 100,
+		},
+		{
+			name:         "half",
+			completeness: model.CompletenessInfo{HasDescription: true, HasReadme: true, HasLicense: true},
+			want:         50,
+		},
+		{
+			name:         "either agent file counts once",
+			completeness: model.CompletenessInfo{HasAgentsMd: true},
+			want:         17,
+		},
+		{
+			name:         "homepage and pages are not counted",
+			completeness: model.CompletenessInfo{HasHomepage: true, HasPages: true},
+			want:         0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := model.Repo{Completeness: tt.completeness}
+			if got := repo.CompletenessScore(); got != tt.want {
+				t.Errorf("CompletenessScore() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestLifecycleTimezoneBoundary tests that day counting near the stale
+// threshold is the same whatever zone the clock, the stored timestamp, or
+// the host are in.
+func TestLifecycleTimezoneBoundary(t *testing.T) {
+	thresholds := model.LifecycleThresholds{StaleDays: 30, AbandonedDays: 90}
+
+	// Just before midnight in New York, already the next day in UTC
+	push := time.Date(2026, 1, 1, 23, 30, 0, 0, time.FixedZone("EST", -5*3600))
+	zones := []*time.Location{
+		time.UTC,
+		time.FixedZone("LINT", 14*3600),
+		time.FixedZone("BIT", -12*3600),
+	}
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want model.Lifecycle
+	}{
+		{"one minute before threshold", push.Add(30*24*time.Hour - time.Minute), model.LifecycleOngoing},
+		{"exactly at threshold", push.Add(30 * 24 * time.Hour), model.LifecycleStale},
+	}
+
+	originalLocal := time.Local
+	defer func() { time.Local = originalLocal }()
+
+	for _, tt := range tests {
+		for _, host := range zones {
+			for _, clock := range zones {
+				for _, stored := range zones {
+					time.Local = host
+					repo := model.Repo{GitHubLastPush: push.In(stored)}
+					if got := repo.ComputeLifecycleAt(thresholds, tt.now.In(clock)); got != tt.want {
+						t.Errorf("%s (host %s, clock %s, stored %s): lifecycle = %s, want %s",
+							tt.name, host, clock, stored, got, tt.want)
+					}
+				}
+			}
+		}
+	}
+}
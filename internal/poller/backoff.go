@@ -0,0 +1,90 @@
+// Package poller manages background polling for local and GitHub data.
+//
+// The backoff subpackage stretches the GitHub poll interval while gh keeps
+// failing, so an outage doesn't produce an error every cycle.
+package poller
+
+import (
+	"time"
+
+	"github.com/alexcatdad/catscan/internal/config"
+	"github.com/alexcatdad/catscan/internal/scanner"
+)
+
+// maxGitHubBackoff caps the GitHub poll interval during consecutive failures.
+const maxGitHubBackoff = 30 * time.Minute
+
+// Backoff growth per consecutive failure. Transient failures (rate limits,
+// network errors) double the interval; fatal ones (gh missing or not
+// authenticated) need the user to act, so they back off harder.
+const (
+	transientBackoffFactor = 2
+	fatalBackoffFactor     = 4
+)
+
+// isFatalGitHubError reports whether err will persist until the user acts.
+func isFatalGitHubError(err error) bool {
+	return scanner.IsGHNotFound(err) || scanner.IsGHAuthError(err)
+}
+
+// githubBackoff returns the delay before the next GitHub poll after
+// failures consecutive failures, the last of which was fatal or not.
+// Zero failures returns base. The delay never exceeds maxGitHubBackoff,
+// unless base itself is longer.
+func githubBackoff(base time.Duration, failures int, fatal bool) time.Duration {
+	limit := max(base, maxGitHubBackoff)
+
+	factor := time.Duration(transientBackoffFactor)
+	if fatal {
+		factor = fatalBackoffFactor
+	}
+
+	delay := base
+	for range failures {
+		delay *= factor
+		if delay >= limit {
+			return limit
+		}
+	}
+	return delay
+}
+
+// recordGitHubResult updates the consecutive failure count after a poll,
+// and the rate-limit reset time when gh reported one.
+// Only the poll loop goroutine calls this.
+func (p *Poller) recordGitHubResult(err error) {
+	p.githubRateLimitReset = scanner.RateLimitReset(err)
+	if err == nil {
+		p.githubFailures.Store(0)
+		p.githubFatal = false
+		return
+	}
+	p.githubFailures.Add(1)
+	p.githubFatal = isFatalGitHubError(err)
+}
+
+// GitHubFailures returns the number of consecutive failed GitHub polls.
+func (p *Poller) GitHubFailures() int {
+	return int(p.githubFailures.Load())
+}
+
+// GitHubHealthy reports whether GitHub polling is healthy: fewer than the
+// configured threshold of consecutive polls have failed.
+func (p *Poller) GitHubHealthy() bool {
+	threshold := p.config().GitHubFailureThreshold
+	if threshold <= 0 {
+		threshold = config.DefaultGitHubFailureThreshold
+	}
+	return p.GitHubFailures() < threshold
+}
+
+// nextGitHubInterval returns the delay before the next GitHub poll: the
+// configured interval, stretched by backoff while polls keep failing, and
+// never earlier than a known rate-limit reset.
+func (p *Poller) nextGitHubInterval() time.Duration {
+	delay := githubBackoff(p.GitHubInterval(), p.GitHubFailures(), p.githubFatal)
+	if !p.githubRateLimitReset.IsZero() {
+		delay = max(delay, time.Until(p.githubRateLimitReset))
+	}
+	return delay
+}
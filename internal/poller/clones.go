@@ -0,0 +1,112 @@
+// Package poller manages background polling for local and GitHub data.
+//
+// The clones subpackage tracks clones from the moment they are claimed
+// until they finish, and keeps a short history of finished clones so their
+// outcome can be read after the clone_progress events are gone.
+package poller
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/alexcatdad/catscan/internal/scanner"
+)
+
+// maxCloneHistory caps how many finished clones are kept.
+const maxCloneHistory = 50
+
+// CloneQueued is the state of a clone that is claimed but waiting for a
+// slot, before scanner reports it started.
+const CloneQueued scanner.CloneState = "queued"
+
+// CloneRecord is the tracked state of one clone.
+type CloneRecord struct {
+	Repo       string             `json:"repo"`
+	State      scanner.CloneState `json:"state"`
+	StartedAt  time.Time          `json:"startedAt"`
+	FinishedAt time.Time          `json:"finishedAt,omitzero"`
+	Error      string             `json:"error,omitempty"`
+}
+
+// CloneStarted marks repo as being cloned, so discovery skips its
+// directory until CloneFinished is called, and starts tracking it as
+// queued. It reports false, changing nothing, when a clone of repo is
+// already in progress.
+func (p *Poller) CloneStarted(repo string) bool {
+	p.cloningMu.Lock()
+	defer p.cloningMu.Unlock()
+	if p.cloning[repo] != nil {
+		return false
+	}
+	if p.cloning == nil {
+		p.cloning = make(map[string]*CloneRecord)
+	}
+	p.cloning[repo] = &CloneRecord{Repo: repo, State: CloneQueued, StartedAt: time.Now().UTC()}
+	return true
+}
+
+// CloneProgress records a status update for a clone in progress.
+// Updates for repos without a claimed clone are ignored.
+func (p *Poller) CloneProgress(status scanner.CloneStatus) {
+	p.cloningMu.Lock()
+	defer p.cloningMu.Unlock()
+	record := p.cloning[status.Repo]
+	if record == nil {
+		return
+	}
+	record.State = status.State
+	record.Error = status.Error
+}
+
+// CloneFinished marks repo's clone as finished, whether it succeeded or not,
+// and moves it into the clone history. A clone that never reported an
+// outcome is recorded as an error.
+func (p *Poller) CloneFinished(repo string) {
+	p.cloningMu.Lock()
+	defer p.cloningMu.Unlock()
+	record := p.cloning[repo]
+	if record == nil {
+		return
+	}
+	delete(p.cloning, repo)
+
+	if record.State != scanner.CloneStateCompleted && record.State != scanner.CloneStateError {
+		record.State = scanner.CloneStateError
+		record.Error = "clone did not finish"
+	}
+	record.FinishedAt = time.Now().UTC()
+	p.cloneHistory = append(p.cloneHistory, *record)
+	if len(p.cloneHistory) > maxCloneHistory {
+		p.cloneHistory = slices.Delete(p.cloneHistory, 0, len(p.cloneHistory)-maxCloneHistory)
+	}
+}
+
+// IsCloning reports whether repo has a clone in progress.
+func (p *Poller) IsCloning(repo string) bool {
+	p.cloningMu.Lock()
+	defer p.cloningMu.Unlock()
+	return p.cloning[repo] != nil
+}
+
+// Clones returns the clones in progress, oldest first, followed by the
+// finished clones, most recent first.
+func (p *Poller) Clones() []CloneRecord {
+	p.cloningMu.Lock()
+	defer p.cloningMu.Unlock()
+
+	clones := make([]CloneRecord, 0, len(p.cloning)+len(p.cloneHistory))
+	for _, record := range p.cloning {
+		clones = append(clones, *record)
+	}
+	slices.SortFunc(clones, func(a, b CloneRecord) int {
+		if c := a.StartedAt.Compare(b.StartedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Repo, b.Repo)
+	})
+	for i := len(p.cloneHistory) - 1; i >= 0; i-- {
+		clones = append(clones, p.cloneHistory[i])
+	}
+	return clones
+}
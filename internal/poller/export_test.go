@@ -0,0 +1,72 @@
+package poller
+
+import (
+	"context"
+	"time"
+
+	"github.com/alexcatdad/catscan/internal/model"
+)
+
+// Test-only exports for the poller_test package.
+
+// SetPreviousRepos sets the previous repo list for change detection.
+func (p *Poller) SetPreviousRepos(repos []model.Repo) {
+	p.setPreviousRepos(repos)
+}
+
+// DetectAndEmitChanges runs change detection against the previous repo list.
+func (p *Poller) DetectAndEmitChanges(repos []model.Repo) {
+	p.detectAndEmitChanges(repos, "test")
+}
+
+// BroadcastRepos writes the cache and broadcasts a snapshot like a poll does.
+func (p *Poller) BroadcastRepos(eventType string, repos []model.Repo) {
+	p.writeCache(repos)
+	p.broadcastRepos(eventType, repos)
+}
+
+// LocalPoll runs a single local poll cycle.
+func (p *Poller) LocalPoll(ctx context.Context) {
+	p.localPoll(ctx)
+}
+
+// RunLocalPoller runs the local poll loop until ctx is cancelled.
+func (p *Poller) RunLocalPoller(ctx context.Context) {
+	p.runLocalPoller(ctx)
+}
+
+// RunGitHubPoller runs the GitHub poll loop until ctx is cancelled.
+func (p *Poller) RunGitHubPoller(ctx context.Context) {
+	p.runGitHubPoller(ctx)
+}
+
+// NewNotifierFor creates a Notifier for goos with an injected binary lookup.
+func NewNotifierFor(goos string, lookPath func(file string) (string, error)) *Notifier {
+	return newNotifier(goos, lookPath)
+}
+
+// Backend returns the selected notification backend name.
+func (n *Notifier) Backend() string {
+	return n.backend
+}
+
+// ReconcileCloneState runs a clone-state-only reconcile.
+func (p *Poller) ReconcileCloneState() (int, error) {
+	return p.reconcileCloneState()
+}
+
+// GitHubPoll runs a single GitHub poll cycle and returns the delay before
+// the next one.
+func (p *Poller) GitHubPoll(ctx context.Context) time.Duration {
+	return p.githubPollOnce(ctx)
+}
+
+// DropRemoved filters removed repos out of a poll result.
+func (p *Poller) DropRemoved(repos []model.Repo, inSource func(name string) bool) []model.Repo {
+	return p.dropRemoved(repos, inSource)
+}
+
+// BindContext sets the run context without starting the poll loops.
+func (p *Poller) BindContext(ctx context.Context) {
+	p.bindContext(ctx)
+}
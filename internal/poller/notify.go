@@ -1,54 +1,109 @@
 // Package poller manages background polling for local and GitHub data.
 //
-// The notify subpackage handles macOS notifications.
+// The notify subpackage handles desktop notifications on macOS and Linux.
 package poller
 
 import (
+	"cmp"
+	"errors"
 	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
 	"os/exec"
+	"runtime"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
+
+	"github.com/alexcatdad/catscan/internal/config"
+)
+
+// Notification backends, chosen once per Notifier based on OS and installed tools.
+const (
+	backendTerminalNotifier = "terminal-notifier"
+	backendOSAScript        = "osascript"
+	backendNotifySend       = "notify-send"
 )
 
-// Notifier sends macOS notifications.
+// errNoNotifyBackend is returned when no notification tool is available.
+var errNoNotifyBackend = errors.New("no notification backend available")
+
+// notificationsStopped is set during shutdown so no notifier subprocess
+// is spawned while the process is exiting.
+var notificationsStopped atomic.Bool
+
+// StopNotifications makes SendNotification a no-op for the rest of the
+// process lifetime. Call it when shutdown begins.
+func StopNotifications() {
+	notificationsStopped.Store(true)
+}
+
+// Notifier sends desktop notifications.
 type Notifier struct {
-	useTerminalNotifier bool
-	terminalNotifierPath string
-	once                 sync.Once
+	backend  string
+	binPath  string
+	goos     string
+	lookPath func(file string) (string, error)
+	once     sync.Once
 }
 
-// NewNotifier creates a new Notifier.
+// NewNotifier creates a new Notifier for the current OS.
 func NewNotifier() *Notifier {
-	n := &Notifier{}
+	return newNotifier(runtime.GOOS, exec.LookPath)
+}
+
+// newNotifier creates a Notifier for goos, using lookPath to find binaries.
+func newNotifier(goos string, lookPath func(file string) (string, error)) *Notifier {
+	n := &Notifier{goos: goos, lookPath: lookPath}
 	n.init()
 	return n
 }
 
-// init checks for terminal-notifier availability.
+// init selects the notification backend for the OS.
+// macOS prefers terminal-notifier and falls back to osascript.
+// Linux uses notify-send when installed. Other systems have no backend.
 func (n *Notifier) init() {
 	n.once.Do(func() {
-		// Check common paths for terminal-notifier
-		paths := []string{
-			"/opt/homebrew/bin/terminal-notifier",
-			"/usr/local/bin/terminal-notifier",
-		}
+		switch n.goos {
+		case "darwin":
+			// Check common paths for terminal-notifier
+			paths := []string{
+				"/opt/homebrew/bin/terminal-notifier",
+				"/usr/local/bin/terminal-notifier",
+			}
 
-		for _, path := range paths {
-			if _, err := exec.LookPath(path); err == nil {
-				n.useTerminalNotifier = true
-				n.terminalNotifierPath = path
-				return
+			for _, path := range paths {
+				if _, err := n.lookPath(path); err == nil {
+					n.backend = backendTerminalNotifier
+					n.binPath = path
+					return
+				}
+			}
+			n.backend = backendOSAScript
+
+		case "linux":
+			if path, err := n.lookPath("notify-send"); err == nil {
+				n.backend = backendNotifySend
+				n.binPath = path
 			}
 		}
 	})
 }
 
-// Notify sends a macOS notification.
+// Notify sends a desktop notification.
 func (n *Notifier) Notify(title, message, url string) error {
-	if n.useTerminalNotifier {
+	switch n.backend {
+	case backendTerminalNotifier:
 		return n.notifyTerminalNotifier(title, message, url)
+	case backendOSAScript:
+		return n.notifyOSAScript(title, message)
+	case backendNotifySend:
+		return n.notifyNotifySend(title, message)
+	default:
+		return errNoNotifyBackend
 	}
-	return n.notifyOSAScript(title, message)
 }
 
 // notifyTerminalNotifier sends a notification using terminal-notifier.
@@ -62,7 +117,7 @@ func (n *Notifier) notifyTerminalNotifier(title, message, url string) error {
 		args = append(args, "-open", url)
 	}
 
-	cmd := exec.Command(n.terminalNotifierPath, args...)
+	cmd := exec.Command(n.binPath, args...)
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("terminal-notifier: %w", err)
 	}
@@ -86,15 +141,64 @@ func (n *Notifier) notifyOSAScript(title, message string) error {
 	return nil
 }
 
-// SendNotification sends a notification for a repo event.
-func SendNotification(eventType, repoName, message string) {
+// notifyNotifySend sends a notification using notify-send (libnotify).
+func (n *Notifier) notifyNotifySend(title, message string) error {
+	// "--" stops option parsing so a message starting with "-" is not a flag
+	cmd := exec.Command(n.binPath, "--app-name=CatScan", "--", title, message)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("notify-send: %w", err)
+	}
+
+	return nil
+}
+
+// NotificationURL returns the link opened when a notification for repoName
+// is clicked, according to cfg.NotificationLinkTarget. GitHub links use
+// owner, falling back to cfg.GitHubOwner when it is empty. An empty
+// repoName links to the owner on GitHub or to the dashboard home.
+func NotificationURL(cfg *config.Config, owner, repoName string) string {
+	if cfg.NotificationLinkTarget == config.NotificationLinkDashboard {
+		base := "http://" + dashboardAddr(cfg)
+		if repoName == "" {
+			return base + "/"
+		}
+		return base + "/repo/" + url.PathEscape(repoName)
+	}
+	owner = cmp.Or(owner, cfg.GitHubOwner)
+	if repoName == "" {
+		return fmt.Sprintf("https://github.com/%s", owner)
+	}
+	return fmt.Sprintf("https://github.com/%s/%s", owner, url.PathEscape(repoName))
+}
+
+// dashboardAddr returns the host:port the dashboard is reachable at on this
+// machine. A wildcard BindHost listens on every interface, so it is reached
+// through loopback.
+func dashboardAddr(cfg *config.Config) string {
+	host := cmp.Or(cfg.BindHost, config.DefaultBindHost)
+	if ip := net.ParseIP(host); ip != nil && ip.IsUnspecified() {
+		host = config.DefaultBindHost
+	}
+	return net.JoinHostPort(host, strconv.Itoa(cfg.Port))
+}
+
+// SendNotification sends a notification for a repo event, or for an
+// app-wide event when repoName is empty. Clicking the notification opens link.
+// Nothing is sent once StopNotifications has been called.
+func SendNotification(eventType, repoName, message, link string) {
+	if notificationsStopped.Load() {
+		return
+	}
+
 	notifier := NewNotifier()
 
-	title := fmt.Sprintf("CatScan — %s", repoName)
-	url := fmt.Sprintf("https://projects.dashboard/repo/%s", repoName)
+	title := "CatScan"
+	if repoName != "" {
+		title = fmt.Sprintf("CatScan — %s", repoName)
+	}
 
-	if err := notifier.Notify(title, message, url); err != nil {
+	if err := notifier.Notify(title, message, link); err != nil {
 		// Log but don't fail — notification failures are non-critical
-		fmt.Printf("notification error: %v\n", err)
+		slog.Warn("notification failed", "err", err)
 	}
 }
@@ -6,10 +6,16 @@
 package poller
 
 import (
+	"bytes"
+	"cmp"
 	"context"
+	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
+	"slices"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/alexcatdad/catscan/internal/cache"
@@ -22,6 +28,7 @@ import (
 // Poller manages background polling for repository data.
 type Poller struct {
 	cfg             *config.Config
+	cfgMu           sync.RWMutex
 	hub             *sse.Hub
 	state           cache.RepoState
 	stateMu         sync.RWMutex
@@ -30,26 +37,182 @@ type Poller struct {
 	lastLocalPollMu sync.RWMutex
 	lastGitHubPollMu sync.RWMutex
 
+	// Most recent failure of each poll, cleared by its next success.
+	// Guarded by the matching lastLocalPollMu or lastGitHubPollMu.
+	lastLocalPollErr  *PollError
+	lastGitHubPollErr *PollError
+
 	// Previous data for change detection
 	previousRepos   []model.Repo
 	previousReposMu sync.RWMutex
+
+	// Per-repo GitHub fetchers
+	fetchers Fetchers
+
+	// notify delivers a desktop notification linking to url
+	notify func(eventType, repo, message, url string)
+
+	// Latest snapshot waiting out the coalescing window
+	pendingType  string
+	pendingRepos []model.Repo
+	pendingTimer *time.Timer
+	pendingMu    sync.Mutex
+
+	// Type of the last GitHub poll error, so a persistent failure
+	// notifies once rather than every cycle
+	lastGitHubErr string
+
+	// Consecutive GitHub poll failures, for backoff and health.
+	// The count is read by health checks, so it is atomic.
+	githubFailures       atomic.Int32
+	githubFatal          bool
+	githubRateLimitReset time.Time
+
+	// Repos removed by the user. A poll that read the cache before the
+	// removal drops them again unless its own source still has them.
+	removed   map[string]bool
+	removedMu sync.Mutex
+
+	// Repos with a clone in progress. Local polls skip their half-cloned
+	// directories until the clone finishes. Finished clones move to
+	// cloneHistory (see clones.go).
+	cloning      map[string]*CloneRecord
+	cloneHistory []CloneRecord
+	cloningMu    sync.Mutex
+
+	// Poll sources whose last cycle reported per-repo errors, so the next
+	// clean cycle broadcasts an empty poll_errors event to clear them
+	pollErrorSources map[string]bool
+	pollErrorsMu     sync.Mutex
+
+	// Context passed to Start; once it is cancelled no notifications are sent
+	runCtx atomic.Pointer[context.Context]
+
+	// Signal the poll loops to adopt a new config
+	localReconfigure  chan struct{}
+	githubReconfigure chan struct{}
+	rescanPending     atomic.Bool
+
+	// Field refreshes from RefreshField, run on the GitHub loop so they
+	// never overlap a GitHub poll (see refresh.go)
+	refreshRequests chan refreshRequest
+}
+
+// Fetchers holds the GitHub fetch functions used by the poller: the repo
+// listing and the per-repo fetches. They can be replaced in tests to avoid
+// calling the gh CLI.
+type Fetchers struct {
+	ListRepos      func(owner string) ([]scanner.GitHubRepo, error)
+	ListOrgs       func() ([]string, error)
+	PROpenCount    func(owner, name string) (int, error)
+	IssueOpenCount func(owner, name string) (int, error)
+	ActionsStatus  func(owner, name string) (string, error)
+	FilePresence   func(owner, name string) (*scanner.FilePresence, error)
+	LatestRelease  func(owner, name string) (*scanner.LatestRelease, error)
+	ProjectFile    func(owner, name string) (*scanner.ProjectInfo, error)
+}
+
+// DefaultFetchers returns fetchers backed by the gh CLI.
+func DefaultFetchers() Fetchers {
+	return Fetchers{
+		ListRepos:      scanner.ListGitHubRepos,
+		PROpenCount:    scanner.GetPROpenCount,
+		IssueOpenCount: scanner.GetIssueOpenCount,
+		ListOrgs:       scanner.ListUserOrgs,
+		ActionsStatus:  scanner.GetActionsStatus,
+		FilePresence:   scanner.GetFilePresence,
+		LatestRelease:  scanner.GetLatestRelease,
+		ProjectFile:    scanner.GetProjectFile,
+	}
 }
 
 // NewPoller creates a new Poller.
 func NewPoller(cfg *config.Config, hub *sse.Hub) *Poller {
 	return &Poller{
-		cfg:   cfg,
-		hub:   hub,
-		state: make(cache.RepoState),
+		cfg:               cfg,
+		hub:               hub,
+		state:             make(cache.RepoState),
+		fetchers:          DefaultFetchers(),
+		notify:            SendNotification,
+		localReconfigure:  make(chan struct{}, 1),
+		githubReconfigure: make(chan struct{}, 1),
+		refreshRequests:   make(chan refreshRequest),
+	}
+}
+
+// config returns the current config. The returned value must not be modified.
+func (p *Poller) config() *config.Config {
+	p.cfgMu.RLock()
+	defer p.cfgMu.RUnlock()
+	return p.cfg
+}
+
+// Reconfigure replaces the poller's config. The poll loops recreate their
+// tickers with the new intervals, and the local loop rescans immediately if
+// the scan path changed. Polls run on the loop goroutines, so a reconfigure
+// never overlaps a poll in progress.
+func (p *Poller) Reconfigure(cfg *config.Config) {
+	p.cfgMu.Lock()
+	scanPathChanged := p.cfg.ScanPath != cfg.ScanPath
+	p.cfg = cfg
+	p.cfgMu.Unlock()
+
+	if scanPathChanged {
+		p.rescanPending.Store(true)
+	}
+
+	for _, ch := range []chan struct{}{p.localReconfigure, p.githubReconfigure} {
+		select {
+		case ch <- struct{}{}:
+		default:
+			// A reconfigure is already pending
+		}
+	}
+}
+
+// LocalInterval returns the configured local poll interval.
+func (p *Poller) LocalInterval() time.Duration {
+	return time.Duration(p.config().LocalIntervalSeconds) * time.Second
+}
+
+// StateBackupInterval returns the configured state.json backup interval.
+// Zero means backups are disabled.
+func (p *Poller) StateBackupInterval() time.Duration {
+	return time.Duration(p.config().StateBackupIntervalMinutes) * time.Minute
+}
+
+// backupState takes a timestamped backup of state.json, keeping the
+// configured number of backups.
+func (p *Poller) backupState() {
+	keep := p.config().StateBackupKeep
+	if keep == 0 {
+		keep = config.DefaultStateBackupKeep
+	}
+	if err := cache.BackupState(keep); err != nil {
+		slog.Error("state backup failed", "err", err)
 	}
 }
 
+// GitHubInterval returns the configured GitHub poll interval.
+func (p *Poller) GitHubInterval() time.Duration {
+	return time.Duration(p.config().GitHubIntervalSeconds) * time.Second
+}
+
+// SetFetchers replaces the per-repo GitHub fetchers.
+func (p *Poller) SetFetchers(f Fetchers) {
+	p.fetchers = f
+}
+
 // Start starts both local and GitHub pollers.
 // It should be run in a separate goroutine.
 func (p *Poller) Start(ctx context.Context) {
+	p.bindContext(ctx)
+
 	// Load initial state from disk
 	if state, err := cache.ReadState(); err == nil {
+		p.stateMu.Lock()
 		p.state = state
+		p.stateMu.Unlock()
 	}
 
 	// Load initial cache and serve immediately
@@ -68,11 +231,19 @@ func (p *Poller) Start(ctx context.Context) {
 	go p.runHeartbeat(ctx)
 }
 
-// runLocalPoller runs the local scanner on a configurable interval.
+// runLocalPoller runs the local scanner on a configurable interval, and the
+// clone-state reconcile and state backup on their own intervals when
+// configured. All run on this goroutine so they never overlap.
 func (p *Poller) runLocalPoller(ctx context.Context) {
-	ticker := time.NewTicker(time.Duration(p.cfg.LocalIntervalSeconds) * time.Second)
+	ticker := time.NewTicker(p.LocalInterval())
 	defer ticker.Stop()
 
+	reconcile := newOptionalTicker(p.CloneStateInterval())
+	defer reconcile.Stop()
+
+	backups := newOptionalTicker(p.StateBackupInterval())
+	defer backups.Stop()
+
 	// First run immediately
 	p.localPoll(ctx)
 
@@ -82,54 +253,144 @@ func (p *Poller) runLocalPoller(ctx context.Context) {
 			return
 		case <-ticker.C:
 			p.localPoll(ctx)
+		case <-reconcile.C():
+			p.runReconcile()
+		case <-backups.C():
+			p.backupState()
+		case <-p.localReconfigure:
+			ticker.Reset(p.LocalInterval())
+			reconcile.Reset(p.CloneStateInterval())
+			backups.Reset(p.StateBackupInterval())
+			if p.rescanPending.Swap(false) {
+				p.localPoll(ctx)
+			}
 		}
 	}
 }
 
-// runGitHubPoller runs the GitHub scanner on a configurable interval.
+// runGitHubPoller runs the GitHub scanner on a configurable interval,
+// backing off while polls keep failing, and serves field refreshes
+// between polls.
 func (p *Poller) runGitHubPoller(ctx context.Context) {
-	ticker := time.NewTicker(time.Duration(p.cfg.GitHubIntervalSeconds) * time.Second)
+	ticker := time.NewTicker(p.GitHubInterval())
 	defer ticker.Stop()
 
 	// First run immediately
-	p.githubPoll(ctx)
+	ticker.Reset(p.githubPollOnce(ctx))
 
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			p.githubPoll(ctx)
+			ticker.Reset(p.githubPollOnce(ctx))
+		case <-p.githubReconfigure:
+			ticker.Reset(p.nextGitHubInterval())
+		case req := <-p.refreshRequests:
+			count, err := p.refreshField(req.ctx, req.field)
+			req.result <- refreshResult{count: count, err: err}
+		}
+	}
+}
+
+// githubPollOnce runs a GitHub poll, records its outcome for backoff, and
+// returns the delay before the next poll. A rate-limited poll broadcasts a
+// rate_limited event saying when polling will resume.
+func (p *Poller) githubPollOnce(ctx context.Context) time.Duration {
+	err := p.githubPoll(ctx)
+	p.recordGitHubResult(err)
+	next := p.nextGitHubInterval()
+
+	if scanner.IsGHRateLimit(err) {
+		data := map[string]interface{}{
+			"error":    "GitHub API rate limit exceeded.",
+			"resumeAt": time.Now().Add(next).UTC(),
+		}
+		if reset := scanner.RateLimitReset(err); !reset.IsZero() {
+			data["resetAt"] = reset
 		}
+		p.hub.Broadcast("rate_limited", data)
 	}
+
+	return next
 }
 
 // localPoll performs a single local poll cycle.
 func (p *Poller) localPoll(ctx context.Context) {
+	cfg := p.config()
 	// Discover local repos
-	localRepoNames, err := scanner.DiscoverLocalRepos(p.cfg.ScanPath)
+	localRepoNames, err := p.discoverLocalRepos(cfg)
 	if err != nil {
-		log.Printf("local poll error: %v", err)
+		slog.Error("local poll failed", "err", err)
+		p.setLastLocalPollError(err)
+		if reason := scanner.ScanPathErrorReason(err); reason != "" {
+			p.hub.Broadcast("scan_path_error", map[string]string{
+				"path":   cfg.ScanPath,
+				"reason": reason,
+				"error":  err.Error(),
+			})
+		}
 		return
 	}
 
-	// Build local repo map
+	// Build local repo map. Owners aren't known until the merge, so
+	// TrackedRepos is matched by name here and fully by trackedOnly.
 	localRepos := make(map[string]scanner.LocalRepo)
+	var errs pollErrors
 	for _, name := range localRepoNames {
-		clonedMap := scanner.FindClonedRepos([]string{name}, p.cfg.ScanPath)
+		if !cfg.IsTracked("", name) {
+			continue
+		}
+		clonedMap := scanner.FindClonedRepos([]string{name}, cfg.ScanPath)
 		if path, ok := clonedMap[name]; ok {
-			branch, dirty, lastCommit, err := scanner.GetGitState(path)
+			status, err := scanner.GetGitStatus(path)
 			if err != nil {
-				log.Printf("error getting git state for %s: %v", name, err)
+				slog.Warn("reading git state failed", "repo", name, "err", err)
+				errs.add(name, "git_status", err)
 				continue
 			}
-			localRepos[name] = scanner.LocalRepo{
+			localRepo := scanner.LocalRepo{
 				Name:       name,
 				Path:       path,
-				Branch:     branch,
-				Dirty:      dirty,
-				LastCommit: lastCommit,
+				Branch:     status.Branch,
+				Dirty:      status.Dirty(),
+				LastCommit: status.LastCommit,
+				Untracked:  status.Untracked,
+				Modified:   status.Modified,
+				Staged:     status.Staged,
+			}
+			ahead, behind, err := scanner.GetAheadBehind(path)
+			if err != nil {
+				slog.Warn("reading ahead/behind failed", "repo", name, "err", err)
+				errs.add(name, "ahead_behind", err)
+			}
+			localRepo.Ahead = ahead
+			localRepo.Behind = behind
+			if cfg.AllowNetworkGit {
+				unpushed, err := scanner.GetUnpushedTags(path)
+				if err != nil {
+					slog.Warn("reading unpushed tags failed", "repo", name, "err", err)
+					errs.add(name, "unpushed_tags", err)
+				}
+				localRepo.UnpushedTags = unpushed
 			}
+			if cfg.MatchLocalByContent {
+				githubName, err := scanner.ReadRepoIdentity(path)
+				if err != nil {
+					slog.Warn("reading repo identity failed", "repo", name, "err", err)
+					errs.add(name, "identity", err)
+				}
+				localRepo.GitHubName = githubName
+			}
+			project, exists, err := scanner.ReadProjectFile(path)
+			if err != nil {
+				slog.Warn("reading project file failed", "repo", name, "err", err)
+				errs.add(name, "project", err)
+			}
+			localRepo.HasProjectFile = exists
+			localRepo.Project = project
+			localRepo.FilePresence = scanner.LocalFilePresence(path)
+			localRepos[name] = localRepo
 		}
 	}
 
@@ -144,16 +405,48 @@ func (p *Poller) localPoll(ctx context.Context) {
 				topics = append(topics, scanner.RepositoryTopic{Name: t})
 			}
 			ghRepo := scanner.GitHubRepo{
-				Name:        repo.Name,
-				Description: repo.Description,
-				Visibility:  string(repo.Visibility),
-				HomepageURL: repo.HomepageURL,
-				Topics:      topics,
-				PushedAt:    repo.GitHubLastPush.Format(time.RFC3339),
+				Name:                  repo.Name,
+				Owner:                 repo.Owner,
+				Description:           repo.Description,
+				Visibility:            string(repo.Visibility),
+				HomepageURL:           repo.HomepageURL,
+				Topics:                topics,
+				CreatedAt:             repo.CreatedAt.Format(time.RFC3339),
+				PushedAt:              repo.GitHubLastPush.Format(time.RFC3339),
+				OpenIssues:            repo.OpenIssues,
+				OpenPRs:               repo.OpenPRs,
+				ActionsStatus:         string(repo.ActionsStatus),
+				IsArchived:            repo.Archived,
+				IsFork:                repo.Fork,
+				StargazerCount:        repo.Stars,
+				ForkCount:             repo.Forks,
+				HasWikiEnabled:        repo.HasWiki,
+				HasDiscussionsEnabled: repo.HasDiscussions,
 			}
 			if repo.Language != "" {
 				ghRepo.PrimaryLanguage = &scanner.PrimaryLanguage{Name: repo.Language}
 			}
+			if repo.ProjectGroup != "" || repo.ProjectStatus != "" {
+				ghRepo.Project = &scanner.ProjectInfo{Group: repo.ProjectGroup, Status: repo.ProjectStatus}
+			}
+			// Caches written before DefaultBranch existed only have it as
+			// the Branch of uncloned repos.
+			defaultBranch := repo.DefaultBranch
+			if defaultBranch == "" && !repo.Cloned {
+				defaultBranch = repo.Branch
+			}
+			if defaultBranch != "" {
+				ghRepo.DefaultBranch = &scanner.DefaultBranch{Name: defaultBranch}
+			}
+			// Remote file presence; cloned repos override it with what's
+			// on disk during the merge.
+			ghRepo.FilePresence = &scanner.FilePresence{
+				HasREADME:      repo.Completeness.HasReadme,
+				HasLICENSE:     repo.Completeness.HasLicense,
+				HasCLAUDEmd:    repo.Completeness.HasClaudeMd,
+				HasAGENTSmd:    repo.Completeness.HasAgentsMd,
+				HasProjectJson: repo.Completeness.HasProjectJson,
+			}
 			if repo.LatestRelease != nil {
 				ghRepo.LatestRelease = &scanner.LatestRelease{
 					TagName:     repo.LatestRelease.TagName,
@@ -165,51 +458,81 @@ func (p *Poller) localPoll(ctx context.Context) {
 	}
 
 	// Merge data
-	thresholds := model.LifecycleThresholds{
-		StaleDays:     p.cfg.StaleDays,
-		AbandonedDays: p.cfg.AbandonedDays,
-	}
+	thresholds := p.lifecycleThresholds()
 
-	repos := scanner.Merge(localRepos, githubRepos, p.cfg.ScanPath, p.state, thresholds)
+	repos := p.merge(localRepos, githubRepos, cfg.ScanPath, thresholds)
+	repos = trackedOnly(cfg, repos)
+	p.updateAutoHide(repos, cfg.AutoHideAbandonedDays)
+	repos = p.dropRemoved(repos, func(name string) bool {
+		_, found := localRepos[name]
+		return found
+	})
 
-	// Detect changes and emit granular events
-	p.detectAndEmitChanges(repos, "local")
+	// Update cache before any broadcast about these repos
+	p.writeCache(repos)
 
-	// Update cache
-	if err := cache.WriteRepos(repos); err != nil {
-		log.Printf("error writing cache: %v", err)
-	}
+	// Detect changes and emit granular events
+	diff := p.detectAndEmitChanges(repos, "local")
 
 	// Broadcast update
-	p.hub.Broadcast("repos_updated", repos)
+	p.broadcastRepoChanges("repos_updated", repos, diff)
+	p.reportPollErrors("local", errs)
 
 	// Update previous repos and poll time
 	p.setPreviousRepos(repos)
 	p.setLastLocalPoll(time.Now())
+	p.setLastLocalPollError(nil)
+}
+
+// merge merges local and GitHub data with the repo state. It holds stateMu
+// while Merge reads the state, since user actions such as Snooze write it
+// from request goroutines while a poll runs.
+func (p *Poller) merge(localRepos map[string]scanner.LocalRepo, githubRepos []scanner.GitHubRepo, scanPath string, thresholds model.LifecycleThresholds) []model.Repo {
+	p.stateMu.RLock()
+	defer p.stateMu.RUnlock()
+	return scanner.Merge(localRepos, githubRepos, scanPath, p.state, thresholds)
+}
+
+// discoverLocalRepos lists the repos under the scan path, up to the
+// configured per-scan-path cap, logging when the cap cut discovery short.
+// Directories with a clone in progress hold a partial .git and are left
+// out until the clone finishes.
+func (p *Poller) discoverLocalRepos(cfg *config.Config) ([]string, error) {
+	names, truncated, err := scanner.DiscoverLocalReposLimit(cfg.ScanPath, cfg.MaxReposPerScanPath)
+	if truncated {
+		slog.Warn("discovery truncated", "scanPath", cfg.ScanPath, "maxRepos", cfg.MaxReposPerScanPath)
+	}
+	return slices.DeleteFunc(names, p.IsCloning), err
 }
 
 // githubPoll performs a single GitHub poll cycle.
-func (p *Poller) githubPoll(ctx context.Context) {
+// It returns an error only when the repo list could not be fetched;
+// per-repo fetch errors are logged and the poll continues.
+func (p *Poller) githubPoll(ctx context.Context) error {
+	cfg := p.config()
 	// List GitHub repos
-	githubRepos, err := scanner.ListGitHubRepos(p.cfg.GitHubOwner)
+	githubRepos, err := p.listGitHubRepos(cfg)
 	if err != nil {
 		if scanner.IsGHNotFound(err) {
-			log.Printf("gh CLI not found")
-			p.hub.Broadcast("error", map[string]string{
-				"type":  "gh_not_found",
-				"error": "gh CLI not found. Please install gh CLI.",
-			})
+			slog.Error("gh CLI not found")
+			p.reportGitHubError("gh_not_found", "gh CLI not found. Please install gh CLI.")
 		} else if scanner.IsGHAuthError(err) {
-			log.Printf("gh CLI not authenticated")
-			p.hub.Broadcast("error", map[string]string{
-				"type":  "gh_auth_error",
-				"error": "gh CLI not authenticated. Please run 'gh auth login'.",
-			})
+			slog.Error("gh CLI not authenticated")
+			p.reportGitHubError("gh_auth_error", "gh CLI not authenticated. Please run 'gh auth login'.")
+		} else if scanner.IsGHRateLimit(err) {
+			slog.Warn("github poll rate limited", "err", err)
 		} else {
-			log.Printf("github poll error: %v", err)
+			slog.Error("github poll failed", "err", err)
 		}
-		return
+		p.setLastGitHubPollError(err)
+		return err
 	}
+	p.lastGitHubErr = ""
+
+	// Skip repos TrackedRepos excludes before any per-repo fetches
+	githubRepos = slices.DeleteFunc(githubRepos, func(repo scanner.GitHubRepo) bool {
+		return !cfg.IsTracked(cmp.Or(repo.Owner, cfg.GitHubOwner), repo.Name)
+	})
 
 	// Get local data from cache
 	var localRepos map[string]scanner.LocalRepo
@@ -218,71 +541,264 @@ func (p *Poller) githubPoll(ctx context.Context) {
 		for _, repo := range cachedRepos {
 			if repo.Cloned {
 				localRepos[repo.Name] = scanner.LocalRepo{
-					Name:       repo.Name,
-					Path:       repo.LocalPath,
-					Branch:     repo.Branch,
-					Dirty:      repo.Dirty,
-					LastCommit: repo.LocalLastCommit,
+					Name:         repo.Name,
+					Path:         repo.LocalPath,
+					Branch:       repo.Branch,
+					Dirty:        repo.Dirty,
+					LastCommit:   repo.LocalLastCommit,
+					Untracked:    repo.Untracked,
+					Modified:     repo.Modified,
+					Staged:       repo.Staged,
+					Ahead:        repo.Ahead,
+					Behind:       repo.Behind,
+					UnpushedTags: repo.UnpushedTags,
+					FilePresence: scanner.LocalFilePresence(repo.LocalPath),
+				}
+				if repo.Completeness.HasProjectJson {
+					localRepo := localRepos[repo.Name]
+					localRepo.HasProjectFile = true
+					localRepo.Project = &scanner.ProjectInfo{Group: repo.ProjectGroup, Status: repo.ProjectStatus}
+					localRepos[repo.Name] = localRepo
 				}
 			}
 		}
 	}
 
 	// Fetch additional GitHub data for each repo
+	var errs pollErrors
 	for i := range githubRepos {
 		repo := &githubRepos[i]
+		owner := cmp.Or(repo.Owner, cfg.GitHubOwner)
 
 		// Get PR count
-		prCount, err := scanner.GetPROpenCount(p.cfg.GitHubOwner, repo.Name)
+		prCount, err := p.fetchers.PROpenCount(owner, repo.Name)
 		if err != nil {
-			log.Printf("error getting PRs for %s: %v", repo.Name, err)
+			slog.Warn("fetching PRs failed", "repo", repo.Name, "err", err)
+			errs.add(repo.Name, "prs", err)
 		}
 		repo.OpenPRs = prCount
 
+		// Get issue count
+		issueCount, err := p.fetchers.IssueOpenCount(owner, repo.Name)
+		if err != nil {
+			slog.Warn("fetching issues failed", "repo", repo.Name, "err", err)
+			errs.add(repo.Name, "issues", err)
+		}
+		repo.OpenIssues = issueCount
+
 		// Get Actions status
-		actionsStatus, err := scanner.GetActionsStatus(p.cfg.GitHubOwner, repo.Name)
+		actionsStatus, err := p.fetchers.ActionsStatus(owner, repo.Name)
 		if err != nil {
-			log.Printf("error getting Actions status for %s: %v", repo.Name, err)
+			slog.Warn("fetching Actions status failed", "repo", repo.Name, "err", err)
+			errs.add(repo.Name, "actions", err)
 		}
 		repo.ActionsStatus = actionsStatus
 
-		// Get file presence
-		filePresence, err := scanner.GetFilePresence(p.cfg.GitHubOwner, repo.Name)
-		if err != nil {
-			log.Printf("error getting file presence for %s: %v", repo.Name, err)
+		// Get file presence; cloned repos read it from disk in Merge instead
+		local, cloned := localRepos[repo.Name]
+		if !cloned || local.FilePresence == nil {
+			filePresence, err := p.fetchers.FilePresence(owner, repo.Name)
+			if err != nil {
+				slog.Warn("fetching file presence failed", "repo", repo.Name, "err", err)
+				errs.add(repo.Name, "files", err)
+			}
+			repo.FilePresence = filePresence
+		}
+
+		// Cloned repos read .project.json locally; others fetch it via gh
+		if !cloned && repo.FilePresence != nil && repo.FilePresence.HasProjectJson {
+			project, err := p.fetchers.ProjectFile(owner, repo.Name)
+			if err != nil {
+				slog.Warn("fetching project file failed", "repo", repo.Name, "err", err)
+				errs.add(repo.Name, "project", err)
+			}
+			repo.Project = project
 		}
-		repo.FilePresence = filePresence
 	}
 
 	// Merge data
-	thresholds := model.LifecycleThresholds{
-		StaleDays:     p.cfg.StaleDays,
-		AbandonedDays: p.cfg.AbandonedDays,
-	}
+	thresholds := p.lifecycleThresholds()
+
+	repos := p.merge(localRepos, githubRepos, cfg.ScanPath, thresholds)
+	repos = trackedOnly(cfg, repos)
+	p.updateAutoHide(repos, cfg.AutoHideAbandonedDays)
+	repos = p.dropRemoved(repos, func(name string) bool {
+		return slices.ContainsFunc(githubRepos, func(repo scanner.GitHubRepo) bool { return repo.Name == name })
+	})
 
-	repos := scanner.Merge(localRepos, githubRepos, p.cfg.ScanPath, p.state, thresholds)
+	// Update cache before any broadcast about these repos
+	p.writeCache(repos)
 
 	// Detect changes and emit granular events
-	p.detectAndEmitChanges(repos, "github")
+	diff := p.detectAndEmitChanges(repos, "github")
 
 	// Update state with new release tags
 	p.updateReleaseState(repos)
 
-	// Update cache
-	if err := cache.WriteRepos(repos); err != nil {
-		log.Printf("error writing cache: %v", err)
-	}
-
 	// Broadcast update
-	p.hub.Broadcast("github_updated", repos)
+	p.broadcastRepoChanges("github_updated", repos, diff)
+	p.reportPollErrors("github", errs)
 
 	// Update previous repos and poll time
 	p.setPreviousRepos(repos)
 	p.setLastGitHubPoll(time.Now())
+	p.setLastGitHubPollError(nil)
+	return nil
+}
+
+// listGitHubRepos lists the configured owner's repos and, with
+// IncludeAllOrgs, the repos of every org the user belongs to, with Owner set
+// to the org. Repos are keyed by name, so an org repo whose name is already
+// listed is skipped. An org that can't be listed is skipped, but a rate limit
+// fails the whole listing so the poll backs off until the limit resets
+// rather than spending what remains of it on further orgs.
+func (p *Poller) listGitHubRepos(cfg *config.Config) ([]scanner.GitHubRepo, error) {
+	repos, err := p.fetchers.ListRepos(cfg.GitHubOwner)
+	if err != nil || !cfg.IncludeAllOrgs {
+		return repos, err
+	}
+
+	orgs, err := p.fetchers.ListOrgs()
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(repos))
+	for _, repo := range repos {
+		seen[repo.Name] = true
+	}
+	for _, org := range orgs {
+		if strings.EqualFold(org, cfg.GitHubOwner) {
+			continue
+		}
+		orgRepos, err := p.fetchers.ListRepos(org)
+		if err != nil {
+			if scanner.IsGHRateLimit(err) {
+				return nil, err
+			}
+			slog.Warn("skipping org", "org", org, "err", err)
+			continue
+		}
+		for _, repo := range orgRepos {
+			if seen[repo.Name] {
+				slog.Warn("skipping repo with a name already listed", "org", org, "repo", repo.Name)
+				continue
+			}
+			seen[repo.Name] = true
+			repo.Owner = org
+			repos = append(repos, repo)
+		}
+	}
+	return repos, nil
+}
+
+// trackedOnly drops repos that TrackedRepos excludes. Repos without an
+// owner belong to the configured GitHubOwner.
+func trackedOnly(cfg *config.Config, repos []model.Repo) []model.Repo {
+	return slices.DeleteFunc(repos, func(repo model.Repo) bool {
+		return !cfg.IsTracked(cmp.Or(repo.Owner, cfg.GitHubOwner), repo.Name)
+	})
+}
+
+// writeCache writes the full repo list to cache.json.
+//
+// Ordering guarantee: every poll writes the cache before broadcasting any
+// event derived from the same data, so a client that reacts to an event by
+// calling GET /api/repos sees data at least as new as the event.
+func (p *Poller) writeCache(repos []model.Repo) {
+	if err := cache.WriteRepos(repos); err != nil {
+		slog.Error("writing cache failed", "err", err)
+	}
 }
 
-// detectAndEmitChanges compares new repos with previous and emits granular events.
-func (p *Poller) detectAndEmitChanges(newRepos []model.Repo, source string) {
+// broadcastRepos broadcasts a full repo list snapshot.
+// Callers must write the cache first (see writeCache).
+//
+// With a coalescing window configured, snapshots are delayed by the window
+// and only the latest one is sent, so bursts of polls produce one event.
+func (p *Poller) broadcastRepos(eventType string, repos []model.Repo) {
+	window := time.Duration(p.config().CoalesceMillis) * time.Millisecond
+	if window <= 0 {
+		p.hub.Broadcast(eventType, repos)
+		return
+	}
+
+	p.pendingMu.Lock()
+	defer p.pendingMu.Unlock()
+
+	p.pendingType = eventType
+	p.pendingRepos = repos
+	if p.pendingTimer == nil {
+		p.pendingTimer = time.AfterFunc(window, p.flushPending)
+	}
+}
+
+// flushPending broadcasts the latest coalesced snapshot.
+func (p *Poller) flushPending() {
+	p.pendingMu.Lock()
+	eventType, repos := p.pendingType, p.pendingRepos
+	p.pendingType, p.pendingRepos, p.pendingTimer = "", nil, nil
+	p.pendingMu.Unlock()
+
+	if eventType != "" {
+		p.hub.Broadcast(eventType, repos)
+	}
+}
+
+// maxRepoChangedEvents caps how many repo_changed events one poll sends.
+// A poll changing more repos sends the full list instead, so the events
+// can't overflow a client's buffer.
+const maxRepoChangedEvents = 5
+
+// repoDiff is what a poll changed relative to the previous repo list.
+type repoDiff struct {
+	// changed holds the repos present before and after whose data differs
+	changed []model.Repo
+	// setChanged reports that repos were added or removed
+	setChanged bool
+}
+
+// broadcastRepoChanges broadcasts a poll's result. Each changed repo is sent
+// as its own repo_changed event, so clients don't re-render the whole list
+// for one field. The full list is broadcast as eventType instead when repos
+// were added or removed, when more than maxRepoChangedEvents changed, or
+// when a coalesced snapshot is pending and would otherwise be sent after
+// the newer per-repo events. Callers must write the cache first (see
+// writeCache).
+func (p *Poller) broadcastRepoChanges(eventType string, repos []model.Repo, diff repoDiff) {
+	p.pendingMu.Lock()
+	pending := p.pendingTimer != nil
+	p.pendingMu.Unlock()
+
+	if diff.setChanged || pending || len(diff.changed) > maxRepoChangedEvents {
+		p.broadcastRepos(eventType, repos)
+		return
+	}
+	for _, repo := range diff.changed {
+		p.hub.Broadcast("repo_changed", repo)
+	}
+}
+
+// repoChanged reports whether a repo's data differs between two polls as
+// clients see it. Comparing the JSON treats nil and empty slices alike.
+func repoChanged(prev, next model.Repo) bool {
+	prevJSON, err := json.Marshal(prev)
+	if err != nil {
+		return true
+	}
+	nextJSON, err := json.Marshal(next)
+	if err != nil {
+		return true
+	}
+	return !bytes.Equal(prevJSON, nextJSON)
+}
+
+// detectAndEmitChanges compares new repos with previous and emits a single
+// "changes" event listing every change found in this cycle. Batching keeps a
+// first poll with hundreds of changes from overflowing the hub's buffer.
+// It returns the diff for broadcastRepoChanges.
+func (p *Poller) detectAndEmitChanges(newRepos []model.Repo, source string) repoDiff {
+	cfg := p.config()
 	previousRepos := p.getPreviousRepos()
 
 	// Build previous repo map
@@ -292,34 +808,38 @@ func (p *Poller) detectAndEmitChanges(newRepos []model.Repo, source string) {
 	}
 
 	// Check for changes
+	diff := repoDiff{setChanged: previousRepos == nil || len(prevMap) != len(newRepos)}
+	var changes []map[string]interface{}
 	for _, newRepo := range newRepos {
 		prevRepo, ok := prevMap[newRepo.Name]
 		if !ok {
+			diff.setChanged = true
 			continue
 		}
+		if repoChanged(prevRepo, newRepo) {
+			diff.changed = append(diff.changed, newRepo)
+		}
 
 		// Check for Actions status change
 		if prevRepo.ActionsStatus != newRepo.ActionsStatus {
-			if p.cfg.Notifications.ActionsChanged {
-				p.sendNotification("actions_changed", newRepo.Name, formatActionsStatusChange(newRepo.ActionsStatus))
+			if cfg.Notifications.ActionsChanged && !p.actionsStatusAcked(newRepo.Name, newRepo.ActionsStatus) {
+				p.sendNotification("actions_changed", newRepo.Owner, newRepo.Name, formatActionsStatusChange(newRepo.ActionsStatus))
 			}
-			p.hub.Broadcast("actions_changed", map[string]interface{}{
-				"repo":        newRepo.Name,
-				"oldStatus":   prevRepo.ActionsStatus,
-				"newStatus":   newRepo.ActionsStatus,
+			changes = append(changes, map[string]interface{}{
+				"type":      "actions_changed",
+				"repo":      newRepo.Name,
+				"oldStatus": prevRepo.ActionsStatus,
+				"newStatus": newRepo.ActionsStatus,
 			})
 		}
 
 		// Check for new release
-		if newRepo.NewRelease {
-			if p.cfg.Notifications.NewRelease {
-				releaseName := "unknown"
-				if newRepo.LatestRelease != nil {
-					releaseName = newRepo.LatestRelease.TagName
-				}
-				p.sendNotification("new_release", newRepo.Name, releaseName)
+		if newRepo.NewRelease && newRepo.LatestRelease != nil {
+			if cfg.Notifications.NewRelease {
+				p.sendNotification("new_release", newRepo.Owner, newRepo.Name, newRepo.LatestRelease.TagName)
 			}
-			p.hub.Broadcast("new_release", map[string]interface{}{
+			changes = append(changes, map[string]interface{}{
+				"type":     "new_release",
 				"repo":     newRepo.Name,
 				"tagName":  newRepo.LatestRelease.TagName,
 				"released": newRepo.LatestRelease.PublishedAt,
@@ -328,16 +848,75 @@ func (p *Poller) detectAndEmitChanges(newRepos []model.Repo, source string) {
 
 		// Check for opened PRs
 		if newRepo.OpenPRs > prevRepo.OpenPRs {
-			if p.cfg.Notifications.PROpened {
-				p.sendNotification("pr_opened", newRepo.Name, fmt.Sprintf("%d open", newRepo.OpenPRs))
+			if cfg.Notifications.PROpened {
+				p.sendNotification("pr_opened", newRepo.Owner, newRepo.Name, fmt.Sprintf("%d open", newRepo.OpenPRs))
 			}
-			p.hub.Broadcast("pr_opened", map[string]interface{}{
+			changes = append(changes, map[string]interface{}{
+				"type":     "pr_opened",
 				"repo":     newRepo.Name,
 				"oldCount": prevRepo.OpenPRs,
 				"newCount": newRepo.OpenPRs,
 			})
 		}
 	}
+
+	if len(changes) > 0 {
+		p.hub.Broadcast("changes", changes)
+	}
+	return diff
+}
+
+// actionsStatusAcked reports whether status is the repo's acknowledged
+// Actions status. A repo that recovers to passing has its acknowledgement
+// cleared, so a later failure notifies again.
+func (p *Poller) actionsStatusAcked(name string, status model.ActionsStatus) bool {
+	p.stateMu.Lock()
+	defer p.stateMu.Unlock()
+
+	entry := p.state[name]
+	if entry == nil || entry.AckedActionsStatus == "" {
+		return false
+	}
+	acked := entry.AckedActionsStatus == string(status)
+	if status == model.ActionsStatusPassing {
+		entry.AckedActionsStatus = ""
+		if err := cache.WriteState(p.state); err != nil {
+			slog.Error("writing state failed", "err", err)
+		}
+	}
+	return acked
+}
+
+// AcknowledgeAllActions records each cached repo's current Actions status
+// as acknowledged, so notifications resume only when a status changes to
+// something else. Returns the number of repos acknowledged.
+func (p *Poller) AcknowledgeAllActions() (int, error) {
+	repos, err := cache.ReadRepos()
+	if err != nil {
+		return 0, fmt.Errorf("reading cache: %w", err)
+	}
+
+	p.stateMu.Lock()
+	defer p.stateMu.Unlock()
+
+	if p.state == nil {
+		p.state = make(cache.RepoState)
+	}
+	acked := 0
+	for _, repo := range repos {
+		if repo.ActionsStatus == "" {
+			continue
+		}
+		if p.state[repo.Name] == nil {
+			p.state[repo.Name] = &cache.RepoStateEntry{}
+		}
+		p.state[repo.Name].AckedActionsStatus = string(repo.ActionsStatus)
+		acked++
+	}
+	if err := cache.WriteState(p.state); err != nil {
+		return 0, fmt.Errorf("writing state: %w", err)
+	}
+	return acked, nil
 }
 
 // updateReleaseState updates the state with new release tags.
@@ -360,18 +939,439 @@ func (p *Poller) updateReleaseState(repos []model.Repo) {
 
 	// Save state
 	if err := cache.WriteState(p.state); err != nil {
-		log.Printf("error writing state: %v", err)
+		slog.Error("writing state failed", "err", err)
+	}
+}
+
+// updateAutoHide hides repos that qualify under ComputeAutoHide and unhides
+// those that no longer do, such as after a clone or a new push, recording
+// the change in state. Each repo's Hidden flag is set to match, or kept set
+// when the user hid the repo.
+func (p *Poller) updateAutoHide(repos []model.Repo, days int) {
+	p.stateMu.Lock()
+	defer p.stateMu.Unlock()
+
+	if p.state == nil {
+		p.state = make(cache.RepoState)
+	}
+
+	now := time.Now()
+	changed := false
+	for i := range repos {
+		hide := repos[i].ComputeAutoHideAt(days, now)
+		entry := p.state[repos[i].Name]
+		repos[i].Hidden = hide || (entry != nil && entry.Hidden)
+
+		hidden := entry != nil && !entry.AutoHiddenAt.IsZero()
+		switch {
+		case hide && !hidden:
+			if entry == nil {
+				entry = &cache.RepoStateEntry{}
+				p.state[repos[i].Name] = entry
+			}
+			entry.AutoHiddenAt = now.UTC()
+			changed = true
+		case !hide && hidden:
+			entry.AutoHiddenAt = time.Time{}
+			changed = true
+		}
+	}
+
+	if changed {
+		if err := cache.WriteState(p.state); err != nil {
+			slog.Error("writing state failed", "err", err)
+		}
+	}
+}
+
+// sendNotification delivers a notification to the desktop, the webhook,
+// or both, depending on the configured NotificationMode.
+// Notifications for snoozed repos, or after the poller's context is
+// cancelled, are suppressed. owner is the org the repo was listed from, or
+// "" for the configured owner.
+func (p *Poller) sendNotification(eventType, owner, repo, message string) {
+	if p.stopped() || p.isSnoozed(repo, time.Now()) {
+		return
+	}
+
+	cfg := p.config()
+	mode := cfg.NotificationMode
+	if mode != config.NotificationModeWebhook {
+		p.notify(eventType, repo, message, NotificationURL(cfg, owner, repo))
+	}
+	if (mode == config.NotificationModeWebhook || mode == config.NotificationModeBoth) && cfg.WebhookURL != "" {
+		// Log but don't fail — notification failures are non-critical
+		if err := SendWebhook(cfg.WebhookURL, eventType, repo, message); err != nil {
+			slog.Warn("webhook failed", "repo", repo, "err", err)
+		}
+	}
+}
+
+// bindContext records the poller's run context. Notifications stop as soon
+// as it is cancelled, so none start during shutdown.
+func (p *Poller) bindContext(ctx context.Context) {
+	p.runCtx.Store(&ctx)
+}
+
+// stopped reports whether the poller's run context has been cancelled.
+func (p *Poller) stopped() bool {
+	ctx := p.runCtx.Load()
+	return ctx != nil && (*ctx).Err() != nil
+}
+
+// reportGitHubError broadcasts a GitHub poll error and, when error
+// notifications are enabled, sends a notification the first time the error
+// occurs. A repeat of the same error on later cycles is only broadcast.
+func (p *Poller) reportGitHubError(errType, message string) {
+	p.hub.Broadcast("error", map[string]string{
+		"type":  errType,
+		"error": message,
+	})
+
+	if errType == p.lastGitHubErr {
+		return
+	}
+	p.lastGitHubErr = errType
+	if p.config().Notifications.Error {
+		p.sendNotification("error", "", "", message)
+	}
+}
+
+// RepoPollError is a per-repo failure in one poll cycle, broadcast in the
+// poll_errors event.
+type RepoPollError struct {
+	Repo  string `json:"repo"`
+	Stage string `json:"stage"`
+	Error string `json:"error"`
+}
+
+// pollErrors collects the per-repo failures of one poll cycle.
+type pollErrors []RepoPollError
+
+// add records err for repo at stage.
+func (e *pollErrors) add(repo, stage string, err error) {
+	*e = append(*e, RepoPollError{Repo: repo, Stage: stage, Error: err.Error()})
+}
+
+// reportPollErrors broadcasts a poll_errors event listing a cycle's
+// per-repo failures. A clean cycle broadcasts an empty list only when the
+// source's previous cycle had errors, so clients can clear them.
+func (p *Poller) reportPollErrors(source string, errs pollErrors) {
+	p.pollErrorsMu.Lock()
+	hadErrors := p.pollErrorSources[source]
+	if p.pollErrorSources == nil {
+		p.pollErrorSources = make(map[string]bool)
+	}
+	p.pollErrorSources[source] = len(errs) > 0
+	p.pollErrorsMu.Unlock()
+
+	if len(errs) == 0 && !hadErrors {
+		return
+	}
+	if errs == nil {
+		errs = pollErrors{}
+	}
+	p.hub.Broadcast("poll_errors", map[string]interface{}{
+		"source": source,
+		"errors": []RepoPollError(errs),
+	})
+}
+
+// NotifyCloneCompleted sends a clone_completed notification for repo, listed
+// from owner ("" for the configured owner), when clone notifications are
+// enabled.
+func (p *Poller) NotifyCloneCompleted(owner, repo string) {
+	if p.config().Notifications.CloneCompleted {
+		p.sendNotification("clone_completed", owner, repo, "Clone completed")
+	}
+}
+
+// SetNotifyFunc replaces the function used to deliver notifications.
+func (p *Poller) SetNotifyFunc(f func(eventType, repo, message, url string)) {
+	p.notify = f
+}
+
+// Snooze suppresses notifications for a repo until the given time.
+// A zero time clears the snooze. The repo stays in the list either way.
+func (p *Poller) Snooze(name string, until time.Time) error {
+	p.stateMu.Lock()
+	if p.state == nil {
+		p.state = make(cache.RepoState)
+	}
+	if p.state[name] == nil {
+		p.state[name] = &cache.RepoStateEntry{}
+	}
+	p.state[name].SnoozeUntil = until
+	err := cache.WriteState(p.state)
+	p.stateMu.Unlock()
+	if err != nil {
+		return fmt.Errorf("writing state: %w", err)
+	}
+
+	// Reflect the snooze in the cached repo so the UI sees it before the next poll
+	repos, err := cache.ReadRepos()
+	if err != nil {
+		return fmt.Errorf("reading cache: %w", err)
+	}
+	for i := range repos {
+		if repos[i].Name == name {
+			repos[i].SnoozeUntil = until
+			if err := cache.UpdateRepo(repos[i]); err != nil {
+				return fmt.Errorf("writing cache: %w", err)
+			}
+			p.broadcastRepos("repos_updated", repos)
+			break
+		}
 	}
+
+	return nil
 }
 
-// sendNotification sends a macOS notification.
-func (p *Poller) sendNotification(eventType, repo, message string) {
-	SendNotification(eventType, repo, message)
+// SetHidden hides a repo from the default repo list, or unhides it. A repo
+// that is also auto-hidden stays hidden until it no longer qualifies.
+func (p *Poller) SetHidden(name string, hidden bool) error {
+	p.stateMu.Lock()
+	if p.state == nil {
+		p.state = make(cache.RepoState)
+	}
+	if p.state[name] == nil {
+		p.state[name] = &cache.RepoStateEntry{}
+	}
+	entry := p.state[name]
+	entry.Hidden = hidden
+	effective := hidden || !entry.AutoHiddenAt.IsZero()
+	err := cache.WriteState(p.state)
+	p.stateMu.Unlock()
+	if err != nil {
+		return fmt.Errorf("writing state: %w", err)
+	}
+
+	// Reflect the change in the cached repo so the UI sees it before the next poll
+	repos, err := cache.ReadRepos()
+	if err != nil {
+		return fmt.Errorf("reading cache: %w", err)
+	}
+	for i := range repos {
+		if repos[i].Name == name {
+			repos[i].Hidden = effective
+			if err := cache.UpdateRepo(repos[i]); err != nil {
+				return fmt.Errorf("writing cache: %w", err)
+			}
+			p.broadcastRepos("repos_updated", repos)
+			break
+		}
+	}
+
+	return nil
 }
 
-// runHeartbeat sends a comment every 30 seconds to keep SSE connections alive.
+// SetAnnotations replaces a repo's user tags and note. Empty values clear
+// them.
+func (p *Poller) SetAnnotations(name string, tags []string, note string) error {
+	p.stateMu.Lock()
+	if p.state == nil {
+		p.state = make(cache.RepoState)
+	}
+	if p.state[name] == nil {
+		p.state[name] = &cache.RepoStateEntry{}
+	}
+	p.state[name].Tags = tags
+	p.state[name].Note = note
+	err := cache.WriteState(p.state)
+	p.stateMu.Unlock()
+	if err != nil {
+		return fmt.Errorf("writing state: %w", err)
+	}
+
+	// Reflect the annotations in the cached repo so the UI sees them before the next poll
+	repos, err := cache.ReadRepos()
+	if err != nil {
+		return fmt.Errorf("reading cache: %w", err)
+	}
+	for i := range repos {
+		if repos[i].Name == name {
+			repos[i].Tags = tags
+			repos[i].Note = note
+			if err := cache.UpdateRepo(repos[i]); err != nil {
+				return fmt.Errorf("writing cache: %w", err)
+			}
+			p.broadcastRepos("repos_updated", repos)
+			break
+		}
+	}
+
+	return nil
+}
+
+// MarkArchived records that a repo was archived on GitHub so the cache and
+// UI reflect it before the next GitHub poll.
+func (p *Poller) MarkArchived(name string) error {
+	repos, err := cache.ReadRepos()
+	if err != nil {
+		return fmt.Errorf("reading cache: %w", err)
+	}
+	for i := range repos {
+		if repos[i].Name == name {
+			repos[i].Archived = true
+			repos[i].Lifecycle = repos[i].ComputeLifecycle(p.lifecycleThresholds())
+			repos[i].SuggestArchive = repos[i].ComputeSuggestArchive()
+			if err := cache.UpdateRepo(repos[i]); err != nil {
+				return fmt.Errorf("writing cache: %w", err)
+			}
+			p.broadcastRepos("repos_updated", repos)
+			break
+		}
+	}
+
+	return nil
+}
+
+// RemoveRepo removes a repo from the cache and broadcasts repo_removed.
+// A repo that still exists on disk or on GitHub reappears on the next poll
+// that sees it. Returns false if the repo isn't cached.
+func (p *Poller) RemoveRepo(name string) (bool, error) {
+	repos, err := cache.ReadRepos()
+	if err != nil {
+		return false, fmt.Errorf("reading cache: %w", err)
+	}
+	i := slices.IndexFunc(repos, func(repo model.Repo) bool { return repo.Name == name })
+	if i < 0 {
+		return false, nil
+	}
+
+	p.removedMu.Lock()
+	if p.removed == nil {
+		p.removed = make(map[string]bool)
+	}
+	p.removed[name] = true
+	p.removedMu.Unlock()
+
+	if err := cache.RemoveRepo(name); err != nil {
+		return false, fmt.Errorf("writing cache: %w", err)
+	}
+	repos = slices.Delete(repos, i, i+1)
+
+	p.hub.Broadcast("repo_removed", map[string]string{"repo": name})
+	p.setPreviousRepos(repos)
+
+	return true, nil
+}
+
+// dropRemoved filters repos the user removed out of a poll result, unless
+// inSource reports that the poll's own source still has them. A repo seen
+// again is no longer treated as removed.
+func (p *Poller) dropRemoved(repos []model.Repo, inSource func(name string) bool) []model.Repo {
+	p.removedMu.Lock()
+	defer p.removedMu.Unlock()
+
+	if len(p.removed) == 0 {
+		return repos
+	}
+	return slices.DeleteFunc(repos, func(repo model.Repo) bool {
+		if !p.removed[repo.Name] {
+			return false
+		}
+		if inSource(repo.Name) {
+			delete(p.removed, repo.Name)
+			return false
+		}
+		return true
+	})
+}
+
+// RepoState returns a copy of the persisted user state for a repo,
+// or nil if the repo has none.
+func (p *Poller) RepoState(name string) *cache.RepoStateEntry {
+	p.stateMu.RLock()
+	defer p.stateMu.RUnlock()
+
+	entry := p.state[name]
+	if entry == nil {
+		return nil
+	}
+	copied := *entry
+	return &copied
+}
+
+// ImportRepo replaces a repo's cached data and persisted user state, adding
+// the repo if it isn't cached yet, then broadcasts the updated list.
+// Derived fields are recomputed under the current config. A nil state
+// leaves the repo's existing state untouched.
+func (p *Poller) ImportRepo(repo model.Repo, state *cache.RepoStateEntry) error {
+	if state != nil {
+		p.stateMu.Lock()
+		if p.state == nil {
+			p.state = make(cache.RepoState)
+		}
+		copied := *state
+		p.state[repo.Name] = &copied
+		err := cache.WriteState(p.state)
+		p.stateMu.Unlock()
+		if err != nil {
+			return fmt.Errorf("writing state: %w", err)
+		}
+		repo.SnoozeUntil = state.SnoozeUntil
+		repo.Tags = state.Tags
+		repo.Note = state.Note
+	}
+
+	thresholds := p.lifecycleThresholds()
+	repo.Lifecycle = repo.ComputeLifecycle(thresholds)
+	repo.NeedsAttention = repo.ComputeNeedsAttention()
+	repo.Completeness.Score = repo.CompletenessScore()
+	repo.SuggestArchive = repo.ComputeSuggestArchive()
+	repo.IsNew = repo.ComputeIsNew(thresholds.NewRepoDays)
+
+	if err := cache.UpdateRepo(repo); err != nil {
+		return fmt.Errorf("writing cache: %w", err)
+	}
+	repos, err := cache.ReadRepos()
+	if err != nil {
+		return fmt.Errorf("reading cache: %w", err)
+	}
+	p.broadcastRepos("repos_updated", repos)
+	p.setPreviousRepos(repos)
+
+	return nil
+}
+
+// isSnoozed reports whether a repo's notifications are snoozed at now.
+func (p *Poller) isSnoozed(name string, now time.Time) bool {
+	p.stateMu.RLock()
+	defer p.stateMu.RUnlock()
+
+	entry, ok := p.state[name]
+	return ok && entry != nil && now.Before(entry.SnoozeUntil)
+}
+
+// lifecycleThresholds returns the lifecycle rules from the config.
+func (p *Poller) lifecycleThresholds() model.LifecycleThresholds {
+	cfg := p.config()
+	newRepoDays := cfg.NewRepoDays
+	if newRepoDays <= 0 {
+		newRepoDays = config.DefaultNewRepoDays
+	}
+	return model.LifecycleThresholds{
+		StaleDays:      cfg.StaleDays,
+		AbandonedDays:  cfg.AbandonedDays,
+		DirtyIsOngoing: cfg.DirtyIsOngoing,
+		NewRepoDays:    newRepoDays,
+	}
+}
+
+// HeartbeatInterval returns the configured SSE heartbeat interval.
+// Falls back to config.DefaultHeartbeatSeconds when unset.
+func (p *Poller) HeartbeatInterval() time.Duration {
+	seconds := p.config().HeartbeatSeconds
+	if seconds <= 0 {
+		seconds = config.DefaultHeartbeatSeconds
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// runHeartbeat sends a heartbeat on the configured interval to keep SSE connections alive.
 func (p *Poller) runHeartbeat(ctx context.Context) {
-	ticker := time.NewTicker(30 * time.Second)
+	ticker := time.NewTicker(p.HeartbeatInterval())
 	defer ticker.Stop()
 
 	for {
@@ -380,7 +1380,7 @@ func (p *Poller) runHeartbeat(ctx context.Context) {
 			return
 		case <-ticker.C:
 			// Send a comment as heartbeat
-			p.hub.Broadcast("heartbeat", map[string]string{"time": time.Now().Format(time.RFC3339)})
+			p.hub.Heartbeat()
 		}
 	}
 }
@@ -413,6 +1413,50 @@ func (p *Poller) setLastGitHubPoll(t time.Time) {
 	p.lastGitHubPoll = t
 }
 
+// PollError is the most recent failure of a poll.
+type PollError struct {
+	Error string    `json:"Error"`
+	At    time.Time `json:"At"`
+}
+
+// newPollError records err as failing now, or returns nil for a nil err.
+func newPollError(err error) *PollError {
+	if err == nil {
+		return nil
+	}
+	return &PollError{Error: err.Error(), At: time.Now().UTC()}
+}
+
+// GetLastLocalPollError returns the last local poll failure, or nil if
+// the last local poll succeeded.
+func (p *Poller) GetLastLocalPollError() *PollError {
+	p.lastLocalPollMu.RLock()
+	defer p.lastLocalPollMu.RUnlock()
+	return p.lastLocalPollErr
+}
+
+// GetLastGitHubPollError returns the last GitHub poll failure, or nil if
+// the last GitHub poll succeeded.
+func (p *Poller) GetLastGitHubPollError() *PollError {
+	p.lastGitHubPollMu.RLock()
+	defer p.lastGitHubPollMu.RUnlock()
+	return p.lastGitHubPollErr
+}
+
+// setLastLocalPollError records a local poll failure; nil clears it.
+func (p *Poller) setLastLocalPollError(err error) {
+	p.lastLocalPollMu.Lock()
+	defer p.lastLocalPollMu.Unlock()
+	p.lastLocalPollErr = newPollError(err)
+}
+
+// setLastGitHubPollError records a GitHub poll failure; nil clears it.
+func (p *Poller) setLastGitHubPollError(err error) {
+	p.lastGitHubPollMu.Lock()
+	defer p.lastGitHubPollMu.Unlock()
+	p.lastGitHubPollErr = newPollError(err)
+}
+
 // setPreviousRepos sets the previous repo list for change detection.
 func (p *Poller) setPreviousRepos(repos []model.Repo) {
 	p.previousReposMu.Lock()
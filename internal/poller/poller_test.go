@@ -2,12 +2,25 @@ package poller_test
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/alexcatdad/catscan/internal/cache"
 	"github.com/alexcatdad/catscan/internal/config"
 	"github.com/alexcatdad/catscan/internal/model"
 	"github.com/alexcatdad/catscan/internal/poller"
+	"github.com/alexcatdad/catscan/internal/scanner"
 	"github.com/alexcatdad/catscan/internal/sse"
 )
 
@@ -215,8 +228,1789 @@ func TestChangeDetectionPRDecreaseDoesNotEmit(t *testing.T) {
 	}
 }
 
+// TestChangeDetectionNewReleaseWithoutRelease tests that a repo flagged
+// NewRelease without LatestRelease neither panics nor emits new_release,
+// and that its other changes are still emitted.
+func TestChangeDetectionNewReleaseWithoutRelease(t *testing.T) {
+	hub := sse.NewHub()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go hub.Run(ctx)
+
+	client := &sse.Client{ID: "reader", Chan: make(chan sse.Event, 10), Ctx: ctx, Cancel: cancel}
+	hub.Register(client)
+
+	cfg := &config.Config{}
+	cfg.Notifications.NewRelease = true
+	p := poller.NewPoller(cfg, hub)
+	var notified []string
+	p.SetNotifyFunc(func(eventType, repo, message, url string) {
+		notified = append(notified, eventType)
+	})
+
+	p.SetPreviousRepos([]model.Repo{{Name: "test-repo", OpenPRs: 1}})
+	p.DetectAndEmitChanges([]model.Repo{{Name: "test-repo", OpenPRs: 2, NewRelease: true}})
+
+	select {
+	case event := <-client.Chan:
+		entries := event.Data.([]map[string]interface{})
+		if event.Type != "changes" || len(entries) != 1 || entries[0]["type"] != "pr_opened" {
+			t.Errorf("event = %s %+v, want changes with only pr_opened", event.Type, event.Data)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("did not receive changes event")
+	}
+	if slices.Contains(notified, "new_release") {
+		t.Error("new_release notification sent without a release")
+	}
+}
+
 // Helper method to access private poller field for testing
 func setPreviousRepos(p *poller.Poller, repos []model.Repo) {
 	// This is a test helper - in real code we'd use a getter or test the behavior through integration
 	_ = p
 }
+
+// TestRefreshFieldOnlyCallsRequestedFetcher tests that refreshing PRs only invokes the PR fetcher.
+func TestRefreshFieldOnlyCallsRequestedFetcher(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalCachePath := cache.GetCachePath()
+	defer cache.SetCachePath(originalCachePath)
+	cache.SetCachePath(filepath.Join(tmpDir, "cache.json"))
+
+	if err := cache.WriteRepos([]model.Repo{
+		{Name: "repo-a", Visibility: model.VisibilityPublic},
+		{Name: "repo-b", Visibility: model.VisibilityPrivate},
+	}); err != nil {
+		t.Fatalf("WriteRepos failed: %v", err)
+	}
+
+	var mu sync.Mutex
+	calls := map[string]int{}
+	record := func(name string) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls[name]++
+	}
+
+	p := poller.NewPoller(&config.Config{GitHubIntervalSeconds: 300, StaleDays: 30, AbandonedDays: 90}, sse.NewHub())
+	p.SetFetchers(poller.Fetchers{
+		// Fail the loop's first poll so it leaves the cache alone
+		ListRepos: func(owner string) ([]scanner.GitHubRepo, error) {
+			return nil, errors.New("offline")
+		},
+		PROpenCount: func(owner, name string) (int, error) {
+			record("prs")
+			return 3, nil
+		},
+		ActionsStatus: func(owner, name string) (string, error) {
+			record("actions")
+			return "passing", nil
+		},
+		FilePresence: func(owner, name string) (*scanner.FilePresence, error) {
+			record("files")
+			return &scanner.FilePresence{}, nil
+		},
+		LatestRelease: func(owner, name string) (*scanner.LatestRelease, error) {
+			record("releases")
+			return nil, nil
+		},
+	})
+
+	p.SetNotifyFunc(func(eventType, repo, message, url string) {})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go p.RunGitHubPoller(ctx)
+
+	count, err := p.RefreshField(ctx, poller.RefreshFieldPRs)
+	if err != nil {
+		t.Fatalf("RefreshField failed: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("count = %d, want 2", count)
+	}
+
+	if calls["prs"] != 2 {
+		t.Errorf("PR fetcher called %d times, want 2", calls["prs"])
+	}
+	for _, other := range []string{"actions", "files", "releases"} {
+		if calls[other] != 0 {
+			t.Errorf("%s fetcher called %d times, want 0", other, calls[other])
+		}
+	}
+
+	repos, err := cache.ReadRepos()
+	if err != nil {
+		t.Fatalf("ReadRepos failed: %v", err)
+	}
+	for _, repo := range repos {
+		if repo.OpenPRs != 3 {
+			t.Errorf("repo %s OpenPRs = %d, want 3", repo.Name, repo.OpenPRs)
+		}
+	}
+
+	if _, err := p.RefreshField(context.Background(), "bogus"); err == nil {
+		t.Error("RefreshField(bogus) error = nil, want error")
+	}
+}
+
+// TestRefreshFieldKeepsConcurrentWrites tests that a refresh patches only
+// its field into the cache, keeping what was written while it fetched.
+func TestRefreshFieldKeepsConcurrentWrites(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalCachePath := cache.GetCachePath()
+	defer cache.SetCachePath(originalCachePath)
+	cache.SetCachePath(filepath.Join(tmpDir, "cache.json"))
+
+	if err := cache.WriteRepos([]model.Repo{
+		{Name: "repo-a", Visibility: model.VisibilityPublic, OpenIssues: 1},
+	}); err != nil {
+		t.Fatalf("WriteRepos failed: %v", err)
+	}
+
+	fetching := make(chan struct{})
+	release := make(chan struct{})
+	p := poller.NewPoller(&config.Config{GitHubIntervalSeconds: 300, StaleDays: 30, AbandonedDays: 90}, sse.NewHub())
+	p.SetFetchers(poller.Fetchers{
+		ListRepos: func(owner string) ([]scanner.GitHubRepo, error) {
+			return nil, errors.New("offline")
+		},
+		PROpenCount: func(owner, name string) (int, error) {
+			close(fetching)
+			<-release
+			return 3, nil
+		},
+	})
+	p.SetNotifyFunc(func(eventType, repo, message, url string) {})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go p.RunGitHubPoller(ctx)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := p.RefreshField(ctx, poller.RefreshFieldPRs)
+		done <- err
+	}()
+
+	// A poll lands while the refresh is fetching
+	<-fetching
+	if err := cache.WriteRepos([]model.Repo{
+		{Name: "repo-a", Visibility: model.VisibilityPublic, OpenIssues: 7},
+	}); err != nil {
+		t.Fatalf("WriteRepos failed: %v", err)
+	}
+	close(release)
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("RefreshField failed: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for refresh")
+	}
+
+	repos, err := cache.ReadRepos()
+	if err != nil {
+		t.Fatalf("ReadRepos failed: %v", err)
+	}
+	if len(repos) != 1 || repos[0].OpenPRs != 3 || repos[0].OpenIssues != 7 {
+		t.Errorf("cached repos = %+v, want OpenPRs 3 and OpenIssues 7", repos)
+	}
+}
+
+// TestHeartbeatIntervalFromConfig tests that the heartbeat uses the configured interval.
+func TestHeartbeatIntervalFromConfig(t *testing.T) {
+	p := poller.NewPoller(&config.Config{HeartbeatSeconds: 12}, sse.NewHub())
+	if got := p.HeartbeatInterval(); got != 12*time.Second {
+		t.Errorf("HeartbeatInterval() = %v, want 12s", got)
+	}
+
+	// Unset falls back to the default
+	p = poller.NewPoller(&config.Config{}, sse.NewHub())
+	if got := p.HeartbeatInterval(); got != config.DefaultHeartbeatSeconds*time.Second {
+		t.Errorf("HeartbeatInterval() = %v, want %ds", got, config.DefaultHeartbeatSeconds)
+	}
+}
+
+// TestSnoozeSuppressesNotifications tests that snoozed repos don't notify until the snooze expires.
+func TestSnoozeSuppressesNotifications(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalCachePath := cache.GetCachePath()
+	defer cache.SetCachePath(originalCachePath)
+	cache.SetCachePath(filepath.Join(tmpDir, "cache.json"))
+
+	hub := sse.NewHub()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go hub.Run(ctx)
+
+	cfg := &config.Config{Notifications: config.NotificationConfig{ActionsChanged: true}}
+	p := poller.NewPoller(cfg, hub)
+
+	var notified []string
+	p.SetNotifyFunc(func(eventType, repo, message, url string) {
+		notified = append(notified, repo)
+	})
+
+	previous := []model.Repo{{Name: "test-repo", ActionsStatus: model.ActionsStatusPassing}}
+	failing := []model.Repo{{Name: "test-repo", ActionsStatus: model.ActionsStatusFailing}}
+
+	// Active snooze suppresses the notification
+	if err := p.Snooze("test-repo", time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("Snooze failed: %v", err)
+	}
+	p.SetPreviousRepos(previous)
+	p.DetectAndEmitChanges(failing)
+	if len(notified) != 0 {
+		t.Errorf("notified = %v, want none while snoozed", notified)
+	}
+
+	// Expired snooze notifies again
+	if err := p.Snooze("test-repo", time.Now().Add(-time.Minute)); err != nil {
+		t.Fatalf("Snooze failed: %v", err)
+	}
+	p.SetPreviousRepos(previous)
+	p.DetectAndEmitChanges(failing)
+	if len(notified) != 1 || notified[0] != "test-repo" {
+		t.Errorf("notified = %v, want [test-repo] after expiry", notified)
+	}
+
+	// Snooze is persisted in state.json
+	state, err := cache.ReadState()
+	if err != nil {
+		t.Fatalf("ReadState failed: %v", err)
+	}
+	if state["test-repo"] == nil || state["test-repo"].SnoozeUntil.IsZero() {
+		t.Error("state.json missing snoozeUntil for test-repo")
+	}
+}
+
+// TestChangesBatchedIntoSingleEvent tests that N changed repos produce one
+// "changes" event containing N entries.
+func TestChangesBatchedIntoSingleEvent(t *testing.T) {
+	hub := sse.NewHub()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go hub.Run(ctx)
+
+	client := &sse.Client{ID: "reader", Chan: make(chan sse.Event, 10), Ctx: ctx, Cancel: cancel}
+	hub.Register(client)
+
+	p := poller.NewPoller(&config.Config{}, hub)
+
+	const n = 250
+	previous := make([]model.Repo, n)
+	changed := make([]model.Repo, n)
+	for i := range n {
+		name := fmt.Sprintf("repo-%d", i)
+		previous[i] = model.Repo{Name: name, ActionsStatus: model.ActionsStatusPassing}
+		changed[i] = model.Repo{Name: name, ActionsStatus: model.ActionsStatusFailing}
+	}
+
+	p.SetPreviousRepos(previous)
+	p.DetectAndEmitChanges(changed)
+
+	select {
+	case event := <-client.Chan:
+		if event.Type != "changes" {
+			t.Fatalf("event type = %s, want changes", event.Type)
+		}
+		entries := event.Data.([]map[string]interface{})
+		if len(entries) != n {
+			t.Errorf("len(entries) = %d, want %d", len(entries), n)
+		}
+		if entries[0]["type"] != "actions_changed" {
+			t.Errorf("entry type = %v, want actions_changed", entries[0]["type"])
+		}
+	case <-time.After(time.Second):
+		t.Fatal("did not receive changes event")
+	}
+
+	select {
+	case event := <-client.Chan:
+		t.Errorf("received extra event %s, want a single changes event", event.Type)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+// TestCacheWrittenBeforeBroadcast tests that a client reading the cache on receipt of a broadcast sees the same data.
+func TestCacheWrittenBeforeBroadcast(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalCachePath := cache.GetCachePath()
+	defer cache.SetCachePath(originalCachePath)
+	cache.SetCachePath(filepath.Join(tmpDir, "cache.json"))
+
+	hub := sse.NewHub()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go hub.Run(ctx)
+
+	client := &sse.Client{ID: "reader", Chan: make(chan sse.Event, 10), Ctx: ctx, Cancel: cancel}
+	hub.Register(client)
+
+	p := poller.NewPoller(&config.Config{}, hub)
+
+	for i := 1; i <= 3; i++ {
+		repos := []model.Repo{{Name: "test-repo", OpenPRs: i}}
+		p.BroadcastRepos("repos_updated", repos)
+
+		select {
+		case event := <-client.Chan:
+			broadcast := event.Data.([]model.Repo)
+			cached, err := cache.ReadRepos()
+			if err != nil {
+				t.Fatalf("ReadRepos failed: %v", err)
+			}
+			if len(cached) != 1 || cached[0].OpenPRs != broadcast[0].OpenPRs {
+				t.Errorf("cached = %+v, want OpenPRs=%d as broadcast", cached, broadcast[0].OpenPRs)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("did not receive broadcast")
+		}
+	}
+}
+
+// TestBroadcastCoalescing tests that snapshots within the window collapse into the latest one.
+func TestBroadcastCoalescing(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalCachePath := cache.GetCachePath()
+	defer cache.SetCachePath(originalCachePath)
+	cache.SetCachePath(filepath.Join(tmpDir, "cache.json"))
+
+	hub := sse.NewHub()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go hub.Run(ctx)
+
+	client := &sse.Client{ID: "reader", Chan: make(chan sse.Event, 10), Ctx: ctx, Cancel: cancel}
+	hub.Register(client)
+
+	p := poller.NewPoller(&config.Config{CoalesceMillis: 50}, hub)
+	for i := 1; i <= 3; i++ {
+		p.BroadcastRepos("repos_updated", []model.Repo{{Name: "test-repo", OpenPRs: i}})
+	}
+
+	select {
+	case event := <-client.Chan:
+		repos := event.Data.([]model.Repo)
+		if repos[0].OpenPRs != 3 {
+			t.Errorf("OpenPRs = %d, want 3 (latest snapshot)", repos[0].OpenPRs)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("did not receive coalesced broadcast")
+	}
+
+	select {
+	case event := <-client.Chan:
+		t.Errorf("received extra event %s, want a single coalesced snapshot", event.Type)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+// TestReconfigureRescansNewPath tests that changing the scan path triggers an
+// immediate scan of the new path.
+func TestReconfigureRescansNewPath(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	tmpDir := t.TempDir()
+	originalCachePath := cache.GetCachePath()
+	defer cache.SetCachePath(originalCachePath)
+	cache.SetCachePath(filepath.Join(tmpDir, "cache.json"))
+
+	// The new scan path holds one repo; the old one is empty
+	oldPath := filepath.Join(tmpDir, "old")
+	newPath := filepath.Join(tmpDir, "new")
+	if err := os.MkdirAll(oldPath, 0o755); err != nil {
+		t.Fatalf("Failed to create old scan path: %v", err)
+	}
+	if output, err := exec.Command("git", "init", filepath.Join(newPath, "moved-repo")).CombinedOutput(); err != nil {
+		t.Fatalf("git init failed: %v (%s)", err, output)
+	}
+
+	cfg := &config.Config{ScanPath: oldPath, LocalIntervalSeconds: 3600, StaleDays: 30, AbandonedDays: 90}
+	p := poller.NewPoller(cfg, sse.NewHub())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go p.RunLocalPoller(ctx)
+
+	newCfg := *cfg
+	newCfg.ScanPath = newPath
+	p.Reconfigure(&newCfg)
+
+	if p.LocalInterval() != time.Hour {
+		t.Errorf("LocalInterval = %v, want 1h", p.LocalInterval())
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		repos, _ := cache.ReadRepos()
+		if len(repos) == 1 && repos[0].Name == "moved-repo" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("cache = %v, want moved-repo from the new scan path", repos)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// TestNotifierBackendSelection tests that the notifier picks a backend per OS.
+func TestNotifierBackendSelection(t *testing.T) {
+	found := func(names ...string) func(string) (string, error) {
+		return func(file string) (string, error) {
+			for _, name := range names {
+				if file == name {
+					return "/usr/bin/" + filepath.Base(name), nil
+				}
+			}
+			return "", exec.ErrNotFound
+		}
+	}
+
+	tests := []struct {
+		name     string
+		goos     string
+		lookPath func(string) (string, error)
+		want     string
+	}{
+		{name: "linux with notify-send", goos: "linux", lookPath: found("notify-send"), want: "notify-send"},
+		{name: "linux without notify-send", goos: "linux", lookPath: found(), want: ""},
+		{name: "darwin with terminal-notifier", goos: "darwin", lookPath: found("/opt/homebrew/bin/terminal-notifier"), want: "terminal-notifier"},
+		{name: "darwin fallback", goos: "darwin", lookPath: found(), want: "osascript"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := poller.NewNotifierFor(tt.goos, tt.lookPath)
+			if got := n.Backend(); got != tt.want {
+				t.Errorf("Backend() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// Without a backend, Notify reports an error instead of running a missing tool
+	if err := poller.NewNotifierFor("linux", found()).Notify("title", "message", ""); err == nil {
+		t.Error("Notify() without a backend returned nil error")
+	}
+}
+
+// TestWebhookNotification tests that a new_release notification is POSTed to
+// the webhook in webhook mode, without touching the desktop notifier.
+func TestWebhookNotification(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalCachePath := cache.GetCachePath()
+	defer cache.SetCachePath(originalCachePath)
+	cache.SetCachePath(filepath.Join(tmpDir, "cache.json"))
+
+	received := make(chan poller.WebhookPayload, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("method = %s, want POST", r.Method)
+		}
+		var payload poller.WebhookPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("decoding payload: %v", err)
+		}
+		received <- payload
+	}))
+	defer ts.Close()
+
+	cfg := &config.Config{
+		Notifications:    config.NotificationConfig{NewRelease: true},
+		NotificationMode: config.NotificationModeWebhook,
+		WebhookURL:       ts.URL,
+	}
+	p := poller.NewPoller(cfg, sse.NewHub())
+
+	desktop := 0
+	p.SetNotifyFunc(func(eventType, repo, message, url string) {
+		desktop++
+	})
+
+	release := &model.ReleaseInfo{TagName: "v1.2.0", PublishedAt: time.Now()}
+	p.SetPreviousRepos([]model.Repo{{Name: "test-repo"}})
+	p.DetectAndEmitChanges([]model.Repo{{Name: "test-repo", NewRelease: true, LatestRelease: release}})
+
+	select {
+	case payload := <-received:
+		if payload.EventType != "new_release" || payload.Repo != "test-repo" || payload.Message != "v1.2.0" {
+			t.Errorf("payload = %+v, want new_release for test-repo with message v1.2.0", payload)
+		}
+		if payload.Timestamp.IsZero() {
+			t.Error("payload timestamp is zero")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("webhook did not receive a notification")
+	}
+
+	if desktop != 0 {
+		t.Errorf("desktop notifications = %d, want 0 in webhook mode", desktop)
+	}
+}
+
+// TestNotificationURL tests the notification deep link for each link target.
+func TestNotificationURL(t *testing.T) {
+	tests := []struct {
+		target string
+		want   string
+	}{
+		{"", "https://github.com/alexcatdad/meowtern"},
+		{config.NotificationLinkGitHub, "https://github.com/alexcatdad/meowtern"},
+		{config.NotificationLinkDashboard, "http://127.0.0.1:7700/repo/meowtern"},
+	}
+
+	for _, tt := range tests {
+		cfg := &config.Config{GitHubOwner: "alexcatdad", Port: 7700, NotificationLinkTarget: tt.target}
+		if got := poller.NotificationURL(cfg, "", "meowtern"); got != tt.want {
+			t.Errorf("NotificationURL(target=%q) = %q, want %q", tt.target, got, tt.want)
+		}
+	}
+
+	// Dashboard links use the bind host, reaching wildcard hosts via loopback
+	for bindHost, want := range map[string]string{
+		"192.168.1.20": "http://192.168.1.20:7700/repo/meowtern",
+		"0.0.0.0":      "http://127.0.0.1:7700/repo/meowtern",
+		"::":           "http://127.0.0.1:7700/repo/meowtern",
+		"::1":          "http://[::1]:7700/repo/meowtern",
+	} {
+		cfg := &config.Config{BindHost: bindHost, Port: 7700, NotificationLinkTarget: config.NotificationLinkDashboard}
+		if got := poller.NotificationURL(cfg, "", "meowtern"); got != want {
+			t.Errorf("NotificationURL(bindHost=%q) = %q, want %q", bindHost, got, want)
+		}
+	}
+
+	// Org repos link to their own owner on GitHub
+	cfg := &config.Config{GitHubOwner: "alexcatdad", Port: 7700}
+	if got := poller.NotificationURL(cfg, "org-a", "service"); got != "https://github.com/org-a/service" {
+		t.Errorf("NotificationURL(org repo) = %q, want https://github.com/org-a/service", got)
+	}
+
+	// The poller passes the link to the notifier
+	cfg = &config.Config{
+		GitHubOwner:            "alexcatdad",
+		Port:                   7700,
+		Notifications:          config.NotificationConfig{ActionsChanged: true},
+		NotificationLinkTarget: config.NotificationLinkDashboard,
+	}
+	p := poller.NewPoller(cfg, sse.NewHub())
+	var gotURL string
+	p.SetNotifyFunc(func(eventType, repo, message, url string) {
+		gotURL = url
+	})
+	p.SetPreviousRepos([]model.Repo{{Name: "meowtern", ActionsStatus: model.ActionsStatusPassing}})
+	p.DetectAndEmitChanges([]model.Repo{{Name: "meowtern", ActionsStatus: model.ActionsStatusFailing}})
+	if gotURL != "http://127.0.0.1:7700/repo/meowtern" {
+		t.Errorf("notifier url = %q, want dashboard link", gotURL)
+	}
+}
+
+// TestReconcileCloneState tests that the fast reconcile updates clone flags
+// from the filesystem without running git.
+func TestReconcileCloneState(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalCachePath := cache.GetCachePath()
+	defer cache.SetCachePath(originalCachePath)
+	cache.SetCachePath(filepath.Join(tmpDir, "cache.json"))
+
+	// Bare .git directories are not valid repos: any git command run
+	// against them would fail, and with PATH empty git cannot run at all.
+	t.Setenv("PATH", "")
+	scanPath := filepath.Join(tmpDir, "repos")
+	for _, name := range []string{"newly-cloned", "new-on-disk"} {
+		if err := os.MkdirAll(filepath.Join(scanPath, name, ".git"), 0o755); err != nil {
+			t.Fatalf("Failed to create repo dir: %v", err)
+		}
+	}
+
+	cached := []model.Repo{
+		{Name: "newly-cloned", Cloned: false, LocalPath: scanPath + "/newly-cloned", OpenPRs: 2},
+		{Name: "removed", Cloned: true, LocalPath: filepath.Join(scanPath, "removed"), Branch: "main"},
+	}
+	if err := cache.WriteRepos(cached); err != nil {
+		t.Fatalf("WriteRepos failed: %v", err)
+	}
+
+	cfg := &config.Config{ScanPath: scanPath, StaleDays: 30, AbandonedDays: 90}
+	p := poller.NewPoller(cfg, sse.NewHub())
+
+	changed, err := p.ReconcileCloneState()
+	if err != nil {
+		t.Fatalf("ReconcileCloneState failed: %v", err)
+	}
+	if changed != 3 {
+		t.Errorf("changed = %d, want 3", changed)
+	}
+
+	repos, err := cache.ReadRepos()
+	if err != nil {
+		t.Fatalf("ReadRepos failed: %v", err)
+	}
+	byName := make(map[string]model.Repo)
+	for _, r := range repos {
+		byName[r.Name] = r
+	}
+
+	if r := byName["newly-cloned"]; !r.Cloned || r.LocalPath != filepath.Join(scanPath, "newly-cloned") || r.OpenPRs != 2 {
+		t.Errorf("newly-cloned = %+v, want Cloned with other fields kept", r)
+	}
+	if r := byName["removed"]; r.Cloned || r.LocalPath != scanPath+"/removed" {
+		t.Errorf("removed = %+v, want not cloned", r)
+	}
+	if r, ok := byName["new-on-disk"]; !ok || !r.Cloned || r.Branch != "" {
+		t.Errorf("new-on-disk = %+v, want added as cloned without git state", r)
+	}
+
+	// A second reconcile finds nothing to change
+	if changed, err := p.ReconcileCloneState(); err != nil || changed != 0 {
+		t.Errorf("second ReconcileCloneState = (%d, %v), want (0, nil)", changed, err)
+	}
+}
+
+// TestCloneCompletedNotificationToggle tests that clone notifications fire
+// only when the CloneCompleted toggle is on.
+func TestCloneCompletedNotificationToggle(t *testing.T) {
+	for _, enabled := range []bool{true, false} {
+		cfg := &config.Config{Notifications: config.NotificationConfig{CloneCompleted: enabled}}
+		p := poller.NewPoller(cfg, sse.NewHub())
+
+		var events []string
+		p.SetNotifyFunc(func(eventType, repo, message, url string) {
+			events = append(events, eventType+":"+repo)
+		})
+
+		p.NotifyCloneCompleted("", "meowtern")
+
+		want := 0
+		if enabled {
+			want = 1
+		}
+		if len(events) != want {
+			t.Errorf("CloneCompleted=%v: notifications = %v, want %d", enabled, events, want)
+		}
+		if enabled && len(events) == 1 && events[0] != "clone_completed:meowtern" {
+			t.Errorf("notification = %s, want clone_completed:meowtern", events[0])
+		}
+	}
+}
+
+// TestGitHubErrorNotificationToggle tests that a gh auth failure notifies
+// only when the Error toggle is on, and only once while it persists.
+func TestGitHubErrorNotificationToggle(t *testing.T) {
+	for _, enabled := range []bool{true, false} {
+		hub := sse.NewHub()
+		ctx, cancel := context.WithCancel(context.Background())
+		go hub.Run(ctx)
+
+		cfg := &config.Config{Notifications: config.NotificationConfig{Error: enabled}}
+		p := poller.NewPoller(cfg, hub)
+		p.SetFetchers(poller.Fetchers{
+			ListRepos: func(owner string) ([]scanner.GitHubRepo, error) {
+				return nil, fmt.Errorf("listing repos: %w", scanner.NewGHAuthError("not authenticated"))
+			},
+		})
+
+		var events []string
+		p.SetNotifyFunc(func(eventType, repo, message, url string) {
+			events = append(events, eventType)
+		})
+
+		p.GitHubPoll(ctx)
+		p.GitHubPoll(ctx)
+		cancel()
+
+		want := 0
+		if enabled {
+			want = 1
+		}
+		if len(events) != want {
+			t.Errorf("Error=%v: notifications = %v, want %d", enabled, events, want)
+		}
+	}
+}
+
+// TestGitHubPollBackoff tests that consecutive gh failures stretch the next
+// poll delay up to the cap, auth failures faster, and success resets it.
+func TestGitHubPollBackoff(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalCachePath := cache.GetCachePath()
+	defer cache.SetCachePath(originalCachePath)
+	cache.SetCachePath(filepath.Join(tmpDir, "cache.json"))
+
+	hub := sse.NewHub()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go hub.Run(ctx)
+
+	var listErr error
+	p := poller.NewPoller(&config.Config{GitHubIntervalSeconds: 60, StaleDays: 30, AbandonedDays: 90}, hub)
+	p.SetFetchers(poller.Fetchers{
+		ListRepos: func(owner string) ([]scanner.GitHubRepo, error) {
+			return nil, listErr
+		},
+	})
+	p.SetNotifyFunc(func(eventType, repo, message, url string) {})
+
+	poll := func(err error) time.Duration {
+		listErr = err
+		return p.GitHubPoll(ctx)
+	}
+
+	transient := errors.New("gh: API rate limit exceeded")
+	for i, want := range []time.Duration{
+		2 * time.Minute,
+		4 * time.Minute,
+		8 * time.Minute,
+		16 * time.Minute,
+		30 * time.Minute, // capped
+		30 * time.Minute,
+	} {
+		if got := poll(transient); got != want {
+			t.Errorf("transient failure %d: next delay = %v, want %v", i+1, got, want)
+		}
+	}
+
+	if got := poll(nil); got != time.Minute {
+		t.Errorf("after success: next delay = %v, want 1m", got)
+	}
+
+	auth := fmt.Errorf("listing repos: %w", scanner.NewGHAuthError("not authenticated"))
+	for i, want := range []time.Duration{
+		4 * time.Minute,
+		16 * time.Minute,
+		30 * time.Minute, // capped
+	} {
+		if got := poll(auth); got != want {
+			t.Errorf("auth failure %d: next delay = %v, want %v", i+1, got, want)
+		}
+	}
+
+	if got := poll(nil); got != time.Minute {
+		t.Errorf("after recovery: next delay = %v, want 1m", got)
+	}
+}
+
+// TestLastPollErrors tests that a failed poll is reported by the error
+// getters and that the next successful poll clears it.
+func TestLastPollErrors(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalCachePath := cache.GetCachePath()
+	defer cache.SetCachePath(originalCachePath)
+	cache.SetCachePath(filepath.Join(tmpDir, "cache.json"))
+
+	hub := sse.NewHub()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go hub.Run(ctx)
+
+	scanPath := filepath.Join(tmpDir, "projects")
+	var listErr error
+	p := poller.NewPoller(&config.Config{ScanPath: scanPath, GitHubIntervalSeconds: 60, StaleDays: 30, AbandonedDays: 90}, hub)
+	p.SetFetchers(poller.Fetchers{
+		ListRepos: func(owner string) ([]scanner.GitHubRepo, error) {
+			return nil, listErr
+		},
+	})
+	p.SetNotifyFunc(func(eventType, repo, message, url string) {})
+
+	// A file where the scan path should be makes discovery fail
+	if err := os.WriteFile(scanPath, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	p.LocalPoll(ctx)
+	if got := p.GetLastLocalPollError(); got == nil || got.Error == "" || got.At.IsZero() {
+		t.Fatalf("GetLastLocalPollError() = %+v, want the discovery failure", got)
+	}
+
+	if err := os.Remove(scanPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(scanPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+	p.LocalPoll(ctx)
+	if got := p.GetLastLocalPollError(); got != nil {
+		t.Errorf("after success: GetLastLocalPollError() = %+v, want nil", got)
+	}
+
+	listErr = errors.New("gh: connection reset")
+	p.GitHubPoll(ctx)
+	got := p.GetLastGitHubPollError()
+	if got == nil || !strings.Contains(got.Error, "connection reset") {
+		t.Fatalf("GetLastGitHubPollError() = %+v, want the listing failure", got)
+	}
+
+	listErr = nil
+	p.GitHubPoll(ctx)
+	if got := p.GetLastGitHubPollError(); got != nil {
+		t.Errorf("after success: GetLastGitHubPollError() = %+v, want nil", got)
+	}
+}
+
+// TestScanPathErrorEvent tests that a local poll whose scan path is a file
+// broadcasts a scan_path_error event with the reason.
+func TestScanPathErrorEvent(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalCachePath := cache.GetCachePath()
+	defer cache.SetCachePath(originalCachePath)
+	cache.SetCachePath(filepath.Join(tmpDir, "cache.json"))
+
+	hub := sse.NewHub()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go hub.Run(ctx)
+
+	client := &sse.Client{ID: "reader", Chan: make(chan sse.Event, 10), Ctx: ctx, Cancel: cancel}
+	hub.Register(client)
+
+	scanPath := filepath.Join(tmpDir, "projects")
+	if err := os.WriteFile(scanPath, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	p := poller.NewPoller(&config.Config{ScanPath: scanPath, StaleDays: 30, AbandonedDays: 90}, hub)
+	p.LocalPoll(ctx)
+
+	for {
+		select {
+		case event := <-client.Chan:
+			if event.Type != "scan_path_error" {
+				continue
+			}
+			data := event.Data.(map[string]string)
+			if data["path"] != scanPath || data["reason"] != scanner.ScanPathNotDirectory {
+				t.Errorf("scan_path_error = %v, want %s for %s", data, scanner.ScanPathNotDirectory, scanPath)
+			}
+			return
+		case <-time.After(time.Second):
+			t.Fatal("did not receive scan_path_error event")
+		}
+	}
+}
+
+// TestRateLimitedEvent tests that a rate-limited poll broadcasts when polling
+// resumes and waits for the reported reset.
+func TestRateLimitedEvent(t *testing.T) {
+	hub := sse.NewHub()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go hub.Run(ctx)
+
+	client := &sse.Client{ID: "reader", Chan: make(chan sse.Event, 10), Ctx: ctx, Cancel: cancel}
+	hub.Register(client)
+
+	reset := time.Now().Add(2 * time.Hour).UTC().Truncate(time.Second)
+	stderr := fmt.Sprintf("HTTP 403: API rate limit exceeded for user ID 1234.\nX-Ratelimit-Reset: %d\n", reset.Unix())
+
+	p := poller.NewPoller(&config.Config{GitHubIntervalSeconds: 60}, hub)
+	p.SetFetchers(poller.Fetchers{
+		ListRepos: func(owner string) ([]scanner.GitHubRepo, error) {
+			return nil, fmt.Errorf("listing repos: %w", scanner.ParseGHError(stderr, errors.New("exit status 1")))
+		},
+	})
+
+	next := p.GitHubPoll(ctx)
+	if next < time.Until(reset)-time.Second {
+		t.Errorf("next delay = %v, want at least until reset (%v)", next, time.Until(reset))
+	}
+
+	select {
+	case event := <-client.Chan:
+		if event.Type != "rate_limited" {
+			t.Fatalf("event type = %s, want rate_limited", event.Type)
+		}
+		data := event.Data.(map[string]interface{})
+		if got := data["resetAt"].(time.Time); !got.Equal(reset) {
+			t.Errorf("resetAt = %v, want %v", got, reset)
+		}
+		if resume := data["resumeAt"].(time.Time); resume.Before(reset) {
+			t.Errorf("resumeAt = %v, want not before reset %v", resume, reset)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("did not receive rate_limited event")
+	}
+}
+
+// TestGitHubHealthGracePeriod tests that health stays healthy through fewer
+// than the threshold of consecutive failures, flips at the threshold, and
+// recovers after a success.
+func TestGitHubHealthGracePeriod(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalCachePath := cache.GetCachePath()
+	defer cache.SetCachePath(originalCachePath)
+	cache.SetCachePath(filepath.Join(tmpDir, "cache.json"))
+
+	hub := sse.NewHub()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go hub.Run(ctx)
+
+	var listErr error
+	cfg := &config.Config{GitHubIntervalSeconds: 60, GitHubFailureThreshold: 3, StaleDays: 30, AbandonedDays: 90}
+	p := poller.NewPoller(cfg, hub)
+	p.SetFetchers(poller.Fetchers{
+		ListRepos: func(owner string) ([]scanner.GitHubRepo, error) {
+			return nil, listErr
+		},
+	})
+
+	listErr = errors.New("network unreachable")
+	for i := 1; i <= 3; i++ {
+		p.GitHubPoll(ctx)
+		want := i < 3
+		if got := p.GitHubHealthy(); got != want {
+			t.Errorf("after %d failures: GitHubHealthy = %v, want %v", i, got, want)
+		}
+	}
+
+	listErr = nil
+	p.GitHubPoll(ctx)
+	if !p.GitHubHealthy() || p.GitHubFailures() != 0 {
+		t.Errorf("after success: GitHubHealthy = %v, failures = %d, want true, 0", p.GitHubHealthy(), p.GitHubFailures())
+	}
+
+	// The counter restarts, so a single new failure is still within grace
+	listErr = errors.New("network unreachable")
+	p.GitHubPoll(ctx)
+	if !p.GitHubHealthy() {
+		t.Error("after one new failure: GitHubHealthy = false, want true")
+	}
+}
+
+func TestRemoveRepoNotResurrectedByStalePoll(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalCachePath := cache.GetCachePath()
+	defer cache.SetCachePath(originalCachePath)
+	cache.SetCachePath(filepath.Join(tmpDir, "cache.json"))
+
+	cached := []model.Repo{{Name: "gone"}, {Name: "kept"}, {Name: "back"}}
+	if err := cache.WriteRepos(cached); err != nil {
+		t.Fatalf("WriteRepos failed: %v", err)
+	}
+
+	p := poller.NewPoller(&config.Config{StaleDays: 30, AbandonedDays: 90}, sse.NewHub())
+
+	for _, name := range []string{"gone", "back"} {
+		removed, err := p.RemoveRepo(name)
+		if err != nil || !removed {
+			t.Fatalf("RemoveRepo(%q) = %v, %v; want true, nil", name, removed, err)
+		}
+	}
+	if removed, err := p.RemoveRepo("unknown"); err != nil || removed {
+		t.Errorf("RemoveRepo(unknown) = %v, %v; want false, nil", removed, err)
+	}
+
+	repos, err := cache.ReadRepos()
+	if err != nil {
+		t.Fatalf("ReadRepos failed: %v", err)
+	}
+	if len(repos) != 1 || repos[0].Name != "kept" {
+		t.Fatalf("cache after remove = %+v, want [kept]", repos)
+	}
+
+	// A poll that read the cache before the removal merges all three repos;
+	// only "back" still exists in the poll's own source.
+	stale := []model.Repo{{Name: "gone"}, {Name: "kept"}, {Name: "back"}}
+	result := p.DropRemoved(stale, func(name string) bool { return name == "back" })
+	var names []string
+	for _, r := range result {
+		names = append(names, r.Name)
+	}
+	if strings.Join(names, ",") != "kept,back" {
+		t.Errorf("stale poll result = %v, want [kept back]", names)
+	}
+
+	// "back" was seen again, so a later poll no longer drops it
+	result = p.DropRemoved([]model.Repo{{Name: "back"}}, func(string) bool { return false })
+	if len(result) != 1 {
+		t.Errorf("re-seen repo dropped again: %+v", result)
+	}
+}
+
+// TestNotificationsSuppressedAfterCancel tests that no notification is sent
+// once the poller's context is cancelled.
+func TestNotificationsSuppressedAfterCancel(t *testing.T) {
+	cfg := &config.Config{Notifications: config.NotificationConfig{CloneCompleted: true}}
+	p := poller.NewPoller(cfg, sse.NewHub())
+
+	var events []string
+	p.SetNotifyFunc(func(eventType, repo, message, url string) {
+		events = append(events, repo)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p.BindContext(ctx)
+
+	p.NotifyCloneCompleted("", "before")
+	cancel()
+	p.NotifyCloneCompleted("", "after")
+
+	if len(events) != 1 || events[0] != "before" {
+		t.Errorf("notifications = %v, want [before]", events)
+	}
+}
+
+// TestAcknowledgeAllActions tests that acknowledging a failing status
+// suppresses re-notification until the status changes to something else.
+func TestAcknowledgeAllActions(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalCachePath := cache.GetCachePath()
+	defer cache.SetCachePath(originalCachePath)
+	cache.SetCachePath(filepath.Join(tmpDir, "cache.json"))
+
+	cfg := &config.Config{Notifications: config.NotificationConfig{ActionsChanged: true}}
+	p := poller.NewPoller(cfg, sse.NewHub())
+
+	var notified []string
+	p.SetNotifyFunc(func(eventType, repo, message, url string) {
+		notified = append(notified, message)
+	})
+
+	// step moves the repo to status and reports whether that notified
+	current := model.ActionsStatusPassing
+	step := func(status model.ActionsStatus) bool {
+		p.SetPreviousRepos([]model.Repo{{Name: "meowtern", ActionsStatus: current}})
+		before := len(notified)
+		p.DetectAndEmitChanges([]model.Repo{{Name: "meowtern", ActionsStatus: status}})
+		current = status
+		return len(notified) > before
+	}
+
+	if !step(model.ActionsStatusFailing) {
+		t.Fatal("passing -> failing did not notify before acknowledging")
+	}
+
+	if err := cache.WriteRepos([]model.Repo{{Name: "meowtern", ActionsStatus: model.ActionsStatusFailing}}); err != nil {
+		t.Fatalf("WriteRepos failed: %v", err)
+	}
+	count, err := p.AcknowledgeAllActions()
+	if err != nil || count != 1 {
+		t.Fatalf("AcknowledgeAllActions() = %d, %v; want 1, nil", count, err)
+	}
+
+	step(model.ActionsStatusNone)
+	if step(model.ActionsStatusFailing) {
+		t.Error("returning to the acknowledged failing status notified")
+	}
+
+	if !step(model.ActionsStatusPassing) {
+		t.Error("recovery to passing did not notify")
+	}
+	if !step(model.ActionsStatusFailing) {
+		t.Error("failing after recovery did not notify")
+	}
+
+	state, err := cache.ReadState()
+	if err != nil {
+		t.Fatalf("ReadState failed: %v", err)
+	}
+	if entry := state["meowtern"]; entry == nil || entry.AckedActionsStatus != "" {
+		t.Errorf("acknowledgement not cleared after recovery: %+v", entry)
+	}
+}
+
+// TestGitHubPollIncludesAllOrgs tests that IncludeAllOrgs lists the repos of
+// every discovered org and fetches per-repo data from the right owner.
+func TestGitHubPollIncludesAllOrgs(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalCachePath := cache.GetCachePath()
+	defer cache.SetCachePath(originalCachePath)
+	cache.SetCachePath(filepath.Join(tmpDir, "cache.json"))
+
+	hub := sse.NewHub()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go hub.Run(ctx)
+
+	listings := map[string][]scanner.GitHubRepo{
+		"alexcatdad": {{Name: "catscan", Visibility: "public"}},
+		"org-a":      {{Name: "service", Visibility: "private"}, {Name: "catscan", Visibility: "public"}},
+		"org-b":      {{Name: "website", Visibility: "public"}},
+	}
+	var mu sync.Mutex
+	prOwners := make(map[string]string)
+
+	cfg := &config.Config{GitHubOwner: "alexcatdad", IncludeAllOrgs: true, GitHubIntervalSeconds: 60, StaleDays: 30, AbandonedDays: 90}
+	p := poller.NewPoller(cfg, hub)
+	p.SetFetchers(poller.Fetchers{
+		ListRepos: func(owner string) ([]scanner.GitHubRepo, error) {
+			if owner == "org-locked" {
+				return nil, errors.New("SAML enforcement")
+			}
+			return listings[owner], nil
+		},
+		ListOrgs: func() ([]string, error) {
+			return []string{"org-a", "org-locked", "AlexCatDad", "org-b"}, nil
+		},
+		PROpenCount: func(owner, name string) (int, error) {
+			mu.Lock()
+			prOwners[name] = owner
+			mu.Unlock()
+			return 0, nil
+		},
+		IssueOpenCount: func(owner, name string) (int, error) { return 0, nil },
+		ActionsStatus:  func(owner, name string) (string, error) { return "none", nil },
+		FilePresence:   func(owner, name string) (*scanner.FilePresence, error) { return nil, nil },
+		LatestRelease:  func(owner, name string) (*scanner.LatestRelease, error) { return nil, nil },
+		ProjectFile:    func(owner, name string) (*scanner.ProjectInfo, error) { return nil, nil },
+	})
+	p.SetNotifyFunc(func(eventType, repo, message, url string) {})
+
+	p.GitHubPoll(ctx)
+
+	repos, err := cache.ReadRepos()
+	if err != nil {
+		t.Fatalf("ReadRepos failed: %v", err)
+	}
+	owners := make(map[string]string)
+	for _, repo := range repos {
+		owners[repo.Name] = repo.Owner
+	}
+	want := map[string]string{"catscan": "", "service": "org-a", "website": "org-b"}
+	if len(owners) != len(want) {
+		t.Fatalf("repos = %v, want %v", owners, want)
+	}
+	for name, owner := range want {
+		if got, ok := owners[name]; !ok || got != owner {
+			t.Errorf("%s owner = %q, want %q", name, got, owner)
+		}
+	}
+
+	wantPROwners := map[string]string{"catscan": "alexcatdad", "service": "org-a", "website": "org-b"}
+	for name, owner := range wantPROwners {
+		if prOwners[name] != owner {
+			t.Errorf("PROpenCount(%s) owner = %q, want %q", name, prOwners[name], owner)
+		}
+	}
+}
+
+// TestLocalPollSkipsRepoBeingCloned tests that a repo with a clone in
+// progress is left out of git-state extraction until the clone finishes.
+func TestLocalPollSkipsRepoBeingCloned(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	tmpDir := t.TempDir()
+	originalCachePath := cache.GetCachePath()
+	defer cache.SetCachePath(originalCachePath)
+	cache.SetCachePath(filepath.Join(tmpDir, "cache.json"))
+
+	if err := cache.WriteRepos([]model.Repo{{Name: "app", Visibility: model.VisibilityPublic}}); err != nil {
+		t.Fatalf("WriteRepos failed: %v", err)
+	}
+
+	scanPath := filepath.Join(tmpDir, "repos")
+	if output, err := exec.Command("git", "init", filepath.Join(scanPath, "app")).CombinedOutput(); err != nil {
+		t.Fatalf("git init failed: %v (%s)", err, output)
+	}
+
+	p := poller.NewPoller(&config.Config{ScanPath: scanPath, StaleDays: 30, AbandonedDays: 90}, sse.NewHub())
+	ctx := context.Background()
+
+	cloned := func() bool {
+		t.Helper()
+		repos, err := cache.ReadRepos()
+		if err != nil {
+			t.Fatalf("ReadRepos failed: %v", err)
+		}
+		for _, repo := range repos {
+			if repo.Name == "app" {
+				return repo.Cloned
+			}
+		}
+		t.Fatalf("app missing from cache: %+v", repos)
+		return false
+	}
+
+	p.CloneStarted("app")
+	p.LocalPoll(ctx)
+	if cloned() {
+		t.Error("repo being cloned was scanned as cloned")
+	}
+
+	p.CloneFinished("app")
+	p.LocalPoll(ctx)
+	if !cloned() {
+		t.Error("repo not scanned after its clone finished")
+	}
+}
+
+// TestGitHubPollAutoHidesLongAbandoned tests that an uncloned repo abandoned
+// past AutoHideAbandonedDays is hidden and recorded in state, while a
+// recently abandoned one stays visible.
+func TestGitHubPollAutoHidesLongAbandoned(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalCachePath := cache.GetCachePath()
+	defer cache.SetCachePath(originalCachePath)
+	cache.SetCachePath(filepath.Join(tmpDir, "cache.json"))
+
+	hub := sse.NewHub()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go hub.Run(ctx)
+
+	now := time.Now().UTC()
+	listing := []scanner.GitHubRepo{
+		{Name: "ancient", Visibility: "public", PushedAt: now.AddDate(0, 0, -800).Format(time.RFC3339)},
+		{Name: "recent", Visibility: "public", PushedAt: now.AddDate(0, 0, -120).Format(time.RFC3339)},
+	}
+
+	cfg := &config.Config{
+		GitHubOwner:           "alexcatdad",
+		ScanPath:              filepath.Join(tmpDir, "repos"),
+		GitHubIntervalSeconds: 60,
+		StaleDays:             30,
+		AbandonedDays:         90,
+		AutoHideAbandonedDays: 365,
+	}
+	p := poller.NewPoller(cfg, hub)
+	p.SetFetchers(poller.Fetchers{
+		ListRepos:      func(owner string) ([]scanner.GitHubRepo, error) { return listing, nil },
+		PROpenCount:    func(owner, name string) (int, error) { return 0, nil },
+		IssueOpenCount: func(owner, name string) (int, error) { return 0, nil },
+		ActionsStatus:  func(owner, name string) (string, error) { return "none", nil },
+		FilePresence:   func(owner, name string) (*scanner.FilePresence, error) { return nil, nil },
+		LatestRelease:  func(owner, name string) (*scanner.LatestRelease, error) { return nil, nil },
+		ProjectFile:    func(owner, name string) (*scanner.ProjectInfo, error) { return nil, nil },
+	})
+	p.SetNotifyFunc(func(eventType, repo, message, url string) {})
+
+	p.GitHubPoll(ctx)
+
+	repos, err := cache.ReadRepos()
+	if err != nil {
+		t.Fatalf("ReadRepos failed: %v", err)
+	}
+	hidden := make(map[string]bool)
+	for _, repo := range repos {
+		if repo.Lifecycle != model.LifecycleAbandoned {
+			t.Errorf("%s lifecycle = %s, want abandoned", repo.Name, repo.Lifecycle)
+		}
+		hidden[repo.Name] = repo.Hidden
+	}
+	if !hidden["ancient"] {
+		t.Error("ancient should be auto-hidden")
+	}
+	if hidden["recent"] {
+		t.Error("recent should not be auto-hidden")
+	}
+
+	if entry := p.RepoState("ancient"); entry == nil || entry.AutoHiddenAt.IsZero() {
+		t.Error("ancient auto-hide should be persisted in state")
+	}
+	if entry := p.RepoState("recent"); entry != nil && !entry.AutoHiddenAt.IsZero() {
+		t.Error("recent should have no auto-hide state")
+	}
+}
+
+// TestTrackedReposAllowlist tests that with TrackedRepos set, GitHub and
+// local polls keep only the listed repos and skip fetching the others.
+func TestTrackedReposAllowlist(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	tmpDir := t.TempDir()
+	originalCachePath := cache.GetCachePath()
+	defer cache.SetCachePath(originalCachePath)
+	cache.SetCachePath(filepath.Join(tmpDir, "cache.json"))
+
+	hub := sse.NewHub()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go hub.Run(ctx)
+
+	scanPath := filepath.Join(tmpDir, "repos")
+	for _, name := range []string{"catscan", "scratch"} {
+		if output, err := exec.Command("git", "init", filepath.Join(scanPath, name)).CombinedOutput(); err != nil {
+			t.Fatalf("git init failed: %v (%s)", err, output)
+		}
+	}
+
+	listing := []scanner.GitHubRepo{
+		{Name: "catscan", Visibility: "public"},
+		{Name: "dotfiles", Visibility: "public"},
+		{Name: "website", Visibility: "public"},
+		{Name: "scratch", Visibility: "private"},
+	}
+	var mu sync.Mutex
+	fetched := make(map[string]bool)
+
+	cfg := &config.Config{
+		GitHubOwner:           "alexcatdad",
+		ScanPath:              scanPath,
+		GitHubIntervalSeconds: 60,
+		StaleDays:             30,
+		AbandonedDays:         90,
+		TrackedRepos:          []string{"catscan", "alexcatdad/website"},
+	}
+	p := poller.NewPoller(cfg, hub)
+	p.SetFetchers(poller.Fetchers{
+		ListRepos: func(owner string) ([]scanner.GitHubRepo, error) { return listing, nil },
+		PROpenCount: func(owner, name string) (int, error) {
+			mu.Lock()
+			fetched[name] = true
+			mu.Unlock()
+			return 0, nil
+		},
+		IssueOpenCount: func(owner, name string) (int, error) { return 0, nil },
+		ActionsStatus:  func(owner, name string) (string, error) { return "none", nil },
+		FilePresence:   func(owner, name string) (*scanner.FilePresence, error) { return nil, nil },
+		LatestRelease:  func(owner, name string) (*scanner.LatestRelease, error) { return nil, nil },
+		ProjectFile:    func(owner, name string) (*scanner.ProjectInfo, error) { return nil, nil },
+	})
+	p.SetNotifyFunc(func(eventType, repo, message, url string) {})
+
+	names := func() []string {
+		t.Helper()
+		repos, err := cache.ReadRepos()
+		if err != nil {
+			t.Fatalf("ReadRepos failed: %v", err)
+		}
+		var names []string
+		for _, repo := range repos {
+			names = append(names, repo.Name)
+		}
+		slices.Sort(names)
+		return names
+	}
+	want := []string{"catscan", "website"}
+
+	p.LocalPoll(ctx)
+	if got := names(); !slices.Equal(got, []string{"catscan"}) {
+		t.Errorf("after local poll repos = %v, want [catscan]", got)
+	}
+
+	p.GitHubPoll(ctx)
+	if got := names(); !slices.Equal(got, want) {
+		t.Errorf("after GitHub poll repos = %v, want %v", got, want)
+	}
+	for _, name := range []string{"dotfiles", "scratch"} {
+		if fetched[name] {
+			t.Errorf("untracked repo %s was fetched", name)
+		}
+	}
+
+	p.LocalPoll(ctx)
+	if got := names(); !slices.Equal(got, want) {
+		t.Errorf("after second local poll repos = %v, want %v", got, want)
+	}
+}
+
+// TestPollErrorsEvent tests that per-repo fetch failures are broadcast in a
+// poll_errors event, and that the next clean cycle clears them.
+func TestPollErrorsEvent(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalCachePath := cache.GetCachePath()
+	defer cache.SetCachePath(originalCachePath)
+	cache.SetCachePath(filepath.Join(tmpDir, "cache.json"))
+
+	hub := sse.NewHub()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go hub.Run(ctx)
+
+	client := &sse.Client{ID: "reader", Chan: make(chan sse.Event, 20), Ctx: ctx, Cancel: cancel}
+	hub.Register(client)
+
+	failing := true
+	p := poller.NewPoller(&config.Config{GitHubOwner: "alexcatdad", GitHubIntervalSeconds: 60, StaleDays: 30, AbandonedDays: 90}, hub)
+	p.SetFetchers(poller.Fetchers{
+		ListRepos: func(owner string) ([]scanner.GitHubRepo, error) {
+			return []scanner.GitHubRepo{{Name: "catscan"}, {Name: "dotfiles"}}, nil
+		},
+		PROpenCount: func(owner, name string) (int, error) {
+			if failing && name == "catscan" {
+				return 0, errors.New("HTTP 502")
+			}
+			return 0, nil
+		},
+		IssueOpenCount: func(owner, name string) (int, error) { return 0, nil },
+		ActionsStatus: func(owner, name string) (string, error) {
+			if failing && name == "dotfiles" {
+				return "", errors.New("workflow runs unavailable")
+			}
+			return "none", nil
+		},
+		FilePresence:  func(owner, name string) (*scanner.FilePresence, error) { return nil, nil },
+		LatestRelease: func(owner, name string) (*scanner.LatestRelease, error) { return nil, nil },
+		ProjectFile:   func(owner, name string) (*scanner.ProjectInfo, error) { return nil, nil },
+	})
+	p.SetNotifyFunc(func(eventType, repo, message, url string) {})
+
+	nextPollErrors := func() []poller.RepoPollError {
+		t.Helper()
+		for {
+			select {
+			case event := <-client.Chan:
+				if event.Type != "poll_errors" {
+					continue
+				}
+				data := event.Data.(map[string]interface{})
+				if data["source"] != "github" {
+					t.Errorf("source = %v, want github", data["source"])
+				}
+				return data["errors"].([]poller.RepoPollError)
+			case <-time.After(time.Second):
+				t.Fatal("did not receive poll_errors event")
+				return nil
+			}
+		}
+	}
+
+	p.GitHubPoll(ctx)
+	got := nextPollErrors()
+	want := []poller.RepoPollError{
+		{Repo: "catscan", Stage: "prs", Error: "HTTP 502"},
+		{Repo: "dotfiles", Stage: "actions", Error: "workflow runs unavailable"},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("errors = %+v, want %+v", got, want)
+	}
+
+	failing = false
+	p.GitHubPoll(ctx)
+	if got := nextPollErrors(); len(got) != 0 {
+		t.Errorf("errors after clean cycle = %+v, want none", got)
+	}
+}
+
+// TestDiscoverySkipsPartialClone tests that a directory with a clone in
+// progress isn't reported as a repo by discovery until the clone finishes.
+func TestDiscoverySkipsPartialClone(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalCachePath := cache.GetCachePath()
+	defer cache.SetCachePath(originalCachePath)
+	cache.SetCachePath(filepath.Join(tmpDir, "cache.json"))
+
+	// A clone in progress has already created its .git directory
+	t.Setenv("PATH", "")
+	scanPath := filepath.Join(tmpDir, "repos")
+	if err := os.MkdirAll(filepath.Join(scanPath, "incoming", ".git"), 0o755); err != nil {
+		t.Fatalf("Failed to create repo dir: %v", err)
+	}
+	if err := cache.WriteRepos([]model.Repo{}); err != nil {
+		t.Fatalf("WriteRepos failed: %v", err)
+	}
+
+	p := poller.NewPoller(&config.Config{ScanPath: scanPath, StaleDays: 30, AbandonedDays: 90}, sse.NewHub())
+
+	if !p.CloneStarted("incoming") {
+		t.Fatal("CloneStarted() = false, want true")
+	}
+	if p.CloneStarted("incoming") {
+		t.Error("second CloneStarted() = true, want false while the first is in progress")
+	}
+
+	changed, err := p.ReconcileCloneState()
+	if err != nil {
+		t.Fatalf("ReconcileCloneState failed: %v", err)
+	}
+	if changed != 0 {
+		t.Errorf("changed = %d during clone, want 0", changed)
+	}
+	if repos, _ := cache.ReadRepos(); len(repos) != 0 {
+		t.Errorf("repos during clone = %+v, want none", repos)
+	}
+
+	p.CloneFinished("incoming")
+	if _, err := p.ReconcileCloneState(); err != nil {
+		t.Fatalf("ReconcileCloneState failed: %v", err)
+	}
+	repos, _ := cache.ReadRepos()
+	if len(repos) != 1 || repos[0].Name != "incoming" || !repos[0].Cloned {
+		t.Errorf("repos after clone = %+v, want cloned incoming", repos)
+	}
+}
+
+// TestCloneTracking tests that a clone moves from queued through started
+// to completed, that a failed clone keeps its error in the history, and
+// that the history is capped.
+func TestCloneTracking(t *testing.T) {
+	p := poller.NewPoller(&config.Config{StaleDays: 30, AbandonedDays: 90}, sse.NewHub())
+
+	p.CloneStarted("catscan")
+	if clones := p.Clones(); len(clones) != 1 || clones[0].State != poller.CloneQueued || clones[0].StartedAt.IsZero() {
+		t.Fatalf("Clones() after CloneStarted = %+v, want one queued clone", clones)
+	}
+
+	p.CloneProgress(scanner.CloneStatus{Repo: "catscan", State: scanner.CloneStateStarted})
+	if clones := p.Clones(); clones[0].State != scanner.CloneStateStarted {
+		t.Errorf("state = %s, want started", clones[0].State)
+	}
+
+	p.CloneProgress(scanner.CloneStatus{Repo: "catscan", State: scanner.CloneStateCompleted})
+	p.CloneFinished("catscan")
+	clones := p.Clones()
+	if len(clones) != 1 || clones[0].State != scanner.CloneStateCompleted || clones[0].FinishedAt.IsZero() {
+		t.Fatalf("Clones() after completion = %+v, want one completed clone", clones)
+	}
+	if p.IsCloning("catscan") {
+		t.Error("IsCloning() = true after CloneFinished")
+	}
+
+	p.CloneStarted("dotfiles")
+	p.CloneProgress(scanner.CloneStatus{Repo: "dotfiles", State: scanner.CloneStateError, Error: "clone failed: exit status 128"})
+	p.CloneFinished("dotfiles")
+	p.CloneStarted("pending")
+
+	clones = p.Clones()
+	if len(clones) != 3 {
+		t.Fatalf("Clones() = %+v, want 3 entries", clones)
+	}
+	if clones[0].Repo != "pending" || clones[0].State != poller.CloneQueued {
+		t.Errorf("clones[0] = %+v, want the in-progress clone first", clones[0])
+	}
+	if clones[1].Repo != "dotfiles" || clones[1].State != scanner.CloneStateError || clones[1].Error != "clone failed: exit status 128" {
+		t.Errorf("clones[1] = %+v, want the failed clone with its error", clones[1])
+	}
+	if clones[2].Repo != "catscan" {
+		t.Errorf("clones[2] = %+v, want the oldest finished clone last", clones[2])
+	}
+
+	// A clone that ends without an outcome is recorded as failed
+	p.CloneFinished("pending")
+	if got := p.Clones()[0]; got.Repo != "pending" || got.State != scanner.CloneStateError || got.Error == "" {
+		t.Errorf("unfinished clone = %+v, want an error entry", got)
+	}
+
+	for i := range 60 {
+		name := fmt.Sprintf("repo-%d", i)
+		p.CloneStarted(name)
+		p.CloneFinished(name)
+	}
+	if got := len(p.Clones()); got != 50 {
+		t.Errorf("len(Clones()) = %d, want the history capped at 50", got)
+	}
+}
+
+// TestLocalPollBroadcastsSingleRepoChange tests that a poll changing one
+// field on one repo broadcasts a single repo_changed event with that repo
+// rather than the full list, while a poll that adds a repo sends the list.
+func TestLocalPollBroadcastsSingleRepoChange(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	tmpDir := t.TempDir()
+	originalCachePath := cache.GetCachePath()
+	defer cache.SetCachePath(originalCachePath)
+	cache.SetCachePath(filepath.Join(tmpDir, "cache.json"))
+
+	scanPath := filepath.Join(tmpDir, "repos")
+	for _, name := range []string{"app", "lib"} {
+		if output, err := exec.Command("git", "init", filepath.Join(scanPath, name)).CombinedOutput(); err != nil {
+			t.Fatalf("git init failed: %v (%s)", err, output)
+		}
+	}
+
+	hub := sse.NewHub()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go hub.Run(ctx)
+
+	client := &sse.Client{ID: "reader", Chan: make(chan sse.Event, 10), Ctx: ctx, Cancel: cancel}
+	hub.Register(client)
+
+	p := poller.NewPoller(&config.Config{ScanPath: scanPath, StaleDays: 30, AbandonedDays: 90}, hub)
+
+	// events drains what a poll broadcast, skipping unrelated events
+	events := func() []sse.Event {
+		t.Helper()
+		time.Sleep(50 * time.Millisecond)
+		var got []sse.Event
+		for len(client.Chan) > 0 {
+			event := <-client.Chan
+			if event.Type == "repos_updated" || event.Type == "repo_changed" {
+				got = append(got, event)
+			}
+		}
+		return got
+	}
+
+	// The second poll fills in names from the cache the first one wrote
+	p.LocalPoll(ctx)
+	p.LocalPoll(ctx)
+	if got := events(); len(got) == 0 || got[0].Type != "repos_updated" {
+		t.Fatalf("first poll events = %+v, want repos_updated", got)
+	}
+
+	if err := os.WriteFile(filepath.Join(scanPath, "app", "new.txt"), []byte("meow"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	p.LocalPoll(ctx)
+	got := events()
+	if len(got) != 1 || got[0].Type != "repo_changed" {
+		t.Fatalf("dirty poll events = %+v, want one repo_changed", got)
+	}
+	if repo := got[0].Data.(model.Repo); repo.Name != "app" || !repo.Dirty {
+		t.Errorf("repo_changed = %s (dirty %v), want dirty app", repo.Name, repo.Dirty)
+	}
+
+	p.LocalPoll(ctx)
+	if got := events(); len(got) != 0 {
+		t.Errorf("unchanged poll events = %+v, want none", got)
+	}
+
+	if output, err := exec.Command("git", "init", filepath.Join(scanPath, "tool")).CombinedOutput(); err != nil {
+		t.Fatalf("git init failed: %v (%s)", err, output)
+	}
+	p.LocalPoll(ctx)
+	if got := events(); len(got) != 1 || got[0].Type != "repos_updated" {
+		t.Errorf("poll adding a repo events = %+v, want one repos_updated", got)
+	}
+}
+
+// TestLocalPollKeepsGitHubFields tests that a local poll right after a
+// GitHub poll, with nothing changed on disk, rebuilds the GitHub side from
+// the cache without dropping any of it.
+func TestLocalPollKeepsGitHubFields(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	tmpDir := t.TempDir()
+	originalCachePath := cache.GetCachePath()
+	defer cache.SetCachePath(originalCachePath)
+	cache.SetCachePath(filepath.Join(tmpDir, "cache.json"))
+
+	scanPath := filepath.Join(tmpDir, "repos")
+	if output, err := exec.Command("git", "init", filepath.Join(scanPath, "app")).CombinedOutput(); err != nil {
+		t.Fatalf("git init failed: %v (%s)", err, output)
+	}
+
+	hub := sse.NewHub()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go hub.Run(ctx)
+
+	client := &sse.Client{ID: "reader", Chan: make(chan sse.Event, 64), Ctx: ctx, Cancel: cancel}
+	hub.Register(client)
+
+	cfg := &config.Config{
+		GitHubOwner:           "alexcatdad",
+		ScanPath:              scanPath,
+		GitHubIntervalSeconds: 60,
+		StaleDays:             30,
+		AbandonedDays:         90,
+	}
+	p := poller.NewPoller(cfg, hub)
+	p.SetFetchers(poller.Fetchers{
+		ListRepos: func(owner string) ([]scanner.GitHubRepo, error) {
+			return []scanner.GitHubRepo{
+				{Name: "app", Visibility: "public", DefaultBranch: &scanner.DefaultBranch{Name: "trunk"}},
+				{Name: "website", Visibility: "public", DefaultBranch: &scanner.DefaultBranch{Name: "trunk"}},
+			}, nil
+		},
+		PROpenCount:    func(owner, name string) (int, error) { return 3, nil },
+		IssueOpenCount: func(owner, name string) (int, error) { return 0, nil },
+		ActionsStatus:  func(owner, name string) (string, error) { return "failing", nil },
+		FilePresence: func(owner, name string) (*scanner.FilePresence, error) {
+			return &scanner.FilePresence{HasREADME: true, HasLICENSE: true}, nil
+		},
+		LatestRelease: func(owner, name string) (*scanner.LatestRelease, error) { return nil, nil },
+		ProjectFile:   func(owner, name string) (*scanner.ProjectInfo, error) { return nil, nil },
+	})
+	p.SetNotifyFunc(func(eventType, repo, message, url string) {})
+
+	cached := func() map[string]model.Repo {
+		t.Helper()
+		repos, err := cache.ReadRepos()
+		if err != nil {
+			t.Fatalf("ReadRepos failed: %v", err)
+		}
+		byName := make(map[string]model.Repo)
+		for _, repo := range repos {
+			byName[repo.Name] = repo
+		}
+		return byName
+	}
+	drain := func() []sse.Event {
+		t.Helper()
+		time.Sleep(50 * time.Millisecond)
+		var got []sse.Event
+		for len(client.Chan) > 0 {
+			event := <-client.Chan
+			switch event.Type {
+			case "repos_updated", "repo_changed", "changes":
+				got = append(got, event)
+			}
+		}
+		return got
+	}
+
+	p.LocalPoll(ctx)
+	p.GitHubPoll(ctx)
+	drain()
+	before := cached()
+
+	p.LocalPoll(ctx)
+	if got := drain(); len(got) != 0 {
+		t.Errorf("local poll after GitHub poll events = %+v, want none", got)
+	}
+
+	after := cached()
+	website := after["website"]
+	if website.OpenPRs != 3 || website.ActionsStatus != model.ActionsStatusFailing {
+		t.Errorf("website OpenPRs = %d, ActionsStatus = %s, want 3 and failing", website.OpenPRs, website.ActionsStatus)
+	}
+	if website.Completeness.Score != before["website"].Completeness.Score || !website.Completeness.HasReadme {
+		t.Errorf("website completeness = %+v, want %+v", website.Completeness, before["website"].Completeness)
+	}
+	for _, name := range []string{"app", "website"} {
+		if got := after[name].DefaultBranch; got != "trunk" {
+			t.Errorf("%s DefaultBranch = %q, want trunk", name, got)
+		}
+	}
+	if website.Branch != "trunk" {
+		t.Errorf("website Branch = %q, want trunk", website.Branch)
+	}
+}
+
+// TestSnoozeDuringPoll tests that user actions writing repo state can run
+// while a poll merges it. Run with -race to catch unguarded access.
+func TestSnoozeDuringPoll(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalCachePath := cache.GetCachePath()
+	defer cache.SetCachePath(originalCachePath)
+	cache.SetCachePath(filepath.Join(tmpDir, "cache.json"))
+
+	var repos []model.Repo
+	for i := range 20 {
+		repos = append(repos, model.Repo{Name: fmt.Sprintf("repo-%d", i), Visibility: model.VisibilityPublic})
+	}
+	if err := cache.WriteRepos(repos); err != nil {
+		t.Fatalf("WriteRepos failed: %v", err)
+	}
+
+	scanPath := filepath.Join(tmpDir, "repos")
+	if err := os.MkdirAll(scanPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+	p := poller.NewPoller(&config.Config{ScanPath: scanPath, StaleDays: 30, AbandonedDays: 90}, sse.NewHub())
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for range 20 {
+			p.LocalPoll(ctx)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := range 100 {
+			name := fmt.Sprintf("new-%d", i)
+			if err := p.Snooze(name, time.Now().Add(time.Hour)); err != nil {
+				t.Errorf("Snooze(%s) failed: %v", name, err)
+				return
+			}
+		}
+	}()
+	wg.Wait()
+}
@@ -0,0 +1,153 @@
+// Package poller manages background polling for local and GitHub data.
+//
+// The reconcile subpackage updates clone state from a directory stat alone,
+// without the per-repo git commands of a full local poll.
+package poller
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"time"
+
+	"github.com/alexcatdad/catscan/internal/cache"
+	"github.com/alexcatdad/catscan/internal/model"
+	"github.com/alexcatdad/catscan/internal/scanner"
+)
+
+// CloneStateInterval returns the configured clone-state reconcile interval.
+// Zero means the fast reconcile is disabled.
+func (p *Poller) CloneStateInterval() time.Duration {
+	return time.Duration(p.config().CloneStateIntervalSeconds) * time.Second
+}
+
+// reconcileCloneState updates Cloned and LocalPath for every cached repo by
+// checking which repos have a .git directory under the scan path. Repos
+// cloned since the last poll are added with clone state only; their git
+// state is filled in by the next full local poll.
+// The repo list is written and broadcast only when something changed.
+// Returns the number of repos whose clone state changed.
+func (p *Poller) reconcileCloneState() (int, error) {
+	cfg := p.config()
+
+	repos, err := cache.ReadRepos()
+	if err != nil {
+		return 0, fmt.Errorf("reading cache: %w", err)
+	}
+
+	localNames, err := p.discoverLocalRepos(cfg)
+	if err != nil {
+		return 0, fmt.Errorf("discovering local repos: %w", err)
+	}
+
+	// Check cached repos and anything new on disk in one pass, by directory
+	// name. Repos matched to a differently named directory (see
+	// config.MatchLocalByContent) are checked under that directory.
+	dirs := make([]string, len(repos))
+	known := make(map[string]bool, len(repos))
+	names := make([]string, 0, len(repos)+len(localNames))
+	for i, repo := range repos {
+		dirs[i] = repo.Name
+		if repo.Cloned && filepath.Dir(repo.LocalPath) == filepath.Clean(cfg.ScanPath) {
+			dirs[i] = filepath.Base(repo.LocalPath)
+		}
+		known[dirs[i]] = true
+		names = append(names, dirs[i])
+	}
+	for _, name := range localNames {
+		if !known[name] {
+			names = append(names, name)
+		}
+	}
+	cloned := scanner.FindClonedRepos(names, cfg.ScanPath)
+
+	changed := 0
+	for i := range repos {
+		// A half-cloned directory is settled by the next poll after the clone
+		if p.IsCloning(repos[i].Name) {
+			continue
+		}
+		path, isCloned := cloned[dirs[i]]
+		if !isCloned {
+			path = fmt.Sprintf("%s/%s", cfg.ScanPath, repos[i].Name)
+		}
+		if repos[i].Cloned == isCloned && repos[i].LocalPath == path {
+			continue
+		}
+		repos[i].Cloned = isCloned
+		repos[i].LocalPath = path
+		changed++
+	}
+
+	thresholds := p.lifecycleThresholds()
+	for _, name := range localNames {
+		path, isCloned := cloned[name]
+		if known[name] || !isCloned {
+			continue
+		}
+		repo := model.Repo{Name: name, Cloned: true, LocalPath: path}
+		repo.Lifecycle = repo.ComputeLifecycle(thresholds)
+		repos = append(repos, repo)
+		changed++
+	}
+
+	if changed == 0 {
+		return 0, nil
+	}
+
+	// Update cache before any broadcast about these repos
+	p.writeCache(repos)
+	p.broadcastRepos("repos_updated", repos)
+	p.setPreviousRepos(repos)
+
+	return changed, nil
+}
+
+// runReconcile runs a clone-state reconcile and logs any error.
+func (p *Poller) runReconcile() {
+	if _, err := p.reconcileCloneState(); err != nil {
+		slog.Error("clone state reconcile failed", "err", err)
+	}
+}
+
+// optionalTicker is a ticker that is disabled by a zero interval.
+// A disabled ticker's channel is nil and never fires.
+type optionalTicker struct {
+	ticker *time.Ticker
+}
+
+// newOptionalTicker creates an optionalTicker firing every d, or never if d is zero.
+func newOptionalTicker(d time.Duration) *optionalTicker {
+	t := &optionalTicker{}
+	t.Reset(d)
+	return t
+}
+
+// C returns the tick channel, or nil when disabled.
+func (t *optionalTicker) C() <-chan time.Time {
+	if t.ticker == nil {
+		return nil
+	}
+	return t.ticker.C
+}
+
+// Reset changes the interval. A zero interval disables the ticker.
+func (t *optionalTicker) Reset(d time.Duration) {
+	if d <= 0 {
+		t.Stop()
+		return
+	}
+	if t.ticker == nil {
+		t.ticker = time.NewTicker(d)
+		return
+	}
+	t.ticker.Reset(d)
+}
+
+// Stop stops the ticker.
+func (t *optionalTicker) Stop() {
+	if t.ticker != nil {
+		t.ticker.Stop()
+		t.ticker = nil
+	}
+}
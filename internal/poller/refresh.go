@@ -0,0 +1,247 @@
+// Package poller manages background polling for local and GitHub data.
+//
+// The refresh subpackage re-fetches a single GitHub field across all repos
+// without running a full poll cycle.
+package poller
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/alexcatdad/catscan/internal/cache"
+	"github.com/alexcatdad/catscan/internal/model"
+	"github.com/alexcatdad/catscan/internal/scanner"
+)
+
+// Refreshable fields accepted by RefreshField.
+const (
+	RefreshFieldActions  = "actions"
+	RefreshFieldPRs      = "prs"
+	RefreshFieldIssues   = "issues"
+	RefreshFieldFiles    = "files"
+	RefreshFieldReleases = "releases"
+)
+
+// refreshConcurrency bounds the number of concurrent gh calls during a refresh.
+const refreshConcurrency = 4
+
+// IsValidRefreshField returns true if field can be passed to RefreshField.
+func IsValidRefreshField(field string) bool {
+	switch field {
+	case RefreshFieldActions, RefreshFieldPRs, RefreshFieldIssues, RefreshFieldFiles, RefreshFieldReleases:
+		return true
+	default:
+		return false
+	}
+}
+
+// refreshRequest asks the GitHub loop to refresh one field.
+type refreshRequest struct {
+	ctx    context.Context
+	field  string
+	result chan refreshResult
+}
+
+// refreshResult is the outcome of a refreshRequest.
+type refreshResult struct {
+	count int
+	err   error
+}
+
+// RefreshField re-fetches a single field for every cached repo that exists on
+// GitHub, updates the cache, and broadcasts the result. The refresh runs on
+// the GitHub poll loop, so it waits for a poll in progress to finish.
+// Returns the number of repos refreshed.
+func (p *Poller) RefreshField(ctx context.Context, field string) (int, error) {
+	if !IsValidRefreshField(field) {
+		return 0, fmt.Errorf("invalid refresh field: %s", field)
+	}
+
+	req := refreshRequest{ctx: ctx, field: field, result: make(chan refreshResult, 1)}
+	select {
+	case p.refreshRequests <- req:
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+	select {
+	case res := <-req.result:
+		return res.count, res.err
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+// refreshField runs a refresh for RefreshField on the GitHub loop. The
+// fetches can take a while, so the cache is read again afterwards and only
+// the refreshed field is patched in, keeping what local polls and user
+// actions wrote in the meantime.
+func (p *Poller) refreshField(ctx context.Context, field string) (int, error) {
+	repos, err := cache.ReadRepos()
+	if err != nil {
+		return 0, fmt.Errorf("reading cache: %w", err)
+	}
+
+	sem := make(chan struct{}, refreshConcurrency)
+	var wg sync.WaitGroup
+	fetched := make(map[string]model.Repo)
+	var fetchedMu sync.Mutex
+
+	for i := range repos {
+		// Local-only repos have nothing to fetch from GitHub
+		if repos[i].Visibility == "" {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return len(fetched), ctx.Err()
+		case sem <- struct{}{}:
+		}
+
+		wg.Add(1)
+		go func(repo model.Repo) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := p.refreshRepoField(&repo, field); err != nil {
+				slog.Warn("refresh failed", "field", field, "repo", repo.Name, "err", err)
+				return
+			}
+			fetchedMu.Lock()
+			fetched[repo.Name] = repo
+			fetchedMu.Unlock()
+		}(repos[i])
+	}
+	wg.Wait()
+
+	repos, err = cache.ReadRepos()
+	if err != nil {
+		return len(fetched), fmt.Errorf("reading cache: %w", err)
+	}
+
+	// Patch the refreshed field and recompute what derives from it
+	thresholds := p.lifecycleThresholds()
+	for i := range repos {
+		src, ok := fetched[repos[i].Name]
+		if !ok {
+			continue
+		}
+		copyRefreshedField(&repos[i], src, field)
+		repos[i].Lifecycle = repos[i].ComputeLifecycle(thresholds)
+		repos[i].NeedsAttention = repos[i].ComputeNeedsAttention()
+		repos[i].Completeness.Score = repos[i].CompletenessScore()
+		repos[i].SuggestArchive = repos[i].ComputeSuggestArchive()
+		repos[i].IsNew = repos[i].ComputeIsNew(thresholds.NewRepoDays)
+	}
+
+	// Update cache before any broadcast about these repos
+	p.writeCache(repos)
+
+	diff := p.detectAndEmitChanges(repos, "refresh")
+
+	if field == RefreshFieldReleases {
+		p.updateReleaseState(repos)
+	}
+
+	p.broadcastRepoChanges("github_updated", repos, diff)
+	p.setPreviousRepos(repos)
+
+	return len(fetched), nil
+}
+
+// copyRefreshedField copies field, as set by refreshRepoField, from src to dst.
+func copyRefreshedField(dst *model.Repo, src model.Repo, field string) {
+	switch field {
+	case RefreshFieldActions:
+		dst.ActionsStatus = src.ActionsStatus
+	case RefreshFieldPRs:
+		dst.OpenPRs = src.OpenPRs
+	case RefreshFieldIssues:
+		dst.OpenIssues = src.OpenIssues
+	case RefreshFieldFiles:
+		dst.Completeness.HasReadme = src.Completeness.HasReadme
+		dst.Completeness.HasLicense = src.Completeness.HasLicense
+		dst.Completeness.HasClaudeMd = src.Completeness.HasClaudeMd
+		dst.Completeness.HasAgentsMd = src.Completeness.HasAgentsMd
+		dst.Completeness.HasProjectJson = src.Completeness.HasProjectJson
+	case RefreshFieldReleases:
+		dst.LatestRelease = src.LatestRelease
+		dst.NewRelease = src.NewRelease
+	}
+}
+
+// refreshRepoField fetches a single field for one repo and applies it.
+func (p *Poller) refreshRepoField(repo *model.Repo, field string) error {
+	owner := cmp.Or(repo.Owner, p.config().GitHubOwner)
+
+	switch field {
+	case RefreshFieldActions:
+		status, err := p.fetchers.ActionsStatus(owner, repo.Name)
+		if err != nil {
+			return err
+		}
+		repo.ActionsStatus = model.ActionsStatus(status)
+
+	case RefreshFieldPRs:
+		count, err := p.fetchers.PROpenCount(owner, repo.Name)
+		if err != nil {
+			return err
+		}
+		repo.OpenPRs = count
+
+	case RefreshFieldIssues:
+		count, err := p.fetchers.IssueOpenCount(owner, repo.Name)
+		if err != nil {
+			return err
+		}
+		repo.OpenIssues = count
+
+	case RefreshFieldFiles:
+		// Cloned repos read the working tree, as polls do
+		var presence *scanner.FilePresence
+		if repo.Cloned {
+			presence = scanner.LocalFilePresence(repo.LocalPath)
+		}
+		if presence == nil {
+			var err error
+			if presence, err = p.fetchers.FilePresence(owner, repo.Name); err != nil {
+				return err
+			}
+		}
+		if presence != nil {
+			repo.Completeness.HasReadme = presence.HasREADME
+			repo.Completeness.HasLicense = presence.HasLICENSE
+			repo.Completeness.HasClaudeMd = presence.HasCLAUDEmd
+			repo.Completeness.HasAgentsMd = presence.HasAGENTSmd
+			repo.Completeness.HasProjectJson = presence.HasProjectJson
+		}
+
+	case RefreshFieldReleases:
+		release, err := p.fetchers.LatestRelease(owner, repo.Name)
+		if err != nil {
+			return err
+		}
+		if release == nil {
+			repo.LatestRelease = nil
+			repo.NewRelease = false
+			return nil
+		}
+		pubTime, _ := time.Parse(time.RFC3339, release.PublishedAt)
+		repo.LatestRelease = &model.ReleaseInfo{
+			TagName:     release.TagName,
+			PublishedAt: pubTime.UTC(),
+		}
+
+		p.stateMu.RLock()
+		entry := p.state[repo.Name]
+		repo.NewRelease = entry == nil || entry.LastSeenReleaseTag != release.TagName
+		p.stateMu.RUnlock()
+	}
+
+	return nil
+}
@@ -0,0 +1,53 @@
+// Package poller manages background polling for local and GitHub data.
+//
+// The webhook subpackage delivers notifications as JSON POSTs to a URL.
+package poller
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// webhookTimeout bounds a single webhook delivery so a slow endpoint
+// cannot stall a poll cycle.
+const webhookTimeout = 5 * time.Second
+
+// webhookClient is shared across deliveries.
+var webhookClient = &http.Client{Timeout: webhookTimeout}
+
+// WebhookPayload is the JSON body POSTed to the configured webhook URL.
+type WebhookPayload struct {
+	EventType string    `json:"eventType"`
+	Repo      string    `json:"repo"`
+	Message   string    `json:"message"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// SendWebhook POSTs a notification to url.
+// Any non-2xx response is returned as an error.
+func SendWebhook(url, eventType, repoName, message string) error {
+	body, err := json.Marshal(WebhookPayload{
+		EventType: eventType,
+		Repo:      repoName,
+		Message:   message,
+		Timestamp: time.Now().UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("marshaling webhook payload: %w", err)
+	}
+
+	resp, err := webhookClient.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook: unexpected status %d", resp.StatusCode)
+	}
+
+	return nil
+}
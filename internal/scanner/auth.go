@@ -0,0 +1,117 @@
+// Package scanner provides repository scanning functionality.
+//
+// The auth subpackage checks that the gh token can read everything CatScan
+// polls, so missing scopes show up as a warning instead of repos that
+// silently look not-found.
+package scanner
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"slices"
+	"strings"
+)
+
+// RequiredGHScopes lists the gh token scopes CatScan needs. The repo scope
+// grants access to private repositories.
+var RequiredGHScopes = []string{"repo"}
+
+// GHAuthStatus describes the account gh is logged in to.
+type GHAuthStatus struct {
+	Login string
+	Host  string
+
+	// MissingScopes lists the RequiredGHScopes the token lacks.
+	MissingScopes []string
+}
+
+// CheckGHAuth runs gh auth status and returns the logged-in account and
+// the required scopes its token lacks. An error means gh is missing or not
+// authenticated.
+func CheckGHAuth() (*GHAuthStatus, error) {
+	ghPath, err := findGH()
+	if err != nil {
+		return nil, err
+	}
+
+	// Older gh versions print the status to stderr, newer ones to stdout
+	var output bytes.Buffer
+	cmd := exec.Command(ghPath, "auth", "status")
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	if err := cmd.Run(); err != nil {
+		return nil, NewGHAuthError(strings.TrimSpace(output.String()))
+	}
+
+	return ParseGHAuthStatus(output.String()), nil
+}
+
+// ParseGHAuthStatus parses gh auth status output. Login and Host are from
+// the first "Logged in to" line, and are empty when there is none.
+func ParseGHAuthStatus(authStatus string) *GHAuthStatus {
+	status := &GHAuthStatus{MissingScopes: MissingGHScopes(authStatus)}
+	for _, line := range strings.Split(authStatus, "\n") {
+		_, rest, found := strings.Cut(line, "Logged in to ")
+		if !found {
+			continue
+		}
+		// "github.com account alexcatdad (keyring)" on newer gh,
+		// "github.com as alexcatdad (oauth_token)" on older
+		fields := strings.Fields(rest)
+		if len(fields) >= 3 && (fields[1] == "account" || fields[1] == "as") {
+			status.Host = fields[0]
+			status.Login = fields[2]
+		}
+		break
+	}
+	return status
+}
+
+// MissingGHScopes parses gh auth status output and returns the required
+// scopes absent from the reported token scopes. Output that doesn't report
+// scopes, as with fine-grained tokens, is not treated as missing any.
+func MissingGHScopes(authStatus string) []string {
+	scopes, ok := parseGHScopes(authStatus)
+	if !ok {
+		return nil
+	}
+
+	var missing []string
+	for _, scope := range RequiredGHScopes {
+		if !slices.Contains(scopes, scope) {
+			missing = append(missing, scope)
+		}
+	}
+	return missing
+}
+
+// parseGHScopes extracts the token scopes from a "Token scopes:" line, e.g.
+// "- Token scopes: 'gist', 'read:org', 'repo'". ok is false when no such
+// line is present.
+func parseGHScopes(authStatus string) (scopes []string, ok bool) {
+	for _, line := range strings.Split(authStatus, "\n") {
+		_, list, found := strings.Cut(line, "Token scopes:")
+		if !found {
+			continue
+		}
+		for _, scope := range strings.Split(list, ",") {
+			scope = strings.Trim(strings.TrimSpace(scope), `'"`)
+			if scope != "" && scope != "none" {
+				scopes = append(scopes, scope)
+			}
+		}
+		return scopes, true
+	}
+	return nil, false
+}
+
+// GHScopeWarning describes missing gh token scopes for display, or returns
+// "" when none are missing.
+func GHScopeWarning(missing []string) string {
+	if len(missing) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("gh token is missing scopes %s; private repos will not be visible. Run 'gh auth refresh -s %s'.",
+		strings.Join(missing, ", "), strings.Join(missing, ","))
+}
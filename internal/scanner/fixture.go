@@ -0,0 +1,87 @@
+// Package scanner provides repository scanning functionality.
+//
+// The fixture subpackage serves GitHub data from local JSON files instead of
+// the gh CLI, for offline demos and tests.
+package scanner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+var (
+	fixtureDir   string
+	fixtureDirMu sync.RWMutex
+)
+
+// SetFixtureDir points all GitHub fetches at a local fixture directory.
+// While set, no gh subprocess is ever run. An empty dir restores gh.
+//
+// The directory contains:
+//   - repos.json: a gh repo list --json payload
+//   - details.json: per-repo enrichment keyed by repo name
+func SetFixtureDir(dir string) {
+	fixtureDirMu.Lock()
+	defer fixtureDirMu.Unlock()
+	fixtureDir = dir
+}
+
+// FixtureDir returns the current fixture directory, or "" if gh is used.
+func FixtureDir() string {
+	fixtureDirMu.RLock()
+	defer fixtureDirMu.RUnlock()
+	return fixtureDir
+}
+
+// fixtureDetails holds per-repo data normally fetched with separate gh calls.
+type fixtureDetails struct {
+	OpenPRs       int            `json:"openPRs"`
+	OpenIssues    int            `json:"openIssues"`
+	ActionsStatus string         `json:"actionsStatus"`
+	FilePresence  *FilePresence  `json:"filePresence"`
+	LatestRelease *LatestRelease `json:"latestRelease"`
+	Project       *ProjectInfo   `json:"project"`
+}
+
+// readFixtureRepos reads repos.json from the fixture directory.
+func readFixtureRepos(dir string) ([]GitHubRepo, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "repos.json"))
+	if err != nil {
+		return nil, fmt.Errorf("reading fixture repos: %w", err)
+	}
+
+	var repos []GitHubRepo
+	if err := json.Unmarshal(data, &repos); err != nil {
+		return nil, fmt.Errorf("parsing fixture repos JSON: %w", err)
+	}
+
+	return repos, nil
+}
+
+// readFixtureDetails reads the details.json entry for a repo.
+// A missing file or entry returns zero details.
+func readFixtureDetails(dir, name string) (fixtureDetails, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "details.json"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fixtureDetails{ActionsStatus: "none"}, nil
+		}
+		return fixtureDetails{}, fmt.Errorf("reading fixture details: %w", err)
+	}
+
+	var details map[string]fixtureDetails
+	if err := json.Unmarshal(data, &details); err != nil {
+		return fixtureDetails{}, fmt.Errorf("parsing fixture details JSON: %w", err)
+	}
+
+	entry, ok := details[name]
+	if !ok || entry.ActionsStatus == "" {
+		entry.ActionsStatus = "none"
+	}
+
+	return entry, nil
+}
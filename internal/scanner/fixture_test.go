@@ -0,0 +1,86 @@
+package scanner_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alexcatdad/catscan/internal/scanner"
+)
+
+// TestFixtureDirShortCircuitsGH tests that fixture mode serves data from files without running gh.
+func TestFixtureDirShortCircuitsGH(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// Fake gh on PATH that records any invocation
+	binDir := filepath.Join(tmpDir, "bin")
+	marker := filepath.Join(tmpDir, "gh-called")
+	if err := os.MkdirAll(binDir, 0o755); err != nil {
+		t.Fatalf("Failed to create bin dir: %v", err)
+	}
+	script := "#!/bin/sh\ntouch " + marker + "\necho '[]'\n"
+	if err := os.WriteFile(filepath.Join(binDir, "gh"), []byte(script), 0o755); err != nil {
+		t.Fatalf("Failed to write fake gh: %v", err)
+	}
+	t.Setenv("PATH", binDir)
+
+	// Fixture files
+	fixtureDir := filepath.Join(tmpDir, "fixtures")
+	if err := os.MkdirAll(fixtureDir, 0o755); err != nil {
+		t.Fatalf("Failed to create fixture dir: %v", err)
+	}
+	repos := `[{"name":"fixture-repo","description":"From a file","visibility":"public","pushedAt":"2025-01-01T00:00:00Z"}]`
+	details := `{"fixture-repo":{"openPRs":4,"actionsStatus":"failing","filePresence":{"HasREADME":true},"latestRelease":{"tagName":"v2.0.0","publishedAt":"2025-01-01T00:00:00Z"}}}`
+	if err := os.WriteFile(filepath.Join(fixtureDir, "repos.json"), []byte(repos), 0o644); err != nil {
+		t.Fatalf("Failed to write repos fixture: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(fixtureDir, "details.json"), []byte(details), 0o644); err != nil {
+		t.Fatalf("Failed to write details fixture: %v", err)
+	}
+
+	scanner.SetFixtureDir(fixtureDir)
+	defer scanner.SetFixtureDir("")
+
+	list, err := scanner.ListGitHubRepos("someone")
+	if err != nil {
+		t.Fatalf("ListGitHubRepos() failed: %v", err)
+	}
+	if len(list) != 1 || list[0].Name != "fixture-repo" || list[0].Description != "From a file" {
+		t.Errorf("ListGitHubRepos() = %+v, want fixture-repo from file", list)
+	}
+
+	prs, err := scanner.GetPROpenCount("someone", "fixture-repo")
+	if err != nil || prs != 4 {
+		t.Errorf("GetPROpenCount() = %d, %v, want 4", prs, err)
+	}
+
+	status, err := scanner.GetActionsStatus("someone", "fixture-repo")
+	if err != nil || status != "failing" {
+		t.Errorf("GetActionsStatus() = %s, %v, want failing", status, err)
+	}
+
+	presence, err := scanner.GetFilePresence("someone", "fixture-repo")
+	if err != nil || presence == nil || !presence.HasREADME {
+		t.Errorf("GetFilePresence() = %+v, %v, want HasREADME", presence, err)
+	}
+
+	release, err := scanner.GetLatestRelease("someone", "fixture-repo")
+	if err != nil || release == nil || release.TagName != "v2.0.0" {
+		t.Errorf("GetLatestRelease() = %+v, %v, want v2.0.0", release, err)
+	}
+
+	// Unknown repos get zero details
+	status, err = scanner.GetActionsStatus("someone", "unknown-repo")
+	if err != nil || status != "none" {
+		t.Errorf("GetActionsStatus(unknown) = %s, %v, want none", status, err)
+	}
+
+	// Calls without fixture support fail rather than falling back to gh
+	if _, err := scanner.GetOwnerInfo("someone"); err == nil {
+		t.Error("GetOwnerInfo() error = nil, want error in fixture mode")
+	}
+
+	if _, err := os.Stat(marker); err == nil {
+		t.Error("gh subprocess was run in fixture mode")
+	}
+}
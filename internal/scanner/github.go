@@ -6,8 +6,11 @@ package scanner
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os/exec"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -29,10 +32,11 @@ func (e *ghNotFoundError) Error() string {
 	return e.msg
 }
 
-// IsGHNotFound returns true if the error indicates gh CLI was not found.
+// IsGHNotFound returns true if the error, or any error it wraps, indicates
+// gh CLI was not found.
 func IsGHNotFound(err error) bool {
-	_, ok := err.(*ghNotFoundError)
-	return ok
+	var target *ghNotFoundError
+	return errors.As(err, &target)
 }
 
 // ghAuthError is returned when gh CLI is not authenticated.
@@ -44,10 +48,90 @@ func (e *ghAuthError) Error() string {
 	return e.msg
 }
 
-// IsGHAuthError returns true if the error indicates gh authentication failure.
+// NewGHAuthError returns an error reporting that gh is not authenticated,
+// with stderr from the failed gh command.
+func NewGHAuthError(stderr string) error {
+	return &ghAuthError{msg: "gh CLI not authenticated: " + stderr}
+}
+
+// IsGHAuthError returns true if the error, or any error it wraps, indicates
+// gh authentication failure.
 func IsGHAuthError(err error) bool {
-	_, ok := err.(*ghAuthError)
-	return ok
+	var target *ghAuthError
+	return errors.As(err, &target)
+}
+
+// ghRateLimitError is returned when the GitHub API rate limit is exhausted.
+type ghRateLimitError struct {
+	msg   string
+	reset time.Time
+}
+
+func (e *ghRateLimitError) Error() string {
+	return e.msg
+}
+
+// IsGHRateLimit returns true if the error, or any error it wraps, indicates
+// the GitHub API rate limit is exhausted.
+func IsGHRateLimit(err error) bool {
+	var target *ghRateLimitError
+	return errors.As(err, &target)
+}
+
+// RateLimitReset returns when the rate limit behind err resets.
+// It returns the zero time if err is not a rate-limit error or gh did not
+// report a reset time.
+func RateLimitReset(err error) time.Time {
+	var target *ghRateLimitError
+	if errors.As(err, &target) {
+		return target.reset
+	}
+	return time.Time{}
+}
+
+// rateLimitResetPattern matches the reset time gh reports with a rate-limit
+// failure, either as an X-Ratelimit-Reset header (Unix seconds) or as an
+// RFC3339 timestamp after "reset".
+var rateLimitResetPattern = regexp.MustCompile(`(?i)x-ratelimit-reset:\s*(\d+)|resets?\s+(?:at\s+)?(\d{4}-\d{2}-\d{2}T[0-9:]+(?:Z|[+-]\d{2}:\d{2}))`)
+
+// parseRateLimitReset extracts the rate-limit reset time from gh stderr.
+// Returns the zero time if none is present.
+func parseRateLimitReset(stderr string) time.Time {
+	m := rateLimitResetPattern.FindStringSubmatch(stderr)
+	if m == nil {
+		return time.Time{}
+	}
+	if m[1] != "" {
+		secs, err := strconv.ParseInt(m[1], 10, 64)
+		if err != nil {
+			return time.Time{}
+		}
+		return time.Unix(secs, 0).UTC()
+	}
+	t, err := time.Parse(time.RFC3339, m[2])
+	if err != nil {
+		return time.Time{}
+	}
+	return t.UTC()
+}
+
+// ParseGHError classifies a failed gh command from its stderr, returning a
+// typed error for authentication and rate-limit failures and a generic
+// wrapped error otherwise.
+func ParseGHError(stderr string, runErr error, args ...string) error {
+	// Check for authentication failure
+	if strings.Contains(stderr, "not authenticated") || strings.Contains(stderr, "GH_ENTERPRISE_TOKEN") || strings.Contains(stderr, "GitHub Credentials") {
+		return NewGHAuthError(stderr)
+	}
+	// Check for rate-limit exhaustion (primary or secondary limit)
+	lower := strings.ToLower(stderr)
+	if strings.Contains(lower, "rate limit exceeded") || (strings.Contains(lower, "http 403") && strings.Contains(lower, "rate limit")) {
+		return &ghRateLimitError{
+			msg:   "GitHub API rate limit exceeded: " + strings.TrimSpace(stderr),
+			reset: parseRateLimitReset(stderr),
+		}
+	}
+	return fmt.Errorf("gh %v: %w (stderr: %s)", args, runErr, stderr)
 }
 
 // findGH returns the path to the gh CLI binary, or an error if not found.
@@ -68,7 +152,12 @@ func findGH() (string, error) {
 }
 
 // runGH executes a gh command and returns the stdout.
+// In fixture mode gh is never run and every call fails.
 func runGH(args ...string) (string, error) {
+	if dir := FixtureDir(); dir != "" {
+		return "", fmt.Errorf("gh %v: disabled while reading GitHub data from fixtures in %s", args, dir)
+	}
+
 	ghPath, err := findGH()
 	if err != nil {
 		return "", err
@@ -81,12 +170,7 @@ func runGH(args ...string) (string, error) {
 	cmd.Stderr = &stderr
 
 	if err := cmd.Run(); err != nil {
-		errMsg := stderr.String()
-		// Check for authentication failure
-		if strings.Contains(errMsg, "not authenticated") || strings.Contains(errMsg, "GH_ENTERPRISE_TOKEN") || strings.Contains(errMsg, "GitHub Credentials") {
-			return "", &ghAuthError{msg: "gh CLI not authenticated: " + errMsg}
-		}
-		return "", fmt.Errorf("gh %v: %w (stderr: %s)", args, err, errMsg)
+		return "", ParseGHError(stderr.String(), err, args...)
 	}
 
 	return stdout.String(), nil
@@ -99,21 +183,33 @@ type RepositoryTopic struct {
 
 // GitHubRepo represents a GitHub repository from the gh CLI.
 type GitHubRepo struct {
-	Name            string             `json:"name"`
-	Description     string             `json:"description"`
-	Visibility      string             `json:"visibility"`
-	HomepageURL     string             `json:"homepageUrl"`
-	PrimaryLanguage *PrimaryLanguage   `json:"primaryLanguage"`
-	Topics          []RepositoryTopic  `json:"repositoryTopics"`
-	DefaultBranch   *DefaultBranch     `json:"defaultBranchRef"`
-	LatestRelease   *LatestRelease     `json:"latestRelease"`
-	PushedAt        string             `json:"pushedAt"`
-	IsArchived      bool               `json:"isArchived"`
+	Name                  string            `json:"name"`
+	Description           string            `json:"description"`
+	Visibility            string            `json:"visibility"`
+	HomepageURL           string            `json:"homepageUrl"`
+	PrimaryLanguage       *PrimaryLanguage  `json:"primaryLanguage"`
+	Topics                []RepositoryTopic `json:"repositoryTopics"`
+	DefaultBranch         *DefaultBranch    `json:"defaultBranchRef"`
+	LatestRelease         *LatestRelease    `json:"latestRelease"`
+	CreatedAt             string            `json:"createdAt"`
+	PushedAt              string            `json:"pushedAt"`
+	IsArchived            bool              `json:"isArchived"`
+	IsFork                bool              `json:"isFork"`
+	StargazerCount        int               `json:"stargazerCount"`
+	ForkCount             int               `json:"forkCount"`
+	HasWikiEnabled        bool              `json:"hasWikiEnabled"`
+	HasDiscussionsEnabled bool              `json:"hasDiscussionsEnabled"`
+
+	// Owner is set when the repo was listed from an org other than the
+	// configured owner (not from gh repo list JSON)
+	Owner string `json:"-"`
 
 	// Per-repo data fetched separately (not from gh repo list JSON)
 	OpenPRs       int           `json:"-"`
+	OpenIssues    int           `json:"-"`
 	ActionsStatus string        `json:"-"`
 	FilePresence  *FilePresence `json:"-"`
+	Project       *ProjectInfo  `json:"-"`
 }
 
 // PrimaryLanguage represents the primary programming language.
@@ -132,9 +228,27 @@ type LatestRelease struct {
 	PublishedAt string `json:"publishedAt"`
 }
 
+// ListUserOrgs returns the login of every organization the authenticated
+// user is a member of. Fixtures have no orgs.
+func ListUserOrgs() ([]string, error) {
+	if dir := FixtureDir(); dir != "" {
+		return nil, nil
+	}
+
+	output, err := runGH("api", "user/orgs", "--paginate", "--jq", ".[].login")
+	if err != nil {
+		return nil, fmt.Errorf("listing orgs: %w", err)
+	}
+	return strings.Fields(output), nil
+}
+
 // ListGitHubRepos lists all repositories for the given owner using gh CLI.
 func ListGitHubRepos(owner string) ([]GitHubRepo, error) {
-	output, err := runGH("repo", "list", owner, "--json", "name,description,visibility,homepageUrl,primaryLanguage,repositoryTopics,defaultBranchRef,latestRelease,pushedAt,isArchived", "--limit", "200")
+	if dir := FixtureDir(); dir != "" {
+		return readFixtureRepos(dir)
+	}
+
+	output, err := runGH("repo", "list", owner, "--json", "name,description,visibility,homepageUrl,primaryLanguage,repositoryTopics,defaultBranchRef,latestRelease,createdAt,pushedAt,isArchived,isFork,stargazerCount,forkCount,hasWikiEnabled,hasDiscussionsEnabled", "--limit", "200")
 	if err != nil {
 		return nil, fmt.Errorf("listing repos: %w", err)
 	}
@@ -153,6 +267,11 @@ func ListGitHubRepos(owner string) ([]GitHubRepo, error) {
 
 // GetPROpenCount returns the count of open pull requests for a repository.
 func GetPROpenCount(owner, name string) (int, error) {
+	if dir := FixtureDir(); dir != "" {
+		details, err := readFixtureDetails(dir, name)
+		return details.OpenPRs, err
+	}
+
 	output, err := runGH("pr", "list", "--repo", fmt.Sprintf("%s/%s", owner, name), "--state", "open", "--json", "number", "--limit", "100")
 	if err != nil {
 		return 0, fmt.Errorf("listing PRs: %w", err)
@@ -173,6 +292,46 @@ func GetPROpenCount(owner, name string) (int, error) {
 	return len(prs), nil
 }
 
+// GetIssueOpenCount returns the count of open issues for a repository.
+func GetIssueOpenCount(owner, name string) (int, error) {
+	if dir := FixtureDir(); dir != "" {
+		details, err := readFixtureDetails(dir, name)
+		return details.OpenIssues, err
+	}
+
+	output, err := runGH("issue", "list", "--repo", fmt.Sprintf("%s/%s", owner, name), "--state", "open", "--json", "number", "--limit", "100")
+	if err != nil {
+		return 0, fmt.Errorf("listing issues: %w", err)
+	}
+
+	return ParseIssueList([]byte(output))
+}
+
+// ParseIssueList parses gh issue list --json number output into a count.
+func ParseIssueList(data []byte) (int, error) {
+	if strings.TrimSpace(string(data)) == "" {
+		return 0, nil
+	}
+
+	var issues []struct {
+		Number int `json:"number"`
+	}
+	if err := json.Unmarshal(data, &issues); err != nil {
+		return 0, fmt.Errorf("parsing issue list JSON: %w", err)
+	}
+
+	return len(issues), nil
+}
+
+// ArchiveRepo archives a repository on GitHub. This cannot be undone from
+// catscan; unarchiving requires the GitHub UI or gh repo unarchive.
+func ArchiveRepo(owner, name string) error {
+	if _, err := runGH("repo", "archive", fmt.Sprintf("%s/%s", owner, name), "--yes"); err != nil {
+		return fmt.Errorf("archiving repo: %w", err)
+	}
+	return nil
+}
+
 // ActionsWorkflowRun represents a GitHub Actions workflow run.
 type ActionsWorkflowRun struct {
 	Status     string `json:"status"`
@@ -181,6 +340,14 @@ type ActionsWorkflowRun struct {
 
 // GetActionsStatus returns the latest Actions status for a repository.
 func GetActionsStatus(owner, name string) (string, error) {
+	if dir := FixtureDir(); dir != "" {
+		details, err := readFixtureDetails(dir, name)
+		if err != nil {
+			return "none", err
+		}
+		return details.ActionsStatus, nil
+	}
+
 	output, err := runGH("run", "list", "--repo", fmt.Sprintf("%s/%s", owner, name), "--limit", "1", "--json", "status,conclusion")
 	if err != nil {
 		// If there are no workflows, gh returns an error
@@ -224,6 +391,11 @@ func GetActionsStatus(owner, name string) (string, error) {
 // This is typically already available from the repo listing, but this
 // function can be used for a refresh.
 func GetLatestRelease(owner, name string) (*LatestRelease, error) {
+	if dir := FixtureDir(); dir != "" {
+		details, err := readFixtureDetails(dir, name)
+		return details.LatestRelease, err
+	}
+
 	output, err := runGH("release", "view", "--repo", fmt.Sprintf("%s/%s", owner, name), "--json", "tagName,publishedAt")
 	if err != nil {
 		// No releases found
@@ -275,6 +447,17 @@ type FilePresence struct {
 
 // GetFilePresence checks for the presence of specific files in a repository.
 func GetFilePresence(owner, name string) (*FilePresence, error) {
+	if dir := FixtureDir(); dir != "" {
+		details, err := readFixtureDetails(dir, name)
+		if err != nil {
+			return nil, err
+		}
+		if details.FilePresence == nil {
+			return &FilePresence{}, nil
+		}
+		return details.FilePresence, nil
+	}
+
 	result := &FilePresence{}
 
 	// Helper to check a file
@@ -310,10 +493,70 @@ func GetFilePresence(owner, name string) (*FilePresence, error) {
 	return result, nil
 }
 
-// parseTime parses an RFC3339 timestamp.
+// parseTime parses an RFC3339 timestamp and normalizes it to UTC.
 func parseTime(s string) (time.Time, error) {
 	if s == "" {
 		return time.Time{}, nil
 	}
-	return time.Parse(time.RFC3339, s)
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return t.UTC(), nil
+}
+
+// OwnerInfo represents the public profile of a GitHub user or organization.
+type OwnerInfo struct {
+	Login     string `json:"Login"`
+	Name      string `json:"Name"`
+	AvatarURL string `json:"AvatarURL"`
+	Found     bool   `json:"Found"`
+}
+
+// ghUser represents the subset of the gh api users/:owner payload we use.
+type ghUser struct {
+	Login     string `json:"login"`
+	Name      string `json:"name"`
+	AvatarURL string `json:"avatar_url"`
+}
+
+// GetOwnerInfo returns the profile for the given owner using gh api.
+// Unknown owners are not an error: they return Found=false.
+func GetOwnerInfo(owner string) (*OwnerInfo, error) {
+	output, err := runGH("api", fmt.Sprintf("users/%s", owner))
+	if err != nil {
+		// 404 means the owner does not exist
+		if strings.Contains(err.Error(), "404") || strings.Contains(err.Error(), "Not Found") {
+			return &OwnerInfo{Login: owner}, nil
+		}
+		return nil, fmt.Errorf("getting owner: %w", err)
+	}
+
+	return ParseOwnerInfo(owner, []byte(output))
+}
+
+// ParseOwnerInfo parses a gh api users/:owner payload.
+// A payload without a login is treated as an unknown owner.
+func ParseOwnerInfo(owner string, data []byte) (*OwnerInfo, error) {
+	var user ghUser
+	if err := json.Unmarshal(data, &user); err != nil {
+		return nil, fmt.Errorf("parsing owner JSON: %w", err)
+	}
+
+	if user.Login == "" {
+		return &OwnerInfo{Login: owner}, nil
+	}
+
+	info := &OwnerInfo{
+		Login:     user.Login,
+		Name:      user.Name,
+		AvatarURL: user.AvatarURL,
+		Found:     true,
+	}
+	// Users without a display name fall back to their login
+	if info.Name == "" {
+		info.Name = user.Login
+	}
+
+	return info, nil
 }
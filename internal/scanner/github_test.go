@@ -0,0 +1,187 @@
+package scanner_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alexcatdad/catscan/internal/scanner"
+)
+
+// TestParseIssueList tests counting issues from gh issue list JSON output.
+func TestParseIssueList(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		want    int
+		wantErr bool
+	}{
+		{name: "three issues", output: `[{"number":12},{"number":9},{"number":4}]`, want: 3},
+		{name: "no issues", output: `[]`, want: 0},
+		{name: "empty output", output: "\n", want: 0},
+		{name: "malformed", output: `[{"number":`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := scanner.ParseIssueList([]byte(tt.output))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseIssueList() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseIssueList() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestParseGHErrorRateLimit tests that rate-limit stderr from gh produces a
+// typed error with the reset time parsed out.
+func TestParseGHErrorRateLimit(t *testing.T) {
+	runErr := errors.New("exit status 1")
+
+	tests := []struct {
+		name      string
+		stderr    string
+		rateLimit bool
+		reset     time.Time
+	}{
+		{
+			name:      "reset header",
+			stderr:    "HTTP 403: API rate limit exceeded for user ID 1234.\nX-Ratelimit-Reset: 1739190000\n",
+			rateLimit: true,
+			reset:     time.Unix(1739190000, 0).UTC(),
+		},
+		{
+			name:      "reset timestamp",
+			stderr:    "GraphQL: API rate limit exceeded for user ID 1234. Limit resets at 2025-02-10T12:30:00Z",
+			rateLimit: true,
+			reset:     time.Date(2025, 2, 10, 12, 30, 0, 0, time.UTC),
+		},
+		{
+			name:      "no reset reported",
+			stderr:    "API rate limit exceeded for 203.0.113.7.",
+			rateLimit: true,
+		},
+		{
+			name:   "other failure",
+			stderr: "GraphQL: Could not resolve to a Repository with the name 'x'.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := scanner.ParseGHError(tt.stderr, runErr, "repo", "list")
+			if got := scanner.IsGHRateLimit(err); got != tt.rateLimit {
+				t.Fatalf("IsGHRateLimit = %v, want %v (err %v)", got, tt.rateLimit, err)
+			}
+			if got := scanner.RateLimitReset(err); !got.Equal(tt.reset) {
+				t.Errorf("RateLimitReset = %v, want %v", got, tt.reset)
+			}
+			if scanner.IsGHAuthError(err) {
+				t.Error("IsGHAuthError = true, want false")
+			}
+		})
+	}
+
+	// Wrapping keeps the type visible
+	wrapped := fmt.Errorf("listing repos: %w", scanner.ParseGHError(tests[0].stderr, runErr))
+	if !scanner.IsGHRateLimit(wrapped) {
+		t.Error("IsGHRateLimit(wrapped) = false, want true")
+	}
+}
+
+// TestMissingGHScopes tests reading token scopes from gh auth status output.
+func TestMissingGHScopes(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []string
+	}{
+		{
+			name: "quoted scopes with repo",
+			output: `github.com
+  ✓ Logged in to github.com account alexcatdad (keyring)
+  - Active account: true
+  - Git operations protocol: https
+  - Token: gho_************************************
+  - Token scopes: 'gist', 'read:org', 'repo', 'workflow'`,
+			want: nil,
+		},
+		{
+			name: "quoted scopes without repo",
+			output: `github.com
+  ✓ Logged in to github.com account alexcatdad (keyring)
+  - Token scopes: 'gist', 'read:org'`,
+			want: []string{"repo"},
+		},
+		{
+			name: "older unquoted format",
+			output: `github.com
+  ✓ Logged in to github.com as alexcatdad (oauth_token)
+  ✓ Token scopes: gist, read:org, repo`,
+			want: nil,
+		},
+		{
+			name:   "no scopes",
+			output: `  - Token scopes: none`,
+			want:   []string{"repo"},
+		},
+		{
+			name: "scopes not reported",
+			output: `github.com
+  ✓ Logged in to github.com account alexcatdad (GH_TOKEN)`,
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := scanner.MissingGHScopes(tt.output)
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("MissingGHScopes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestParseGHAuthStatus tests reading the logged-in account from gh auth
+// status output in both the newer and older formats.
+func TestParseGHAuthStatus(t *testing.T) {
+	tests := []struct {
+		name      string
+		output    string
+		wantLogin string
+		wantHost  string
+	}{
+		{
+			name: "account format",
+			output: `github.com
+  ✓ Logged in to github.com account alexcatdad (keyring)
+  - Token scopes: 'repo'`,
+			wantLogin: "alexcatdad",
+			wantHost:  "github.com",
+		},
+		{
+			name: "older as format",
+			output: `ghe.example.com
+  ✓ Logged in to ghe.example.com as cat (oauth_token)`,
+			wantLogin: "cat",
+			wantHost:  "ghe.example.com",
+		},
+		{
+			name:   "no login line",
+			output: `You are not logged into any GitHub hosts.`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := scanner.ParseGHAuthStatus(tt.output)
+			if got.Login != tt.wantLogin || got.Host != tt.wantHost {
+				t.Errorf("ParseGHAuthStatus() = %+v, want login %q on %q", got, tt.wantLogin, tt.wantHost)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+// Package scanner provides repository scanning functionality.
+//
+// The identity subpackage works out which GitHub repo a local directory
+// holds when the directory name doesn't say.
+package scanner
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// repoMarkerFileName is an optional file in a local repo whose first line
+// names the GitHub repo the directory holds.
+const repoMarkerFileName = ".catscan-repo"
+
+// ReadRepoIdentity returns the GitHub repo name a local directory declares:
+// the repo in the origin remote URL from .git/config, falling back to the
+// name in the .catscan-repo marker. Returns "" when neither names a repo.
+func ReadRepoIdentity(repoPath string) (string, error) {
+	name, err := readOriginRepoName(repoPath)
+	if err != nil || name != "" {
+		return name, err
+	}
+	return readRepoMarker(repoPath)
+}
+
+// readOriginRepoName returns the repo name from the origin remote URL in
+// .git/config, or "" when there is no origin.
+func readOriginRepoName(repoPath string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(repoPath, ".git", "config"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", nil
+		}
+		return "", fmt.Errorf("reading git config: %w", err)
+	}
+
+	inOrigin := false
+	lines := bufio.NewScanner(bytes.NewReader(data))
+	for lines.Scan() {
+		line := strings.TrimSpace(lines.Text())
+		if strings.HasPrefix(line, "[") {
+			inOrigin = line == `[remote "origin"]`
+			continue
+		}
+		if !inOrigin {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if ok && strings.TrimSpace(key) == "url" {
+			return repoNameFromURL(strings.TrimSpace(value)), nil
+		}
+	}
+	return "", lines.Err()
+}
+
+// repoNameFromURL extracts the repo name from an https or ssh remote URL,
+// e.g. git@github.com:owner/name.git or https://github.com/owner/name.
+func repoNameFromURL(url string) string {
+	url = strings.TrimSuffix(strings.TrimSuffix(url, "/"), ".git")
+	if i := strings.LastIndexAny(url, "/:"); i >= 0 {
+		url = url[i+1:]
+	}
+	return url
+}
+
+// readRepoMarker returns the repo name from the .catscan-repo marker, or ""
+// when there is none. The marker may hold "name" or "owner/name".
+func readRepoMarker(repoPath string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(repoPath, repoMarkerFileName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", nil
+		}
+		return "", fmt.Errorf("reading %s: %w", repoMarkerFileName, err)
+	}
+
+	line, _, _ := strings.Cut(string(data), "\n")
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return "", nil
+	}
+	return path.Base(line), nil
+}
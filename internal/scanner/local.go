@@ -6,10 +6,15 @@ package scanner
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -27,6 +32,71 @@ type LocalRepo struct {
 	Branch    string
 	Dirty     bool
 	LastCommit time.Time
+
+	// Working tree file counts; Dirty is true when any is non-zero
+	Untracked int
+	Modified  int
+	Staged    int
+
+	// Commits relative to the upstream tracking branch, zero without one
+	Ahead  int
+	Behind int
+
+	// UnpushedTags is only populated when network git access is allowed
+	UnpushedTags []string
+
+	// HasProjectFile is true when .project.json exists, even if malformed.
+	// Project holds its parsed fields, nil when absent or malformed.
+	HasProjectFile bool
+	Project        *ProjectInfo
+
+	// FilePresence is read from the working tree (see LocalFilePresence),
+	// nil when the directory couldn't be read.
+	FilePresence *FilePresence
+
+	// GitHubName is the GitHub repo this directory holds according to its
+	// contents (see ReadRepoIdentity), used when Name matches no GitHub repo.
+	// Empty when unknown or not looked up.
+	GitHubName string
+}
+
+// Reasons a scan path can't be scanned, reported by ScanPathErrorReason.
+const (
+	ScanPathNotDirectory     = "not_a_directory"
+	ScanPathPermissionDenied = "permission_denied"
+	ScanPathUnreadable       = "unreadable"
+)
+
+// scanPathError is returned when the scan path exists but can't be scanned.
+type scanPathError struct {
+	path   string
+	reason string
+	err    error
+}
+
+func (e *scanPathError) Error() string {
+	switch e.reason {
+	case ScanPathNotDirectory:
+		return fmt.Sprintf("scan path %s is not a directory", e.path)
+	case ScanPathPermissionDenied:
+		return fmt.Sprintf("scan path %s is not readable: permission denied", e.path)
+	default:
+		return fmt.Sprintf("reading scan path %s: %v", e.path, e.err)
+	}
+}
+
+func (e *scanPathError) Unwrap() error {
+	return e.err
+}
+
+// ScanPathErrorReason returns why the scan path behind err can't be scanned,
+// one of the ScanPath reason constants, or "" if err is not a scan path error.
+func ScanPathErrorReason(err error) string {
+	var target *scanPathError
+	if errors.As(err, &target) {
+		return target.reason
+	}
+	return ""
 }
 
 // DiscoverLocalRepos scans the given path for git repositories.
@@ -34,11 +104,19 @@ type LocalRepo struct {
 // Skips hidden directories (those starting with a dot).
 // Returns a sorted list of discovered repositories.
 func DiscoverLocalRepos(scanPath string) ([]string, error) {
+	repos, _, err := DiscoverLocalReposLimit(scanPath, 0)
+	return repos, err
+}
+
+// DiscoverLocalReposLimit is DiscoverLocalRepos, stopping after limit repos.
+// A zero limit means no limit. truncated reports whether repos were left out.
+// Directories are visited in name order, so the same repos are kept each scan.
+func DiscoverLocalReposLimit(scanPath string, limit int) (repos []string, truncated bool, err error) {
 	// Expand tilde if present
 	if strings.HasPrefix(scanPath, "~") {
 		homeDir, err := os.UserHomeDir()
 		if err != nil {
-			return nil, fmt.Errorf("expanding tilde: %w", err)
+			return nil, false, fmt.Errorf("expanding tilde: %w", err)
 		}
 		if len(scanPath) == 1 {
 			scanPath = homeDir
@@ -52,13 +130,17 @@ func DiscoverLocalRepos(scanPath string) ([]string, error) {
 	if err != nil {
 		if os.IsNotExist(err) {
 			// Scan path doesn't exist, return empty list
-			return []string{}, nil
+			return []string{}, false, nil
+		}
+		reason := ScanPathUnreadable
+		if info, statErr := os.Stat(scanPath); statErr == nil && !info.IsDir() {
+			reason = ScanPathNotDirectory
+		} else if errors.Is(err, fs.ErrPermission) {
+			reason = ScanPathPermissionDenied
 		}
-		return nil, fmt.Errorf("reading scan path: %w", err)
+		return nil, false, &scanPathError{path: scanPath, reason: reason, err: err}
 	}
 
-	var repos []string
-
 	for _, entry := range entries {
 		// Skip hidden directories
 		if strings.HasPrefix(entry.Name(), ".") {
@@ -79,6 +161,9 @@ func DiscoverLocalRepos(scanPath string) ([]string, error) {
 
 		// .git exists and is a directory
 		if info.IsDir() {
+			if limit > 0 && len(repos) == limit {
+				return repos, true, nil
+			}
 			repos = append(repos, entry.Name())
 		}
 	}
@@ -87,38 +172,256 @@ func DiscoverLocalRepos(scanPath string) ([]string, error) {
 	// Note: Go's ReadDir already returns sorted entries, so this is a no-op
 	// but we'll keep it for clarity and robustness
 
-	return repos, nil
+	return repos, false, nil
+}
+
+// GitStatus is the local git state of a repository.
+type GitStatus struct {
+	Branch     string
+	LastCommit time.Time
+
+	// Working tree file counts from git status. A file that is both staged
+	// and modified afterwards counts toward both.
+	Untracked int
+	Modified  int
+	Staged    int
+}
+
+// Dirty reports whether the working tree has any uncommitted changes.
+func (s GitStatus) Dirty() bool {
+	return s.Untracked+s.Modified+s.Staged > 0
 }
 
 // GetGitState extracts the git state for a repository at the given path.
 // Returns branch name, dirty status, and last commit date.
-// Logs errors and returns zero values if git commands fail.
+// Repos with no commits yet (e.g. freshly cloned empty repos) return the
+// unborn branch name and a zero lastCommit rather than an error.
 func GetGitState(repoPath string) (branch string, dirty bool, lastCommit time.Time, err error) {
+	status, err := GetGitStatus(repoPath)
+	if err != nil {
+		return "", false, time.Time{}, err
+	}
+	return status.Branch, status.Dirty(), status.LastCommit, nil
+}
+
+// GetGitStatus extracts the git state for a repository at the given path,
+// including untracked, modified, and staged file counts.
+// Repos with no commits yet return the unborn branch name and a zero LastCommit.
+func GetGitStatus(repoPath string) (GitStatus, error) {
+	var status GitStatus
+
+	// Repos without commits have no HEAD to resolve
+	hasCommits := HasCommits(repoPath)
+
 	// Get current branch
-	branch, err = runGitCommand(repoPath, "rev-parse", "--abbrev-ref", "HEAD")
+	var branch string
+	var err error
+	if hasCommits {
+		branch, err = runGitCommand(repoPath, "rev-parse", "--abbrev-ref", "HEAD")
+	} else {
+		branch, err = runGitCommand(repoPath, "symbolic-ref", "--short", "HEAD")
+	}
 	if err != nil {
-		return "", false, time.Time{}, fmt.Errorf("getting branch: %w", err)
+		return GitStatus{}, fmt.Errorf("getting branch: %w", err)
 	}
+	status.Branch = strings.TrimSpace(branch)
 
-	// Get dirty status
-	dirtyOutput, err := runGitCommand(repoPath, "status", "--porcelain")
+	// Get working tree counts
+	porcelain, err := runGitCommand(repoPath, "status", "--porcelain")
 	if err != nil {
-		return "", false, time.Time{}, fmt.Errorf("getting dirty status: %w", err)
+		return GitStatus{}, fmt.Errorf("getting dirty status: %w", err)
+	}
+	status.Untracked, status.Modified, status.Staged = parseStatusCounts(porcelain)
+
+	if !hasCommits {
+		return status, nil
 	}
-	dirty = strings.TrimSpace(dirtyOutput) != ""
 
 	// Get last commit date
 	dateOutput, err := runGitCommand(repoPath, "log", "-1", "--format=%aI")
 	if err != nil {
-		return "", false, time.Time{}, fmt.Errorf("getting last commit: %w", err)
+		return GitStatus{}, fmt.Errorf("getting last commit: %w", err)
+	}
+
+	lastCommit, err := time.Parse(time.RFC3339, strings.TrimSpace(dateOutput))
+	if err != nil {
+		return GitStatus{}, fmt.Errorf("parsing commit date: %w", err)
+	}
+	// git reports the committer's offset; store UTC like every other timestamp
+	status.LastCommit = lastCommit.UTC()
+
+	return status, nil
+}
+
+// parseStatusCounts counts files in git status --porcelain output.
+// Each line is "XY path" where X is the index state and Y the work tree state.
+func parseStatusCounts(porcelain string) (untracked, modified, staged int) {
+	for _, line := range strings.Split(porcelain, "\n") {
+		if len(line) < 2 {
+			continue
+		}
+		x, y := line[0], line[1]
+		if x == '?' && y == '?' {
+			untracked++
+			continue
+		}
+		if x != ' ' && x != '!' {
+			staged++
+		}
+		if y != ' ' && y != '!' {
+			modified++
+		}
+	}
+	return untracked, modified, staged
+}
+
+// HasCommits reports whether the repository at repoPath has at least one commit.
+func HasCommits(repoPath string) bool {
+	_, err := runGitCommand(repoPath, "rev-parse", "--verify", "--quiet", "HEAD")
+	return err == nil
+}
+
+// GetAheadBehind returns how many commits HEAD is ahead of and behind its
+// upstream tracking branch. Uses the last fetched remote state and never
+// contacts the remote. Returns zeros when there is no upstream.
+func GetAheadBehind(repoPath string) (ahead, behind int, err error) {
+	if _, err := runGitCommand(repoPath, "rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{upstream}"); err != nil {
+		// No upstream configured (or no commits yet)
+		return 0, 0, nil
+	}
+
+	output, err := runGitCommand(repoPath, "rev-list", "--left-right", "--count", "@{upstream}...HEAD")
+	if err != nil {
+		return 0, 0, fmt.Errorf("counting ahead/behind: %w", err)
+	}
+
+	// Output is "<behind>\t<ahead>"
+	fields := strings.Fields(output)
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("unexpected rev-list output: %q", output)
+	}
+	if behind, err = strconv.Atoi(fields[0]); err != nil {
+		return 0, 0, fmt.Errorf("parsing behind count: %w", err)
+	}
+	if ahead, err = strconv.Atoi(fields[1]); err != nil {
+		return 0, 0, fmt.Errorf("parsing ahead count: %w", err)
+	}
+
+	return ahead, behind, nil
+}
+
+// GetUnpushedTags returns local tags that do not exist on the origin remote.
+// This contacts the remote via git ls-remote, so callers should only use it
+// when network access is allowed. Returns a sorted list of tag names.
+func GetUnpushedTags(repoPath string) ([]string, error) {
+	localOutput, err := runGitCommand(repoPath, "tag", "--list")
+	if err != nil {
+		return nil, fmt.Errorf("listing local tags: %w", err)
+	}
+
+	localTags := strings.Fields(localOutput)
+	if len(localTags) == 0 {
+		return nil, nil
+	}
+
+	remoteOutput, err := runGitCommand(repoPath, "ls-remote", "--tags", "origin")
+	if err != nil {
+		return nil, fmt.Errorf("listing remote tags: %w", err)
+	}
+
+	// Lines look like "<sha>\trefs/tags/<name>" or "<sha>\trefs/tags/<name>^{}"
+	remoteTags := make(map[string]struct{})
+	for _, line := range strings.Split(remoteOutput, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		name := strings.TrimPrefix(fields[1], "refs/tags/")
+		name = strings.TrimSuffix(name, "^{}")
+		remoteTags[name] = struct{}{}
+	}
+
+	var unpushed []string
+	for _, tag := range localTags {
+		if _, ok := remoteTags[tag]; !ok {
+			unpushed = append(unpushed, tag)
+		}
+	}
+	sort.Strings(unpushed)
+
+	return unpushed, nil
+}
+
+// GetDiff returns the uncommitted changes in repoPath as unified diff text:
+// unstaged changes (git diff) followed by staged ones (git diff --cached).
+// Output beyond maxBytes is dropped and truncated is set. Untracked files
+// are not included. A clean tree returns "".
+func GetDiff(repoPath string, maxBytes int) (diff string, truncated bool, err error) {
+	out := &cappedBuffer{limit: maxBytes}
+	for _, args := range [][]string{
+		{"diff", "--no-color", "--no-ext-diff"},
+		{"diff", "--no-color", "--no-ext-diff", "--cached"},
+	} {
+		cmd := exec.Command(gitBin, args...)
+		cmd.Dir = repoPath
+
+		var stderr bytes.Buffer
+		cmd.Stdout = out
+		cmd.Stderr = &stderr
+
+		if err := cmd.Run(); err != nil {
+			return "", false, fmt.Errorf("git %v: %w (stderr: %s)", args, err, stderr.String())
+		}
+	}
+	return out.buf.String(), out.truncated, nil
+}
+
+// cappedBuffer keeps the first limit bytes written to it and discards the
+// rest, so a huge command output can be drained without holding it all.
+type cappedBuffer struct {
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	n := len(p)
+	if room := b.limit - b.buf.Len(); n > room {
+		b.truncated = true
+		p = p[:max(room, 0)]
 	}
+	b.buf.Write(p)
+	return n, nil
+}
 
-	lastCommit, err = time.Parse(time.RFC3339, strings.TrimSpace(dateOutput))
+// LocalFilePresence checks the working tree at repoPath for the files
+// GetFilePresence looks for on GitHub, without any API calls. README* and
+// LICENSE* match any extension, case-insensitively. Returns nil if the
+// directory can't be read.
+func LocalFilePresence(repoPath string) *FilePresence {
+	entries, err := os.ReadDir(repoPath)
 	if err != nil {
-		return "", false, time.Time{}, fmt.Errorf("parsing commit date: %w", err)
+		return nil
 	}
 
-	return branch, dirty, lastCommit, nil
+	result := &FilePresence{}
+	for _, entry := range entries {
+		name := entry.Name()
+		upper := strings.ToUpper(name)
+		switch {
+		case strings.HasPrefix(upper, "README"):
+			result.HasREADME = true
+		case strings.HasPrefix(upper, "LICENSE"):
+			result.HasLICENSE = true
+		case name == "CLAUDE.md":
+			result.HasCLAUDEmd = true
+		case name == "AGENTS.md":
+			result.HasAGENTSmd = true
+		case name == projectFileName:
+			result.HasProjectJson = true
+		}
+	}
+	return result
 }
 
 // runGitCommand executes a git command in the given repository directory.
@@ -170,10 +473,51 @@ func FindClonedRepos(repos []string, scanPath string) map[string]string {
 	return cloned
 }
 
+// Clone URL protocols.
+const (
+	CloneProtocolHTTPS = "https"
+	CloneProtocolSSH   = "ssh"
+)
+
+// cloneWaitDelay bounds how long a cancelled clone waits for its I/O to close.
+const cloneWaitDelay = 2 * time.Second
+
+// CloneOptions controls how a repository is cloned.
+type CloneOptions struct {
+	// Depth creates a shallow clone with that many commits. Zero clones full history.
+	Depth int
+	// Protocol selects the clone URL scheme: CloneProtocolHTTPS (default) or CloneProtocolSSH.
+	Protocol string
+	// Branch checks out that branch instead of the remote HEAD. Empty uses the remote HEAD.
+	Branch string
+}
+
+// CloneURL returns the GitHub clone URL for a repository using the given protocol.
+func CloneURL(owner, name, protocol string) string {
+	if protocol == CloneProtocolSSH {
+		return fmt.Sprintf("git@github.com:%s/%s.git", owner, name)
+	}
+	return fmt.Sprintf("https://github.com/%s/%s.git", owner, name)
+}
+
+// CloneArgs returns the git arguments used to clone a repository into repoPath.
+func CloneArgs(owner, name, repoPath string, opts CloneOptions) []string {
+	args := []string{"clone"}
+	if opts.Depth > 0 {
+		args = append(args, "--depth", strconv.Itoa(opts.Depth))
+	}
+	if opts.Branch != "" {
+		args = append(args, "--branch", opts.Branch)
+	}
+	return append(args, CloneURL(owner, name, opts.Protocol), repoPath)
+}
+
 // CloneRepo clones a GitHub repository to the scan path.
 // Returns a channel of status updates for progress tracking.
 // Errors are sent through the channel as CloneError values.
-func CloneRepo(owner, name, scanPath string) <-chan CloneStatus {
+// Cancelling ctx kills the clone, removes the partial checkout, and sends
+// a CloneStateError status with a "cancelled" message.
+func CloneRepo(ctx context.Context, owner, name, scanPath string, opts CloneOptions) <-chan CloneStatus {
 	statusChan := make(chan CloneStatus)
 
 	go func() {
@@ -215,14 +559,25 @@ func CloneRepo(owner, name, scanPath string) <-chan CloneStatus {
 		}
 
 		// Clone the repository
-		url := fmt.Sprintf("https://github.com/%s/%s.git", owner, name)
-		cmd := exec.Command(gitBin, "clone", url, repoPath)
+		cmd := exec.CommandContext(ctx, gitBin, CloneArgs(owner, name, repoPath, opts)...)
+		// Don't wait on helpers (ssh, credential) that outlive a killed git
+		cmd.WaitDelay = cloneWaitDelay
 
 		var stdout, stderr bytes.Buffer
 		cmd.Stdout = &stdout
 		cmd.Stderr = &stderr
 
 		if err := cmd.Run(); err != nil {
+			if ctx.Err() != nil {
+				// Remove the partial checkout; the path didn't exist before
+				_ = os.RemoveAll(repoPath)
+				statusChan <- CloneStatus{
+					Repo:  name,
+					State: CloneStateError,
+					Error: fmt.Sprintf("clone cancelled: %v", ctx.Err()),
+				}
+				return
+			}
 			statusChan <- CloneStatus{
 				Repo:  name,
 				State: CloneStateError,
@@ -241,6 +596,47 @@ func CloneRepo(owner, name, scanPath string) <-chan CloneStatus {
 	return statusChan
 }
 
+// PullRepo fast-forwards an already-cloned repository from its upstream.
+// Returns a channel of status updates using the same states as CloneRepo.
+// Errors are sent through the channel as CloneStateError values.
+func PullRepo(name, repoPath string) <-chan CloneStatus {
+	statusChan := make(chan CloneStatus)
+
+	go func() {
+		defer close(statusChan)
+
+		// Send started status
+		statusChan <- CloneStatus{
+			Repo:  name,
+			State: CloneStateStarted,
+		}
+
+		// Only fast-forward so local work is never merged or rebased
+		cmd := exec.Command(gitBin, "-C", repoPath, "pull", "--ff-only")
+
+		var stdout, stderr bytes.Buffer
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+
+		if err := cmd.Run(); err != nil {
+			statusChan <- CloneStatus{
+				Repo:  name,
+				State: CloneStateError,
+				Error: fmt.Sprintf("pull failed: %v (stderr: %s)", err, stderr.String()),
+			}
+			return
+		}
+
+		// Send completed status
+		statusChan <- CloneStatus{
+			Repo:  name,
+			State: CloneStateCompleted,
+		}
+	}()
+
+	return statusChan
+}
+
 // CloneState represents the state of a clone operation.
 type CloneState string
 
@@ -1,10 +1,13 @@
 package scanner_test
 
 import (
+	"context"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/alexcatdad/catscan/internal/scanner"
 )
@@ -124,6 +127,79 @@ func TestDiscoverLocalReposHandlesNonExistentPath(t *testing.T) {
 	}
 }
 
+// TestDiscoverLocalReposScanPathIsFile tests that a scan path pointing at a
+// regular file returns a scan path error saying so.
+func TestDiscoverLocalReposScanPathIsFile(t *testing.T) {
+	scanPath := filepath.Join(t.TempDir(), "projects")
+	if err := os.WriteFile(scanPath, []byte("not a directory"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := scanner.DiscoverLocalRepos(scanPath)
+	if err == nil {
+		t.Fatal("DiscoverLocalRepos() succeeded, want error")
+	}
+	if got := scanner.ScanPathErrorReason(err); got != scanner.ScanPathNotDirectory {
+		t.Errorf("ScanPathErrorReason() = %q, want %q", got, scanner.ScanPathNotDirectory)
+	}
+	if !strings.Contains(err.Error(), "not a directory") {
+		t.Errorf("error = %q, want it to say the path is not a directory", err)
+	}
+}
+
+// TestDiscoverLocalReposScanPathPermissionDenied tests that an unreadable
+// scan path directory returns a permission denied scan path error.
+func TestDiscoverLocalReposScanPathPermissionDenied(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("root can read directories without read permission")
+	}
+	scanPath := filepath.Join(t.TempDir(), "projects")
+	if err := os.Mkdir(scanPath, 0000); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chmod(scanPath, 0755)
+
+	_, err := scanner.DiscoverLocalRepos(scanPath)
+	if err == nil {
+		t.Fatal("DiscoverLocalRepos() succeeded, want error")
+	}
+	if got := scanner.ScanPathErrorReason(err); got != scanner.ScanPathPermissionDenied {
+		t.Errorf("ScanPathErrorReason() = %q, want %q", got, scanner.ScanPathPermissionDenied)
+	}
+}
+
+// TestDiscoverLocalReposLimit tests that discovery stops at the cap and
+// reports the scan path as truncated.
+func TestDiscoverLocalReposLimit(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	for _, name := range []string{"repo-a", "repo-b", "repo-c", "repo-d", "repo-e"} {
+		if err := os.MkdirAll(filepath.Join(tmpDir, name, ".git"), 0o755); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	repos, truncated, err := scanner.DiscoverLocalReposLimit(tmpDir, 3)
+	if err != nil {
+		t.Fatalf("DiscoverLocalReposLimit() failed: %v", err)
+	}
+	if !truncated {
+		t.Error("truncated = false, want true")
+	}
+	if strings.Join(repos, ",") != "repo-a,repo-b,repo-c" {
+		t.Errorf("repos = %v, want [repo-a repo-b repo-c]", repos)
+	}
+
+	// A cap at or above the repo count keeps everything
+	repos, truncated, err = scanner.DiscoverLocalReposLimit(tmpDir, 5)
+	if err != nil {
+		t.Fatalf("DiscoverLocalReposLimit() failed: %v", err)
+	}
+	if truncated || len(repos) != 5 {
+		t.Errorf("cap 5: repos = %v, truncated = %v; want 5 repos, false", repos, truncated)
+	}
+}
+
 // TestGetGitStateWithRealRepo tests git state extraction with a real temporary git repo.
 func TestGetGitStateWithRealRepo(t *testing.T) {
 	// Check if git is available
@@ -271,7 +347,7 @@ func TestCloneRepoAlreadyExists(t *testing.T) {
 		t.Fatalf("Failed to create existing repo: %v", err)
 	}
 
-	statusChan := scanner.CloneRepo("testowner", "existing-repo", tmpDir)
+	statusChan := scanner.CloneRepo(context.Background(), "testowner", "existing-repo", tmpDir, scanner.CloneOptions{})
 
 	// Receive status
 	status := <-statusChan
@@ -291,6 +367,84 @@ func TestCloneRepoAlreadyExists(t *testing.T) {
 	}
 }
 
+// TestCloneRepoCancelled tests that cancelling the context stops a running clone.
+func TestCloneRepoCancelled(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	// Make the ssh transport hang so the clone runs until cancelled
+	t.Setenv("GIT_SSH_COMMAND", "sleep 30 #")
+
+	tmpDir := t.TempDir()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	statusChan := scanner.CloneRepo(ctx, "testowner", "slow-repo", tmpDir, scanner.CloneOptions{Protocol: scanner.CloneProtocolSSH})
+
+	if status := <-statusChan; status.State != scanner.CloneStateStarted {
+		t.Fatalf("first state = %s, want %s", status.State, scanner.CloneStateStarted)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+
+	select {
+	case status := <-statusChan:
+		if status.State != scanner.CloneStateError {
+			t.Errorf("state = %s, want %s", status.State, scanner.CloneStateError)
+		}
+		if !contains(status.Error, "cancelled") {
+			t.Errorf("error = %s, want to contain cancelled", status.Error)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("clone did not stop after cancel")
+	}
+
+	if _, err := os.Stat(filepath.Join(tmpDir, "slow-repo")); !os.IsNotExist(err) {
+		t.Errorf("partial clone left behind: %v", err)
+	}
+}
+
+// TestCloneArgs tests the git clone arguments for depth, protocol, and branch.
+func TestCloneArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		opts scanner.CloneOptions
+		want []string
+	}{
+		{
+			name: "full https clone",
+			opts: scanner.CloneOptions{},
+			want: []string{"clone", "https://github.com/owner/repo.git", "/scan/repo"},
+		},
+		{
+			name: "shallow https clone",
+			opts: scanner.CloneOptions{Depth: 1, Protocol: scanner.CloneProtocolHTTPS},
+			want: []string{"clone", "--depth", "1", "https://github.com/owner/repo.git", "/scan/repo"},
+		},
+		{
+			name: "shallow ssh clone",
+			opts: scanner.CloneOptions{Depth: 1, Protocol: scanner.CloneProtocolSSH},
+			want: []string{"clone", "--depth", "1", "git@github.com:owner/repo.git", "/scan/repo"},
+		},
+		{
+			name: "clone of a named branch",
+			opts: scanner.CloneOptions{Branch: "develop"},
+			want: []string{"clone", "--branch", "develop", "https://github.com/owner/repo.git", "/scan/repo"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := scanner.CloneArgs("owner", "repo", "/scan/repo", tt.opts)
+			if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("CloneArgs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(s) > len(substr) && indexOf(s, substr) >= 0)
 }
@@ -303,3 +457,183 @@ func indexOf(s, substr string) int {
 	}
 	return -1
 }
+
+// TestGetUnpushedTags tests detection of local tags missing from the remote.
+func TestGetUnpushedTags(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	tmpDir := t.TempDir()
+	remotePath := filepath.Join(tmpDir, "remote.git")
+	repoPath := filepath.Join(tmpDir, "test-repo")
+
+	// Fake remote is a local bare repository
+	runGit(t, tmpDir, "init", "--bare", remotePath)
+	runGit(t, tmpDir, "init", repoPath)
+	runGit(t, repoPath, "config", "user.email", "test@example.com")
+	runGit(t, repoPath, "config", "user.name", "Test User")
+	runGit(t, repoPath, "remote", "add", "origin", remotePath)
+
+	if err := os.WriteFile(filepath.Join(repoPath, "test.txt"), []byte("test"), 0o644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+	runGit(t, repoPath, "add", "test.txt")
+	runGit(t, repoPath, "commit", "-m", "test commit")
+
+	// v1.0.0 is pushed, v1.1.0 only exists locally
+	runGit(t, repoPath, "tag", "-a", "v1.0.0", "-m", "v1.0.0")
+	runGit(t, repoPath, "push", "origin", "HEAD", "--tags")
+	runGit(t, repoPath, "tag", "v1.1.0")
+
+	unpushed, err := scanner.GetUnpushedTags(repoPath)
+	if err != nil {
+		t.Fatalf("GetUnpushedTags() failed: %v", err)
+	}
+
+	if len(unpushed) != 1 || unpushed[0] != "v1.1.0" {
+		t.Errorf("unpushed = %v, want [v1.1.0]", unpushed)
+	}
+}
+
+// TestGetGitStatusCounts tests untracked, modified, and staged file counts.
+func TestGetGitStatusCounts(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	repoPath := filepath.Join(t.TempDir(), "test-repo")
+	runGit(t, filepath.Dir(repoPath), "init", repoPath)
+	runGit(t, repoPath, "config", "user.email", "test@example.com")
+	runGit(t, repoPath, "config", "user.name", "Test User")
+
+	for _, name := range []string{"modified.txt", "staged.txt"} {
+		if err := os.WriteFile(filepath.Join(repoPath, name), []byte("original"), 0o644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	runGit(t, repoPath, "add", ".")
+	runGit(t, repoPath, "commit", "-m", "initial")
+
+	// One untracked, one modified (unstaged), one staged
+	files := map[string]string{
+		"untracked.txt": "new",
+		"modified.txt":  "changed",
+		"staged.txt":    "changed",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(repoPath, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	runGit(t, repoPath, "add", "staged.txt")
+
+	status, err := scanner.GetGitStatus(repoPath)
+	if err != nil {
+		t.Fatalf("GetGitStatus() failed: %v", err)
+	}
+
+	if status.Untracked != 1 {
+		t.Errorf("Untracked = %d, want 1", status.Untracked)
+	}
+	if status.Modified != 1 {
+		t.Errorf("Modified = %d, want 1", status.Modified)
+	}
+	if status.Staged != 1 {
+		t.Errorf("Staged = %d, want 1", status.Staged)
+	}
+	if !status.Dirty() {
+		t.Error("Dirty() = false, want true")
+	}
+}
+
+// TestGetAheadBehind tests ahead/behind counts against a simulated upstream.
+func TestGetAheadBehind(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	tmpDir := t.TempDir()
+	remotePath := filepath.Join(tmpDir, "remote.git")
+	repoPath := filepath.Join(tmpDir, "test-repo")
+	otherPath := filepath.Join(tmpDir, "other-clone")
+
+	runGit(t, tmpDir, "init", "--bare", "-b", "main", remotePath)
+	runGit(t, tmpDir, "init", "-b", "main", repoPath)
+	runGit(t, repoPath, "config", "user.email", "test@example.com")
+	runGit(t, repoPath, "config", "user.name", "Test User")
+	runGit(t, repoPath, "commit", "--allow-empty", "-m", "initial")
+
+	// No upstream yet: zeros, not an error
+	ahead, behind, err := scanner.GetAheadBehind(repoPath)
+	if err != nil {
+		t.Fatalf("GetAheadBehind() without upstream failed: %v", err)
+	}
+	if ahead != 0 || behind != 0 {
+		t.Errorf("without upstream: ahead=%d behind=%d, want 0 0", ahead, behind)
+	}
+
+	runGit(t, repoPath, "remote", "add", "origin", remotePath)
+	runGit(t, repoPath, "push", "-u", "origin", "main")
+
+	// Another clone pushes one commit upstream
+	runGit(t, tmpDir, "clone", remotePath, otherPath)
+	runGit(t, otherPath, "config", "user.email", "test@example.com")
+	runGit(t, otherPath, "config", "user.name", "Test User")
+	runGit(t, otherPath, "commit", "--allow-empty", "-m", "remote change")
+	runGit(t, otherPath, "push", "origin", "main")
+
+	// Two local commits, then fetch the remote one
+	runGit(t, repoPath, "commit", "--allow-empty", "-m", "local 1")
+	runGit(t, repoPath, "commit", "--allow-empty", "-m", "local 2")
+	runGit(t, repoPath, "fetch", "origin")
+
+	ahead, behind, err = scanner.GetAheadBehind(repoPath)
+	if err != nil {
+		t.Fatalf("GetAheadBehind() failed: %v", err)
+	}
+	if ahead != 2 {
+		t.Errorf("ahead = %d, want 2", ahead)
+	}
+	if behind != 1 {
+		t.Errorf("behind = %d, want 1", behind)
+	}
+}
+
+// runGit runs a git command in dir and fails the test on error.
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v failed: %v (%s)", args, err, output)
+	}
+}
+
+// TestGetGitStateEmptyRepo tests that a repo with no commits is not treated as an error.
+func TestGetGitStateEmptyRepo(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	repoPath := filepath.Join(t.TempDir(), "empty-repo")
+	runGit(t, filepath.Dir(repoPath), "init", "-b", "main", repoPath)
+
+	if scanner.HasCommits(repoPath) {
+		t.Error("HasCommits = true, want false for empty repo")
+	}
+
+	branch, dirty, lastCommit, err := scanner.GetGitState(repoPath)
+	if err != nil {
+		t.Fatalf("GetGitState() failed on empty repo: %v", err)
+	}
+	if branch != "main" {
+		t.Errorf("branch = %q, want main", branch)
+	}
+	if dirty {
+		t.Error("dirty = true, want false")
+	}
+	if !lastCommit.IsZero() {
+		t.Errorf("lastCommit = %v, want zero", lastCommit)
+	}
+}
@@ -5,6 +5,8 @@ package scanner
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 	"time"
 
 	"github.com/alexcatdad/catscan/internal/cache"
@@ -17,6 +19,8 @@ import (
 // GitHub fields populate everything else.
 // Repos that exist on GitHub but not locally get cloned=false.
 // Repos that exist locally but not on GitHub appear with minimal data.
+// A local repo whose directory name matches no GitHub repo is matched by its
+// GitHubName instead, when that GitHub repo has no local clone of its own.
 // Lifecycle status is computed during merge.
 func Merge(
 	localRepos map[string]LocalRepo,
@@ -31,6 +35,12 @@ func Merge(
 		githubMap[ghRepo.Name] = ghRepo
 	}
 
+	// Re-key local repos whose directory name isn't a GitHub repo but whose
+	// contents name one; the caller's map is left untouched
+	if matched := matchLocalByContent(localRepos, githubMap); matched != nil {
+		localRepos = matched
+	}
+
 	// Collect all unique repo names
 	allNames := make(map[string]struct{})
 	for name := range localRepos {
@@ -40,9 +50,10 @@ func Merge(
 		allNames[name] = struct{}{}
 	}
 
-	// Build unified repo list
+	// Build unified repo list in name order so every poll produces the same
+	// cache file and SSE payload for the same input
 	var result []model.Repo
-	for name := range allNames {
+	for _, name := range slices.Sorted(maps.Keys(allNames)) {
 		repo := model.Repo{Name: name}
 
 		// Get GitHub data if available
@@ -58,6 +69,7 @@ func Merge(
 				repo.FullName = name
 			}
 			repo.Visibility = parseVisibility(ghRepo.Visibility)
+			repo.Owner = ghRepo.Owner
 			repo.Description = ghRepo.Description
 			repo.HomepageURL = ghRepo.HomepageURL
 
@@ -70,15 +82,28 @@ func Merge(
 				repo.Topics = topics
 			}
 
+			if ghRepo.CreatedAt != "" {
+				if createTime, err := time.Parse(time.RFC3339, ghRepo.CreatedAt); err == nil {
+					repo.CreatedAt = createTime.UTC()
+				}
+			}
+
 			// Parse pushedAt for lifecycle calculation
 			if ghRepo.PushedAt != "" {
 				if pushTime, err := time.Parse(time.RFC3339, ghRepo.PushedAt); err == nil {
-					repo.GitHubLastPush = pushTime
+					repo.GitHubLastPush = pushTime.UTC()
 				}
 			}
 
 			// Activity data from per-repo GitHub fetches
 			repo.OpenPRs = ghRepo.OpenPRs
+			repo.OpenIssues = ghRepo.OpenIssues
+			repo.Archived = ghRepo.IsArchived
+			repo.Fork = ghRepo.IsFork
+			repo.Stars = ghRepo.StargazerCount
+			repo.Forks = ghRepo.ForkCount
+			repo.HasWiki = ghRepo.HasWikiEnabled
+			repo.HasDiscussions = ghRepo.HasDiscussionsEnabled
 			repo.ActionsStatus = model.ActionsStatus(ghRepo.ActionsStatus)
 
 			// Completeness info
@@ -92,13 +117,17 @@ func Merge(
 				repo.Completeness.HasAgentsMd = ghRepo.FilePresence.HasAGENTSmd
 				repo.Completeness.HasProjectJson = ghRepo.FilePresence.HasProjectJson
 			}
+			if ghRepo.Project != nil {
+				repo.ProjectGroup = ghRepo.Project.Group
+				repo.ProjectStatus = ghRepo.Project.Status
+			}
 
 			// Release info
 			if ghRepo.LatestRelease != nil {
 				pubTime, _ := time.Parse(time.RFC3339, ghRepo.LatestRelease.PublishedAt)
 				repo.LatestRelease = &model.ReleaseInfo{
 					TagName:     ghRepo.LatestRelease.TagName,
-					PublishedAt: pubTime,
+					PublishedAt: pubTime.UTC(),
 				}
 
 				// Check if this is a new release
@@ -109,9 +138,12 @@ func Merge(
 				}
 			}
 
-			// Default branch name (for non-cloned repos)
-			if !hasLocal && ghRepo.DefaultBranch != nil {
-				repo.Branch = ghRepo.DefaultBranch.Name
+			// Default branch name (shown as Branch for non-cloned repos)
+			if ghRepo.DefaultBranch != nil {
+				repo.DefaultBranch = ghRepo.DefaultBranch.Name
+				if !hasLocal {
+					repo.Branch = ghRepo.DefaultBranch.Name
+				}
 			}
 		}
 
@@ -122,13 +154,51 @@ func Merge(
 			repo.Branch = localRepo.Branch
 			repo.Dirty = localRepo.Dirty
 			repo.LocalLastCommit = localRepo.LastCommit
+			repo.Untracked = localRepo.Untracked
+			repo.Modified = localRepo.Modified
+			repo.Staged = localRepo.Staged
+			repo.Ahead = localRepo.Ahead
+			repo.Behind = localRepo.Behind
+			repo.UnpushedTags = localRepo.UnpushedTags
+
+			// Files on disk are current and cost no API calls
+			if localRepo.FilePresence != nil {
+				repo.Completeness.HasReadme = localRepo.FilePresence.HasREADME
+				repo.Completeness.HasLicense = localRepo.FilePresence.HasLICENSE
+				repo.Completeness.HasClaudeMd = localRepo.FilePresence.HasCLAUDEmd
+				repo.Completeness.HasAgentsMd = localRepo.FilePresence.HasAGENTSmd
+				repo.Completeness.HasProjectJson = localRepo.FilePresence.HasProjectJson
+			}
+
+			// The local .project.json is authoritative for cloned repos
+			if localRepo.HasProjectFile {
+				repo.Completeness.HasProjectJson = true
+				repo.ProjectGroup = ""
+				repo.ProjectStatus = ""
+				if localRepo.Project != nil {
+					repo.ProjectGroup = localRepo.Project.Group
+					repo.ProjectStatus = localRepo.Project.Status
+				}
+			}
 		} else {
 			repo.Cloned = false
 			repo.LocalPath = fmt.Sprintf("%s/%s", scanPath, name)
 		}
 
+		// User state
+		if stateEntry, ok := state[name]; ok && stateEntry != nil {
+			repo.SnoozeUntil = stateEntry.SnoozeUntil
+			repo.Hidden = stateEntry.Hidden || !stateEntry.AutoHiddenAt.IsZero()
+			repo.Tags = stateEntry.Tags
+			repo.Note = stateEntry.Note
+		}
+
 		// Compute lifecycle
 		repo.Lifecycle = repo.ComputeLifecycle(thresholds)
+		repo.NeedsAttention = repo.ComputeNeedsAttention()
+		repo.Completeness.Score = repo.CompletenessScore()
+		repo.SuggestArchive = repo.ComputeSuggestArchive()
+		repo.IsNew = repo.ComputeIsNew(thresholds.NewRepoDays)
 
 		result = append(result, repo)
 	}
@@ -136,6 +206,39 @@ func Merge(
 	return result
 }
 
+// matchLocalByContent returns a copy of localRepos with repos that match
+// a GitHub repo only by GitHubName keyed by the GitHub name, or nil when
+// no repo needs re-keying.
+func matchLocalByContent(localRepos map[string]LocalRepo, githubMap map[string]GitHubRepo) map[string]LocalRepo {
+	var matched map[string]LocalRepo
+	for name, local := range localRepos {
+		if _, ok := githubMap[name]; ok || local.GitHubName == "" || local.GitHubName == name {
+			continue
+		}
+		if _, ok := githubMap[local.GitHubName]; !ok {
+			continue
+		}
+		// A clone under the repo's own name wins
+		if _, ok := localRepos[local.GitHubName]; ok {
+			continue
+		}
+		if matched == nil {
+			matched = make(map[string]LocalRepo, len(localRepos))
+			for k, v := range localRepos {
+				matched[k] = v
+			}
+		}
+		if _, taken := matched[local.GitHubName]; taken {
+			// Two directories claim the same repo; keep them separate
+			continue
+		}
+		delete(matched, name)
+		local.Name = local.GitHubName
+		matched[local.GitHubName] = local
+	}
+	return matched
+}
+
 // parseVisibility converts GitHub visibility string to model.Visibility.
 func parseVisibility(v string) model.Visibility {
 	switch v {
@@ -1,6 +1,11 @@
 package scanner_test
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -375,3 +380,289 @@ func TestMergeNoPreviousRelease(t *testing.T) {
 		t.Error("NewRelease = false, want true (first release seen)")
 	}
 }
+
+// TestMergeEmptyClonedRepo tests that a cloned repo with no commits merges with its GitHub data.
+func TestMergeEmptyClonedRepo(t *testing.T) {
+	localRepos := map[string]scanner.LocalRepo{
+		"empty-repo": {
+			Name:   "empty-repo",
+			Path:   "/path/to/empty-repo",
+			Branch: "main",
+			// No commits yet: LastCommit is zero
+		},
+	}
+
+	githubRepos := []scanner.GitHubRepo{
+		{
+			Name:        "empty-repo",
+			Description: "Just created",
+			Visibility:  "private",
+		},
+	}
+
+	thresholds := model.LifecycleThresholds{
+		StaleDays:     30,
+		AbandonedDays: 90,
+	}
+
+	result := scanner.Merge(localRepos, githubRepos, "/test/path", cache.RepoState{}, thresholds)
+
+	if len(result) != 1 {
+		t.Fatalf("len(result) = %d, want 1", len(result))
+	}
+
+	repo := result[0]
+	if !repo.Cloned {
+		t.Error("Cloned = false, want true")
+	}
+	if repo.LocalPath != "/path/to/empty-repo" {
+		t.Errorf("LocalPath = %s, want /path/to/empty-repo", repo.LocalPath)
+	}
+	if !repo.LocalLastCommit.IsZero() {
+		t.Errorf("LocalLastCommit = %v, want zero", repo.LocalLastCommit)
+	}
+	if repo.Description != "Just created" {
+		t.Errorf("Description = %s, want 'Just created'", repo.Description)
+	}
+}
+
+// TestMergeStarsAndForks tests that star and fork counts survive the merge.
+func TestMergeStarsAndForks(t *testing.T) {
+	githubRepos := []scanner.GitHubRepo{
+		{
+			Name:           "popular-repo",
+			Visibility:     "public",
+			StargazerCount: 120,
+			ForkCount:      15,
+		},
+	}
+
+	thresholds := model.LifecycleThresholds{
+		StaleDays:     30,
+		AbandonedDays: 90,
+	}
+
+	result := scanner.Merge(map[string]scanner.LocalRepo{}, githubRepos, "/test/path", cache.RepoState{}, thresholds)
+	if len(result) != 1 {
+		t.Fatalf("len(result) = %d, want 1", len(result))
+	}
+
+	if result[0].Stars != 120 {
+		t.Errorf("Stars = %d, want 120", result[0].Stars)
+	}
+	if result[0].Forks != 15 {
+		t.Errorf("Forks = %d, want 15", result[0].Forks)
+	}
+}
+
+// TestMergeWikiAndDiscussions tests that wiki and discussions flags populate from GitHub.
+func TestMergeWikiAndDiscussions(t *testing.T) {
+	githubRepos := []scanner.GitHubRepo{
+		{Name: "wiki-repo", Visibility: "public", HasWikiEnabled: true},
+		{Name: "community-repo", Visibility: "public", HasWikiEnabled: true, HasDiscussionsEnabled: true},
+		{Name: "bare-repo", Visibility: "public"},
+	}
+
+	thresholds := model.LifecycleThresholds{
+		StaleDays:     30,
+		AbandonedDays: 90,
+	}
+
+	result := scanner.Merge(map[string]scanner.LocalRepo{}, githubRepos, "/test/path", cache.RepoState{}, thresholds)
+
+	want := map[string][2]bool{
+		"wiki-repo":      {true, false},
+		"community-repo": {true, true},
+		"bare-repo":      {false, false},
+	}
+	for _, repo := range result {
+		w := want[repo.Name]
+		if repo.HasWiki != w[0] || repo.HasDiscussions != w[1] {
+			t.Errorf("%s: HasWiki=%v HasDiscussions=%v, want %v %v", repo.Name, repo.HasWiki, repo.HasDiscussions, w[0], w[1])
+		}
+	}
+}
+
+func TestMergeCreatedAtAndIsNew(t *testing.T) {
+	recent := time.Now().UTC().Add(-48 * time.Hour).Truncate(time.Second)
+	githubRepos := []scanner.GitHubRepo{
+		{Name: "fresh-repo", Visibility: "public", CreatedAt: recent.Format(time.RFC3339)},
+		{Name: "old-repo", Visibility: "public", CreatedAt: "2020-01-01T00:00:00Z"},
+		{Name: "unknown-repo", Visibility: "public"},
+	}
+
+	thresholds := model.LifecycleThresholds{
+		StaleDays:     30,
+		AbandonedDays: 90,
+		NewRepoDays:   7,
+	}
+
+	result := scanner.Merge(map[string]scanner.LocalRepo{}, githubRepos, "/test/path", cache.RepoState{}, thresholds)
+
+	byName := make(map[string]model.Repo)
+	for _, repo := range result {
+		byName[repo.Name] = repo
+	}
+	if got := byName["fresh-repo"]; !got.CreatedAt.Equal(recent) || !got.IsNew {
+		t.Errorf("fresh-repo: CreatedAt=%v IsNew=%v, want %v true", got.CreatedAt, got.IsNew, recent)
+	}
+	if got := byName["old-repo"]; got.CreatedAt.Year() != 2020 || got.IsNew {
+		t.Errorf("old-repo: CreatedAt=%v IsNew=%v, want 2020 false", got.CreatedAt, got.IsNew)
+	}
+	if got := byName["unknown-repo"]; !got.CreatedAt.IsZero() || got.IsNew {
+		t.Errorf("unknown-repo: CreatedAt=%v IsNew=%v, want zero false", got.CreatedAt, got.IsNew)
+	}
+}
+
+func TestMergeMatchesLocalByMarker(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "work-copy")
+	if err := os.MkdirAll(filepath.Join(dir, ".git"), 0o755); err != nil {
+		t.Fatalf("Failed to create repo dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".catscan-repo"), []byte("alexcatdad/meowtern\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write marker: %v", err)
+	}
+
+	githubName, err := scanner.ReadRepoIdentity(dir)
+	if err != nil {
+		t.Fatalf("ReadRepoIdentity failed: %v", err)
+	}
+	if githubName != "meowtern" {
+		t.Fatalf("ReadRepoIdentity = %q, want meowtern", githubName)
+	}
+
+	localRepos := map[string]scanner.LocalRepo{
+		"work-copy": {Name: "work-copy", Path: dir, Branch: "main", GitHubName: githubName},
+	}
+	githubRepos := []scanner.GitHubRepo{
+		{Name: "meowtern", Visibility: "public", Description: "A cat-themed tool"},
+	}
+
+	thresholds := model.LifecycleThresholds{
+		StaleDays:     30,
+		AbandonedDays: 90,
+	}
+
+	result := scanner.Merge(localRepos, githubRepos, filepath.Dir(dir), cache.RepoState{}, thresholds)
+
+	if len(result) != 1 {
+		t.Fatalf("len(result) = %d, want 1: %+v", len(result), result)
+	}
+	repo := result[0]
+	if repo.Name != "meowtern" || !repo.Cloned || repo.LocalPath != dir || repo.Branch != "main" || repo.Description != "A cat-themed tool" {
+		t.Errorf("merged repo = %+v, want meowtern cloned at %s with GitHub data", repo, dir)
+	}
+	if _, ok := localRepos["meowtern"]; ok {
+		t.Error("Merge modified the caller's local repo map")
+	}
+}
+
+func TestReadRepoIdentityPrefersOriginRemote(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, ".git"), 0o755); err != nil {
+		t.Fatalf("Failed to create repo dir: %v", err)
+	}
+	gitConfig := "[core]\n\tbare = false\n[remote \"upstream\"]\n\turl = https://github.com/other/fork.git\n[remote \"origin\"]\n\turl = git@github.com:alexcatdad/purrfect.git\n"
+	if err := os.WriteFile(filepath.Join(dir, ".git", "config"), []byte(gitConfig), 0o644); err != nil {
+		t.Fatalf("Failed to write git config: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".catscan-repo"), []byte("meowtern"), 0o644); err != nil {
+		t.Fatalf("Failed to write marker: %v", err)
+	}
+
+	name, err := scanner.ReadRepoIdentity(dir)
+	if err != nil {
+		t.Fatalf("ReadRepoIdentity failed: %v", err)
+	}
+	if name != "purrfect" {
+		t.Errorf("ReadRepoIdentity = %q, want purrfect", name)
+	}
+}
+
+// TestMergeDeterministicOrder tests that Merge returns repos in name order,
+// identically on every run, despite building the list from maps.
+func TestMergeDeterministicOrder(t *testing.T) {
+	localRepos := map[string]scanner.LocalRepo{}
+	var githubRepos []scanner.GitHubRepo
+	for i := range 20 {
+		name := fmt.Sprintf("repo-%02d", (i*7)%20)
+		switch i % 3 {
+		case 0:
+			localRepos[name] = scanner.LocalRepo{Name: name, Path: "/test/path/" + name, Branch: "main"}
+		case 1:
+			githubRepos = append(githubRepos, scanner.GitHubRepo{Name: name, Visibility: "public"})
+		default:
+			localRepos[name] = scanner.LocalRepo{Name: name, Path: "/test/path/" + name, Branch: "main"}
+			githubRepos = append(githubRepos, scanner.GitHubRepo{Name: name, Visibility: "private"})
+		}
+	}
+
+	thresholds := model.LifecycleThresholds{
+		StaleDays:     30,
+		AbandonedDays: 90,
+	}
+
+	first := scanner.Merge(localRepos, githubRepos, "/test/path", cache.RepoState{}, thresholds)
+	second := scanner.Merge(localRepos, githubRepos, "/test/path", cache.RepoState{}, thresholds)
+
+	firstJSON, err := json.Marshal(first)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	secondJSON, err := json.Marshal(second)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if !bytes.Equal(firstJSON, secondJSON) {
+		t.Error("Merge output differs between runs on the same input")
+	}
+
+	if len(first) != 20 {
+		t.Fatalf("len(result) = %d, want 20", len(first))
+	}
+	for i := 1; i < len(first); i++ {
+		if first[i-1].Name >= first[i].Name {
+			t.Errorf("result not in name order: %s before %s", first[i-1].Name, first[i].Name)
+		}
+	}
+}
+
+// TestMergeUsesLocalFilePresence tests that a cloned repo's completeness is
+// read from its working tree, with no GitHub data at all.
+func TestMergeUsesLocalFilePresence(t *testing.T) {
+	repoPath := filepath.Join(t.TempDir(), "local-only")
+	if err := os.MkdirAll(repoPath, 0o755); err != nil {
+		t.Fatalf("Failed to create repo dir: %v", err)
+	}
+	for _, name := range []string{"README.md", "LICENSE"} {
+		if err := os.WriteFile(filepath.Join(repoPath, name), []byte("x"), 0o644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	presence := scanner.LocalFilePresence(repoPath)
+	if presence == nil {
+		t.Fatal("LocalFilePresence() = nil")
+	}
+
+	localRepos := map[string]scanner.LocalRepo{
+		"local-only": {Name: "local-only", Path: repoPath, Branch: "main", FilePresence: presence},
+	}
+	thresholds := model.LifecycleThresholds{
+		StaleDays:     30,
+		AbandonedDays: 90,
+	}
+
+	result := scanner.Merge(localRepos, nil, filepath.Dir(repoPath), cache.RepoState{}, thresholds)
+	if len(result) != 1 {
+		t.Fatalf("len(result) = %d, want 1", len(result))
+	}
+
+	c := result[0].Completeness
+	if !c.HasReadme || !c.HasLicense {
+		t.Errorf("HasReadme = %v, HasLicense = %v, want both true", c.HasReadme, c.HasLicense)
+	}
+	if c.HasClaudeMd || c.HasAgentsMd || c.HasProjectJson {
+		t.Errorf("Completeness = %+v, want only README and LICENSE", c)
+	}
+}
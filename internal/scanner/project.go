@@ -0,0 +1,67 @@
+// Package scanner provides repository scanning functionality.
+//
+// The project subpackage parses .project.json metadata files.
+package scanner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// projectFileName is the per-repo metadata file.
+const projectFileName = ".project.json"
+
+// ProjectInfo holds the structured fields read from .project.json.
+type ProjectInfo struct {
+	Group  string `json:"group"`
+	Status string `json:"status"`
+}
+
+// ParseProjectFile parses the contents of a .project.json file.
+// Unknown keys are ignored.
+func ParseProjectFile(data []byte) (*ProjectInfo, error) {
+	var info ProjectInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", projectFileName, err)
+	}
+	return &info, nil
+}
+
+// ReadProjectFile reads .project.json from a local repository.
+// Returns exists=false when the file is missing. A malformed file returns
+// exists=true with a nil info and the parse error.
+func ReadProjectFile(repoPath string) (info *ProjectInfo, exists bool, err error) {
+	data, err := os.ReadFile(filepath.Join(repoPath, projectFileName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("reading %s: %w", projectFileName, err)
+	}
+
+	info, err = ParseProjectFile(data)
+	return info, true, err
+}
+
+// GetProjectFile fetches and parses .project.json for a repository via gh.
+// Returns nil without error when the file doesn't exist.
+func GetProjectFile(owner, name string) (*ProjectInfo, error) {
+	if dir := FixtureDir(); dir != "" {
+		details, err := readFixtureDetails(dir, name)
+		return details.Project, err
+	}
+
+	output, err := runGH("api", fmt.Sprintf("repos/%s/%s/contents/%s", owner, name, projectFileName), "-H", "Accept: application/vnd.github.raw")
+	if err != nil {
+		if strings.Contains(err.Error(), "404") || strings.Contains(err.Error(), "Not Found") {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("getting %s: %w", projectFileName, err)
+	}
+
+	return ParseProjectFile([]byte(output))
+}
@@ -0,0 +1,55 @@
+package scanner_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alexcatdad/catscan/internal/scanner"
+)
+
+// TestParseProjectFile tests parsing group and status from a sample .project.json.
+func TestParseProjectFile(t *testing.T) {
+	data := []byte(`{
+  "what": "A local-only GitHub repository dashboard.",
+  "tags": ["go", "svelte"],
+  "group": "devtools",
+  "status": "active"
+}`)
+
+	info, err := scanner.ParseProjectFile(data)
+	if err != nil {
+		t.Fatalf("ParseProjectFile() error = %v", err)
+	}
+	if info.Group != "devtools" {
+		t.Errorf("Group = %q, want devtools", info.Group)
+	}
+	if info.Status != "active" {
+		t.Errorf("Status = %q, want active", info.Status)
+	}
+}
+
+// TestReadProjectFileMalformed tests that a malformed file is reported present with no fields.
+func TestReadProjectFileMalformed(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	info, exists, err := scanner.ReadProjectFile(tmpDir)
+	if err != nil || exists || info != nil {
+		t.Errorf("missing file: got (%v, %v, %v), want (nil, false, nil)", info, exists, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(tmpDir, ".project.json"), []byte(`{"group": `), 0o644); err != nil {
+		t.Fatalf("Failed to write project file: %v", err)
+	}
+
+	info, exists, err = scanner.ReadProjectFile(tmpDir)
+	if err == nil {
+		t.Error("expected parse error for malformed file")
+	}
+	if !exists {
+		t.Error("exists = false, want true for malformed file")
+	}
+	if info != nil {
+		t.Errorf("info = %+v, want nil", info)
+	}
+}
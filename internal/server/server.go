@@ -5,17 +5,26 @@
 package server
 
 import (
+	"bytes"
+	"cmp"
+	"compress/gzip"
 	"context"
+	"crypto/sha256"
+	"crypto/subtle"
 	"encoding/json"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
 	"net"
 	"net/http"
 	"net/url"
 	"os"
 	"os/exec"
 	"os/signal"
+	"reflect"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"syscall"
@@ -23,6 +32,7 @@ import (
 
 	"github.com/alexcatdad/catscan/internal/cache"
 	"github.com/alexcatdad/catscan/internal/config"
+	"github.com/alexcatdad/catscan/internal/logging"
 	"github.com/alexcatdad/catscan/internal/model"
 	"github.com/alexcatdad/catscan/internal/poller"
 	"github.com/alexcatdad/catscan/internal/scanner"
@@ -42,42 +52,124 @@ type Server struct {
 	shutdownCancel   context.CancelFunc
 	wg               sync.WaitGroup
 	mu               sync.RWMutex
+
+	// Last gh auth check, reused for ghAuthCacheTTL
+	ghAuth    *scanner.GHAuthStatus
+	ghAuthErr error
+	ghAuthAt  time.Time
+	ghAuthMu  sync.Mutex
+
+	// Owner profiles fetched via gh, cached long-term
+	owners   map[string]ownerCacheEntry
+	ownersMu sync.Mutex
+
+	// Slots limiting how many bulk clones run at once
+	cloneSlots chan struct{}
+}
+
+// startupSummary describes the effective config on one line, so the log
+// shows at a glance what CatScan is scanning and where it notifies.
+func startupSummary(cfg *config.Config, ghAvailable bool) string {
+	owners := cfg.GitHubOwner
+	if cfg.IncludeAllOrgs {
+		owners += "+orgs"
+	}
+	notify := cmp.Or(cfg.NotificationMode, config.NotificationModeDesktop)
+	return fmt.Sprintf("config: scanPath=%s owner=%s host=%s port=%d localInterval=%ds githubInterval=%ds notifications=%s readOnly=%t gh=%t",
+		cfg.ScanPath, owners, cmp.Or(cfg.BindHost, config.DefaultBindHost), cfg.Port, cfg.LocalIntervalSeconds, cfg.GitHubIntervalSeconds, notify, cfg.ReadOnly, ghAvailable)
+}
+
+// ownerCacheTTL is how long a fetched owner profile is reused.
+const ownerCacheTTL = 24 * time.Hour
+
+// ownerCacheEntry holds a cached owner profile and when it was fetched.
+type ownerCacheEntry struct {
+	info      *scanner.OwnerInfo
+	fetchedAt time.Time
 }
 
+// fetchOwnerInfo fetches an owner profile. Overridable in tests.
+var fetchOwnerInfo = scanner.GetOwnerInfo
+
+// archiveRepo archives a repository on GitHub. Overridable in tests.
+var archiveRepo = scanner.ArchiveRepo
+
+// cloneRepo clones a repository from GitHub. Overridable in tests.
+var cloneRepo = scanner.CloneRepo
+
+// Version is the CatScan build version, set at build time with
+// -ldflags "-X github.com/alexcatdad/catscan/internal/server.Version=...".
+var Version = "dev"
+
+// checkGHAuth reports the gh login and missing token scopes. Overridable in tests.
+var checkGHAuth = scanner.CheckGHAuth
+
+// ghAuthCacheTTL is how long a gh auth check is reused by the health
+// endpoint, so polling health doesn't spawn gh each time.
+const ghAuthCacheTTL = 30 * time.Second
+
+// maxConcurrentClones bounds how many clones a bulk clone runs at once.
+const maxConcurrentClones = 3
+
 // NewServer creates a new Server.
+// The config is validated with the same rules as runtime reconfiguration.
 func NewServer(cfg *config.Config) (*Server, error) {
-	hub := sse.NewHub()
-	p := poller.NewPoller(cfg, hub)
-
 	s := &Server{
-		cfg:       cfg,
-		hub:       hub,
-		poller:    p,
-		startTime: time.Now(),
-		distDir:   "dist",
+		cfg:        cfg,
+		startTime:  time.Now(),
+		distDir:    "dist",
+		owners:     make(map[string]ownerCacheEntry),
+		cloneSlots: make(chan struct{}, maxConcurrentClones),
+	}
+	if err := config.Validate(cfg); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	// Serve GitHub data from fixtures instead of gh when configured
+	if cfg.GitHubFixtureDir != "" {
+		scanner.SetFixtureDir(cfg.GitHubFixtureDir)
 	}
 
+	s.hub = sse.NewHubWithOptions(sse.HubOptions{
+		HistorySize:      cfg.SSEHistorySize,
+		ClientBufferSize: cfg.SSEClientBufferSize,
+		MaxClients:       cfg.SSEMaxClients,
+	})
+	s.poller = poller.NewPoller(cfg, s.hub)
+
 	// Create shutdown context
 	s.shutdownCtx, s.shutdownCancel = context.WithCancel(context.Background())
 
 	return s, nil
 }
 
+// config returns the current config. Reconfigure swaps the pointer rather
+// than modifying the config, so the returned value must not be modified.
+func (s *Server) config() *config.Config {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.cfg
+}
+
 // Start starts the HTTP server.
 // This blocks until the server is stopped.
 func (s *Server) Start() error {
 	// Create listener
-	addr := fmt.Sprintf("127.0.0.1:%d", s.cfg.Port)
-	listener, err := net.Listen("tcp", addr)
+	listener, err := s.listen()
 	if err != nil {
-		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+		return err
 	}
 	s.listener = listener
+	addr := listener.Addr().String()
+	cfg := s.config()
+	if !isLoopbackHost(cmp.Or(cfg.BindHost, config.DefaultBindHost)) && cfg.AuthToken == "" {
+		slog.Warn("listening without an authToken; anyone on the network can use the API", "addr", addr)
+	}
 
 	// Create HTTP server
 	mux := http.NewServeMux()
 	s.server = &http.Server{
-		Handler:     s.withHeaders(mux),
+		Handler:     s.withHeaders(s.withAuth(withGzip(mux))),
 		ReadTimeout: 15 * time.Second,
 		// WriteTimeout must be 0 for SSE — a non-zero value kills
 		// long-lived connections after the timeout elapses.
@@ -102,7 +194,9 @@ func (s *Server) Start() error {
 		s.poller.Start(s.shutdownCtx)
 	}()
 
-	log.Printf("CatScan starting on http://%s", addr)
+	slog.Info("CatScan starting", "url", "http://"+addr)
+	_, ghErr := exec.LookPath("gh")
+	slog.Info(startupSummary(cfg, ghErr == nil))
 
 	// Start server in a goroutine
 	serverErr := make(chan error, 1)
@@ -118,9 +212,9 @@ func (s *Server) Start() error {
 
 	select {
 	case sig := <-sigChan:
-		log.Printf("Received signal %v, shutting down...", sig)
+		slog.Info("received signal, shutting down", "signal", sig.String())
 	case err := <-serverErr:
-		log.Printf("Server error: %v", err)
+		slog.Error("server error", "err", err)
 		return err
 	}
 
@@ -130,9 +224,37 @@ func (s *Server) Start() error {
 	return <-serverErr
 }
 
+// listen opens the TCP listener on the configured host and port.
+func (s *Server) listen() (net.Listener, error) {
+	addr := listenAddr(s.config())
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+	return listener, nil
+}
+
+// listenAddr returns the host:port to listen on, defaulting the host to
+// config.DefaultBindHost.
+func listenAddr(cfg *config.Config) string {
+	return net.JoinHostPort(cmp.Or(cfg.BindHost, config.DefaultBindHost), strconv.Itoa(cfg.Port))
+}
+
+// isLoopbackHost reports whether host only accepts local connections.
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
 // Shutdown gracefully shuts down the server.
 func (s *Server) Shutdown() {
-	log.Println("Shutting down...")
+	slog.Info("shutting down")
+
+	// No notifier subprocesses from here on; they could outlive the process
+	poller.StopNotifications()
 
 	// Cancel pollers and SSE hub
 	s.shutdownCancel()
@@ -142,7 +264,7 @@ func (s *Server) Shutdown() {
 	defer cancel()
 
 	if err := s.server.Shutdown(ctx); err != nil {
-		log.Printf("Server shutdown error: %v", err)
+		slog.Error("server shutdown failed", "err", err)
 	}
 
 	// Close listener
@@ -153,7 +275,12 @@ func (s *Server) Shutdown() {
 	// Wait for all goroutines to finish
 	s.wg.Wait()
 
-	log.Println("Shutdown complete")
+	// Write any single-repo changes still waiting for the debounced flush
+	if err := cache.Flush(); err != nil {
+		slog.Error("cache flush failed", "err", err)
+	}
+
+	slog.Info("shutdown complete")
 }
 
 // withHeaders wraps the handler with security headers.
@@ -168,27 +295,199 @@ func (s *Server) withHeaders(h http.Handler) http.Handler {
 	})
 }
 
+// withAuth requires the configured AuthToken as a bearer token on /api/
+// routes, responding 401 when it is missing or wrong. Static assets stay
+// open so the dashboard can load. EventSource can't set headers, so the
+// events stream also accepts the token as ?token=.
+func (s *Server) withAuth(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.RLock()
+		token := s.cfg.AuthToken
+		s.mu.RUnlock()
+
+		if token == "" || !strings.HasPrefix(r.URL.Path, "/api/") {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok && r.URL.Path == "/api/events" {
+			got, ok = r.URL.Query().Get("token"), true
+		}
+		if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("WWW-Authenticate", `Bearer realm="catscan"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			jsonEncoder(w, r).Encode(map[string]string{"error": "unauthorized"})
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// gzipMinBytes is the smallest response body worth compressing.
+const gzipMinBytes = 1024
+
+// withGzip wraps the handler with gzip compression for clients that accept
+// it. Bodies under gzipMinBytes are sent as-is. The SSE endpoint is never
+// compressed: the gzip writer would hold events back instead of flushing them.
+func withGzip(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/events" || !acceptsGzip(r) {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Add("Vary", "Accept-Encoding")
+		gw := &gzipResponseWriter{ResponseWriter: w, status: http.StatusOK}
+		defer gw.Close()
+		h.ServeHTTP(gw, r)
+	})
+}
+
+// acceptsGzip reports whether the request's Accept-Encoding allows gzip.
+func acceptsGzip(r *http.Request) bool {
+	for _, part := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if strings.TrimSpace(coding) != "gzip" {
+			continue
+		}
+		return strings.ReplaceAll(strings.TrimSpace(params), " ", "") != "q=0"
+	}
+	return false
+}
+
+// gzipResponseWriter buffers the start of a response until it is known to
+// reach gzipMinBytes, then switches to gzip. Close must be called after the
+// handler returns to send whatever is still buffered.
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	buf         []byte
+	gz          *gzip.Writer
+	passthrough bool
+}
+
+// WriteHeader records the status; it is sent once compression is decided.
+func (g *gzipResponseWriter) WriteHeader(status int) {
+	if g.wroteHeader {
+		return
+	}
+	g.wroteHeader = true
+	g.status = status
+}
+
+// Write buffers until gzipMinBytes, then compresses the rest.
+func (g *gzipResponseWriter) Write(p []byte) (int, error) {
+	g.wroteHeader = true
+	switch {
+	case g.gz != nil:
+		return g.gz.Write(p)
+	case g.passthrough:
+		return g.ResponseWriter.Write(p)
+	}
+
+	g.buf = append(g.buf, p...)
+	if len(g.buf) < gzipMinBytes {
+		return len(p), nil
+	}
+	if err := g.start(); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// start decides how to send the response and flushes the buffer. Partial
+// content and already-encoded responses are passed through unchanged.
+func (g *gzipResponseWriter) start() error {
+	header := g.Header()
+	if len(g.buf) < gzipMinBytes || g.status == http.StatusPartialContent || header.Get("Content-Encoding") != "" {
+		g.passthrough = true
+		g.ResponseWriter.WriteHeader(g.status)
+		_, err := g.ResponseWriter.Write(g.buf)
+		g.buf = nil
+		return err
+	}
+
+	header.Set("Content-Encoding", "gzip")
+	header.Del("Content-Length")
+	g.ResponseWriter.WriteHeader(g.status)
+	g.gz = gzip.NewWriter(g.ResponseWriter)
+	_, err := g.gz.Write(g.buf)
+	g.buf = nil
+	return err
+}
+
+// Close sends any buffered response and finishes the gzip stream.
+func (g *gzipResponseWriter) Close() error {
+	if g.gz == nil && !g.passthrough {
+		if !g.wroteHeader {
+			// Nothing was written; let the server send its default response
+			return nil
+		}
+		if err := g.start(); err != nil {
+			return err
+		}
+	}
+	if g.gz != nil {
+		return g.gz.Close()
+	}
+	return nil
+}
+
 // setupRoutes sets up all HTTP routes.
 func (s *Server) setupRoutes(mux *http.ServeMux) {
 	// API routes
 	mux.HandleFunc("/api/repos", s.handleReposList)
 	mux.HandleFunc("/api/repos/", s.handleRepoByName)
+	mux.HandleFunc("/api/clone", s.handleBulkClone)
+	mux.HandleFunc("/api/clones", s.handleClones)
 	mux.HandleFunc("/api/config", s.handleConfig)
 	mux.HandleFunc("/api/health", s.handleHealth)
+	mux.HandleFunc("/api/version", s.handleVersion)
+	mux.HandleFunc("/api/stats", s.handleStats)
+	mux.HandleFunc("/api/owners", s.handleOwners)
+	mux.HandleFunc("/api/refresh", s.handleRefresh)
+	mux.HandleFunc("/api/actions/acknowledge-all", s.handleAcknowledgeAllActions)
+	// SSE must stream: never wrap this route in buffering or compressing middleware
+	// (withGzip skips it by path)
 	mux.HandleFunc("/api/events", s.handleEvents)
 
 	// Static file serving for the Svelte frontend (dist/ directory)
 	mux.Handle("/", http.FileServer(http.Dir(s.distDir)))
 }
 
+// jsonEncoder returns a JSON encoder for a response. Output is compact
+// unless the request asks for indented JSON with ?pretty=true.
+func jsonEncoder(w io.Writer, r *http.Request) *json.Encoder {
+	enc := json.NewEncoder(w)
+	if pretty, _ := strconv.ParseBool(r.URL.Query().Get("pretty")); pretty {
+		enc.SetIndent("", "  ")
+	}
+	return enc
+}
+
 // handleReposList handles GET /api/repos with filtering and sorting.
 func (s *Server) handleReposList(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		w.WriteHeader(http.StatusMethodNotAllowed)
-		json.NewEncoder(w).Encode(map[string]string{"error": "method not allowed"})
+		jsonEncoder(w, r).Encode(map[string]string{"error": "method not allowed"})
 		return
 	}
 
+	// Resolve the field projection before doing any work
+	var fields []string
+	if v := r.URL.Query().Get("fields"); v != "" {
+		var err error
+		if fields, err = parseRepoFields(v); err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			jsonEncoder(w, r).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+	}
+
 	// Get repos from cache
 	repos, err := cache.ReadRepos()
 	if err != nil {
@@ -202,31 +501,199 @@ func (s *Server) handleReposList(w http.ResponseWriter, r *http.Request) {
 	// Apply sorting
 	repos = s.sortRepos(repos, r.URL.Query())
 
+	// An empty match is [] in JSON, never null
+	if repos == nil {
+		repos = []model.Repo{}
+	}
+
+	if fields != nil {
+		projected, err := projectRepos(repos, fields)
+		if err != nil {
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+		writeJSONWithETag(w, r, projected)
+		return
+	}
+
+	writeJSONWithETag(w, r, repos)
+}
+
+// compactRepoFields are the repo fields returned for fields=compact: what
+// the dashboard list view shows.
+var compactRepoFields = []string{"Name", "Lifecycle", "Cloned", "Dirty", "OpenPRs", "GitHubLastPush"}
+
+// repoJSONFields maps lowercased repo JSON field names to their canonical
+// spelling, so ?fields= matches case-insensitively.
+var repoJSONFields = func() map[string]string {
+	fields := make(map[string]string)
+	t := reflect.TypeFor[model.Repo]()
+	for i := range t.NumField() {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			fields[strings.ToLower(name)] = name
+		}
+	}
+	return fields
+}()
+
+// parseRepoFields resolves a ?fields= value, either "compact" or a comma
+// list of repo JSON field names, to canonical field names.
+func parseRepoFields(v string) ([]string, error) {
+	if v == "compact" {
+		return compactRepoFields, nil
+	}
+	var fields []string
+	for _, f := range splitFilterValues(v) {
+		name, ok := repoJSONFields[strings.ToLower(f)]
+		if !ok {
+			return nil, fmt.Errorf("unknown field %q", f)
+		}
+		fields = append(fields, name)
+	}
+	return fields, nil
+}
+
+// projectRepos returns each repo as a JSON object holding only the given
+// fields. Fields a repo omits from its JSON (omitempty) stay omitted.
+func projectRepos(repos []model.Repo, fields []string) ([]map[string]json.RawMessage, error) {
+	projected := make([]map[string]json.RawMessage, 0, len(repos))
+	for _, repo := range repos {
+		data, err := json.Marshal(repo)
+		if err != nil {
+			return nil, err
+		}
+		var all map[string]json.RawMessage
+		if err := json.Unmarshal(data, &all); err != nil {
+			return nil, err
+		}
+		obj := make(map[string]json.RawMessage, len(fields))
+		for _, f := range fields {
+			if v, ok := all[f]; ok {
+				obj[f] = v
+			}
+		}
+		projected = append(projected, obj)
+	}
+	return projected, nil
+}
+
+// writeJSONWithETag writes v as JSON with a weak ETag computed from the
+// encoded body, or 304 Not Modified when the request's If-None-Match
+// already names that ETag.
+func writeJSONWithETag(w http.ResponseWriter, r *http.Request, v interface{}) {
+	var body bytes.Buffer
+	if err := jsonEncoder(&body, r).Encode(v); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+	sum := sha256.Sum256(body.Bytes())
+	etag := fmt.Sprintf(`W/"%x"`, sum[:16])
+
+	w.Header().Set("ETag", etag)
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(body.Bytes())
+}
+
+// etagMatches reports whether an If-None-Match header value matches etag,
+// using the weak comparison RFC 9110 requires for If-None-Match.
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	want := strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == want {
+			return true
+		}
+	}
+	return false
+}
+
+// validateRepoName rejects repo names that could escape the scan path
+// when joined onto it: empty names, "." and "..", and names containing a
+// path separator. A leading "-" is rejected too, so a name can't be read
+// as an option by git.
+func validateRepoName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, "-") {
+		return fmt.Errorf("invalid repo name %q", name)
+	}
+	return nil
+}
+
+// writeInvalidRepoName responds 400 with err from validateRepoName.
+func writeInvalidRepoName(w http.ResponseWriter, r *http.Request, err error) {
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(repos)
+	w.WriteHeader(http.StatusBadRequest)
+	jsonEncoder(w, r).Encode(map[string]string{"error": err.Error()})
 }
 
-// handleRepoByName handles GET /api/repos/:name.
+// handleRepoByName handles GET and DELETE /api/repos/:name and dispatches
+// the /api/repos/:name/... sub-routes.
 func (s *Server) handleRepoByName(w http.ResponseWriter, r *http.Request) {
-	// Check if it's the clone endpoint
-	if strings.HasSuffix(r.URL.Path, "/clone") {
-		s.handleClone(w, r)
+	// Cut the path once: the name is the first segment, and whatever
+	// follows selects the sub-route, so a repo may be named after one
+	rest := strings.TrimPrefix(r.URL.Path, "/api/repos/")
+	name, sub, _ := strings.Cut(rest, "/")
+
+	// Reject names that could escape the scan path before any sub-route
+	// uses them
+	if err := validateRepoName(name); err != nil {
+		writeInvalidRepoName(w, r, err)
 		return
 	}
 
-	if r.Method != http.MethodGet {
-		w.WriteHeader(http.StatusMethodNotAllowed)
-		json.NewEncoder(w).Encode(map[string]string{"error": "method not allowed"})
+	switch sub {
+	case "":
+		// The repo itself, handled below
+	case "clone":
+		s.handleClone(w, r, name)
+		return
+	case "pull":
+		s.handlePull(w, r, name)
+		return
+	case "diff":
+		s.handleDiff(w, r, name)
+		return
+	case "archive":
+		s.handleArchive(w, r, name)
+		return
+	case "snooze":
+		s.handleSnooze(w, r, name)
+		return
+	case "hide", "unhide":
+		s.handleHide(w, r, name, sub == "hide")
+		return
+	case "state":
+		s.handleRepoState(w, r, name)
+		return
+	case "export":
+		s.handleExport(w, r, name)
+		return
+	case "import":
+		s.handleImport(w, r, name)
+		return
+	default:
+		// Not a sub-route, so the extra segments make the name invalid
+		writeInvalidRepoName(w, r, validateRepoName(rest))
+		return
+	}
+
+	if r.Method == http.MethodDelete {
+		s.handleDeleteRepo(w, r, name)
 		return
 	}
 
-	// Extract repo name from /api/repos/{name}
-	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/repos/"), "/")
-	if len(parts) == 0 || parts[0] == "" {
-		http.Error(w, "Repo name required", http.StatusBadRequest)
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		jsonEncoder(w, r).Encode(map[string]string{"error": "method not allowed"})
 		return
 	}
-	repoName := parts[0]
 
 	// Get repos from cache
 	repos, err := cache.ReadRepos()
@@ -237,9 +704,8 @@ func (s *Server) handleRepoByName(w http.ResponseWriter, r *http.Request) {
 
 	// Find the requested repo
 	for _, repo := range repos {
-		if repo.Name == repoName {
-			w.Header().Set("Content-Type", "application/json")
-			json.NewEncoder(w).Encode(repo)
+		if repo.Name == name {
+			writeJSONWithETag(w, r, repo)
 			return
 		}
 	}
@@ -247,171 +713,1031 @@ func (s *Server) handleRepoByName(w http.ResponseWriter, r *http.Request) {
 	// Not found
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusNotFound)
-	json.NewEncoder(w).Encode(map[string]string{"error": "repository not found"})
+	jsonEncoder(w, r).Encode(map[string]string{"error": "repository not found"})
 }
 
 // handleClone handles POST /api/repos/:name/clone.
-func (s *Server) handleClone(w http.ResponseWriter, r *http.Request) {
+func (s *Server) handleClone(w http.ResponseWriter, r *http.Request, repoName string) {
 	if r.Method != http.MethodPost {
 		w.WriteHeader(http.StatusMethodNotAllowed)
-		json.NewEncoder(w).Encode(map[string]string{"error": "method not allowed"})
+		jsonEncoder(w, r).Encode(map[string]string{"error": "method not allowed"})
 		return
 	}
 
-	// Extract repo name from path
-	parts := strings.Split(strings.TrimPrefix(strings.TrimSuffix(r.URL.Path, "/clone"), "/api/repos/"), "/")
-	if len(parts) == 0 || parts[0] == "" {
-		http.Error(w, "Repo name required", http.StatusBadRequest)
+	// Check if repo is already cloned locally
+	cfg := s.config()
+	cloned := scanner.FindClonedRepos([]string{repoName}, cfg.ScanPath)
+	if _, ok := cloned[repoName]; ok && !s.poller.IsCloning(repoName) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		jsonEncoder(w, r).Encode(map[string]string{"error": "repository already cloned"})
 		return
 	}
-	repoName := parts[0]
 
-	// Check if repo is already cloned locally
-	cloned := scanner.FindClonedRepos([]string{repoName}, s.cfg.ScanPath)
-	if _, ok := cloned[repoName]; ok {
+	// Clone options default from config, depth can be overridden per request
+	opts := scanner.CloneOptions{
+		Depth:    cfg.CloneDepth,
+		Protocol: cfg.CloneProtocol,
+	}
+	if v := r.URL.Query().Get("depth"); v != "" {
+		depth, err := strconv.Atoi(v)
+		if err != nil || depth < 0 {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			jsonEncoder(w, r).Encode(map[string]string{"error": "depth must be a non-negative integer"})
+			return
+		}
+		opts.Depth = depth
+	}
+	opts.Branch = r.URL.Query().Get("branch")
+
+	// Claim the clone before responding, so a second request can't start
+	// another clone into the same directory
+	if !s.poller.CloneStarted(repoName) {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusConflict)
-		json.NewEncoder(w).Encode(map[string]string{"error": "repository already cloned"})
+		jsonEncoder(w, r).Encode(map[string]string{"error": "clone already in progress"})
 		return
 	}
 
-	// Start clone asynchronously
-	statusChan := scanner.CloneRepo(s.cfg.GitHubOwner, repoName, s.cfg.ScanPath)
-
-	// Broadcast clone progress events in a goroutine
-	go func() {
-		for status := range statusChan {
-			s.hub.Broadcast("clone_progress", map[string]interface{}{
-				"repo":  status.Repo,
-				"state": status.State,
-				"error": status.Error,
-			})
-		}
-	}()
+	// Start clone asynchronously; it outlives this request but not the server
+	go s.runClone(repoName, opts)
 
 	// Return 202 Accepted
 	w.WriteHeader(http.StatusAccepted)
-	json.NewEncoder(w).Encode(map[string]string{"status": "clone started"})
+	jsonEncoder(w, r).Encode(map[string]string{"status": "clone started"})
 }
 
-// handleConfig handles GET/PUT /api/config.
-func (s *Server) handleConfig(w http.ResponseWriter, r *http.Request) {
-	switch r.Method {
-	case http.MethodGet:
-		s.handleGetConfig(w, r)
-	case http.MethodPut:
-		s.handlePutConfig(w, r)
-	default:
-		w.WriteHeader(http.StatusMethodNotAllowed)
-		json.NewEncoder(w).Encode(map[string]string{"error": "method not allowed"})
+// runClone clones a repo and broadcasts its clone_progress events,
+// returning when the clone finishes. Repos listed from an org clone from
+// that org. Without an explicit branch the clone checks out the repo's
+// GitHub default branch when the cache knows it. The caller must have
+// claimed the clone with CloneStarted.
+func (s *Server) runClone(repoName string, opts scanner.CloneOptions) {
+	defer s.poller.CloneFinished(repoName)
+	owner, defaultBranch := cachedCloneSource(repoName)
+	if opts.Branch == "" {
+		opts.Branch = defaultBranch
+	}
+
+	cfg := s.config()
+	statusChan := cloneRepo(s.shutdownCtx, cmp.Or(owner, cfg.GitHubOwner), repoName, cfg.ScanPath, opts)
+	for status := range statusChan {
+		s.poller.CloneProgress(status)
+		s.hub.Broadcast("clone_progress", map[string]interface{}{
+			"repo":  status.Repo,
+			"state": status.State,
+			"error": status.Error,
+		})
+		if status.State == scanner.CloneStateCompleted {
+			s.poller.NotifyCloneCompleted(owner, status.Repo)
+		}
 	}
 }
 
-// handleGetConfig handles GET /api/config.
-func (s *Server) handleGetConfig(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(s.cfg)
+// cachedCloneSource returns the org and GitHub default branch recorded for a
+// not-yet-cloned repo. Either is "" when the cache doesn't know it. Caches
+// written before DefaultBranch existed carry it only in Branch.
+func cachedCloneSource(repoName string) (owner, defaultBranch string) {
+	repos, err := cache.ReadRepos()
+	if err != nil {
+		return "", ""
+	}
+	for _, repo := range repos {
+		if repo.Name == repoName && !repo.Cloned {
+			if repo.DefaultBranch != "" {
+				return repo.Owner, repo.DefaultBranch
+			}
+			return repo.Owner, repo.Branch
+		}
+	}
+	return "", ""
 }
 
-// handlePutConfig handles PUT /api/config.
-func (s *Server) handlePutConfig(w http.ResponseWriter, r *http.Request) {
-	var newCfg config.Config
-	if err := json.NewDecoder(r.Body).Decode(&newCfg); err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": "invalid JSON"})
+// BulkCloneRequest is the body of POST /api/clone: either a list of repo
+// names, or All to clone every cached repo that isn't cloned yet.
+type BulkCloneRequest struct {
+	Names []string `json:"names"`
+	All   bool     `json:"all"`
+}
+
+// BulkCloneResponse summarizes a bulk clone: the repos queued for cloning
+// and those skipped because they are already cloned.
+type BulkCloneResponse struct {
+	Queued  []string `json:"queued"`
+	Skipped []string `json:"skipped"`
+}
+
+// handleBulkClone handles POST /api/clone.
+// Clones run in the background, at most maxConcurrentClones at a time,
+// and report progress through clone_progress events like single clones.
+func (s *Server) handleBulkClone(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		jsonEncoder(w, r).Encode(map[string]string{"error": "method not allowed"})
 		return
 	}
 
-	// Validate config
-	if err := s.validateConfig(&newCfg); err != nil {
+	var req BulkCloneRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxImportBytes)).Decode(&req); err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		jsonEncoder(w, r).Encode(map[string]string{"error": "invalid request body: " + err.Error()})
 		return
 	}
 
-	// Save config
-	if err := config.Save(newCfg); err != nil {
-		http.Error(w, "Failed to save config", http.StatusInternalServerError)
+	names := req.Names
+	if req.All {
+		repos, err := cache.ReadRepos()
+		if err != nil {
+			http.Error(w, "Failed to read cache", http.StatusInternalServerError)
+			return
+		}
+		names = nil
+		for _, repo := range repos {
+			if !repo.Cloned && !repo.Archived {
+				names = append(names, repo.Name)
+			}
+		}
+	} else if len(names) == 0 {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		jsonEncoder(w, r).Encode(map[string]string{"error": "names or all is required"})
 		return
 	}
 
-	// Update server config
-	s.mu.Lock()
-	s.cfg = &newCfg
-	s.mu.Unlock()
-
-	// Notify connected clients that config changed
-	s.hub.Broadcast("config_updated", newCfg)
-
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(newCfg)
-}
-
-// validateConfig validates the config values.
-func (s *Server) validateConfig(cfg *config.Config) error {
-	if cfg.ScanPath == "" {
-		return fmt.Errorf("scanPath cannot be empty")
-	}
-	if cfg.Port < 1024 || cfg.Port > 65535 {
-		return fmt.Errorf("port must be between 1024 and 65535")
-	}
-	if cfg.LocalIntervalSeconds < 10 {
-		return fmt.Errorf("localIntervalSeconds must be at least 10")
-	}
-	if cfg.GitHubIntervalSeconds < 60 {
-		return fmt.Errorf("githubIntervalSeconds must be at least 60")
-	}
-	if cfg.StaleDays < 1 {
-		return fmt.Errorf("staleDays must be at least 1")
+	// Names become directories under the scan path
+	for _, name := range names {
+		if err := validateRepoName(name); err != nil {
+			writeInvalidRepoName(w, r, err)
+			return
+		}
 	}
-	if cfg.AbandonedDays < 1 {
-		return fmt.Errorf("abandonedDays must be at least 1")
+
+	cfg := s.config()
+	opts := scanner.CloneOptions{
+		Depth:    cfg.CloneDepth,
+		Protocol: cfg.CloneProtocol,
 	}
-	if cfg.StaleDays >= cfg.AbandonedDays {
-		return fmt.Errorf("staleDays must be less than abandonedDays")
+
+	cloned := scanner.FindClonedRepos(names, cfg.ScanPath)
+	resp := BulkCloneResponse{Queued: []string{}, Skipped: []string{}}
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		if _, ok := cloned[name]; ok || !s.poller.CloneStarted(name) {
+			resp.Skipped = append(resp.Skipped, name)
+			continue
+		}
+		resp.Queued = append(resp.Queued, name)
+
+		// Queued clones wait for a slot; they outlive this request but not the server
+		go func() {
+			select {
+			case s.cloneSlots <- struct{}{}:
+			case <-s.shutdownCtx.Done():
+				s.poller.CloneFinished(name)
+				return
+			}
+			defer func() { <-s.cloneSlots }()
+			s.runClone(name, opts)
+		}()
 	}
-	return nil
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	jsonEncoder(w, r).Encode(resp)
 }
 
-// handleHealth handles GET /api/health.
-func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+// handleClones handles GET /api/clones: clones in progress, then recently
+// finished clones with their outcome.
+func (s *Server) handleClones(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		w.WriteHeader(http.StatusMethodNotAllowed)
-		json.NewEncoder(w).Encode(map[string]string{"error": "method not allowed"})
+		jsonEncoder(w, r).Encode(map[string]string{"error": "method not allowed"})
 		return
 	}
 
-	// Get repo count
-	repos, _ := cache.ReadRepos()
+	w.Header().Set("Content-Type", "application/json")
+	jsonEncoder(w, r).Encode(s.poller.Clones())
+}
 
-	// Check gh CLI availability and authentication
-	ghAvailable := false
-	ghAuthenticated := false
-	if ghPath, err := exec.LookPath("gh"); err == nil {
-		ghAvailable = true
-		cmd := exec.Command(ghPath, "auth", "status")
-		if cmd.Run() == nil {
-			ghAuthenticated = true
-		}
+// handlePull handles POST /api/repos/:name/pull.
+func (s *Server) handlePull(w http.ResponseWriter, r *http.Request, repoName string) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		jsonEncoder(w, r).Encode(map[string]string{"error": "method not allowed"})
+		return
 	}
 
-	// Get poll times
+	// A clone in progress owns the directory until it finishes
+	if s.poller.IsCloning(repoName) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		jsonEncoder(w, r).Encode(map[string]string{"error": "clone in progress"})
+		return
+	}
+
+	// Repo must be cloned locally
+	cloned := scanner.FindClonedRepos([]string{repoName}, s.config().ScanPath)
+	repoPath, ok := cloned[repoName]
+	if !ok {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		jsonEncoder(w, r).Encode(map[string]string{"error": "repository not cloned"})
+		return
+	}
+
+	// Refuse to pull over uncommitted changes
+	_, dirty, _, err := scanner.GetGitState(repoPath)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		jsonEncoder(w, r).Encode(map[string]string{"error": "failed to read git state"})
+		return
+	}
+	if dirty {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		jsonEncoder(w, r).Encode(map[string]string{"error": "working tree has uncommitted changes"})
+		return
+	}
+
+	// Start pull asynchronously
+	statusChan := scanner.PullRepo(repoName, repoPath)
+
+	// Broadcast pull progress events in a goroutine
+	go func() {
+		for status := range statusChan {
+			s.hub.Broadcast("pull_progress", map[string]interface{}{
+				"repo":  status.Repo,
+				"state": status.State,
+				"error": status.Error,
+			})
+		}
+	}()
+
+	// Return 202 Accepted
+	w.WriteHeader(http.StatusAccepted)
+	jsonEncoder(w, r).Encode(map[string]string{"status": "pull started"})
+}
+
+// maxDiffBytes caps the diff text returned by GET /api/repos/:name/diff.
+const maxDiffBytes = 1 << 20
+
+// RepoDiff is the body of GET /api/repos/:name/diff. Diff is empty for a
+// clean tree; Truncated reports that it was cut at maxDiffBytes.
+type RepoDiff struct {
+	Repo      string `json:"repo"`
+	Diff      string `json:"diff"`
+	Truncated bool   `json:"truncated"`
+}
+
+// handleDiff handles GET /api/repos/:name/diff.
+func (s *Server) handleDiff(w http.ResponseWriter, r *http.Request, repoName string) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		jsonEncoder(w, r).Encode(map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	// Repo must be cloned locally
+	cloned := scanner.FindClonedRepos([]string{repoName}, s.config().ScanPath)
+	repoPath, ok := cloned[repoName]
+	if !ok {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		jsonEncoder(w, r).Encode(map[string]string{"error": "repository not cloned"})
+		return
+	}
+
+	diff, truncated, err := scanner.GetDiff(repoPath, maxDiffBytes)
+	if err != nil {
+		slog.Error("diff failed", "repo", repoName, "err", err)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		jsonEncoder(w, r).Encode(map[string]string{"error": "failed to read diff"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	jsonEncoder(w, r).Encode(RepoDiff{Repo: repoName, Diff: diff, Truncated: truncated})
+}
+
+// handleDeleteRepo handles DELETE /api/repos/:name.
+// The repo is removed from the cache; it reappears on the next poll only if
+// it still exists on disk or on GitHub.
+func (s *Server) handleDeleteRepo(w http.ResponseWriter, r *http.Request, repoName string) {
+	removed, err := s.poller.RemoveRepo(repoName)
+	if err != nil {
+		slog.Error("remove failed", "repo", repoName, "err", err)
+		http.Error(w, "Failed to remove repo", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if !removed {
+		w.WriteHeader(http.StatusNotFound)
+		jsonEncoder(w, r).Encode(map[string]string{"error": "repository not found"})
+		return
+	}
+	jsonEncoder(w, r).Encode(map[string]string{
+		"repo":   repoName,
+		"status": "removed",
+	})
+}
+
+// handleSnooze handles POST /api/repos/:name/snooze?days=N.
+// days=0 clears an existing snooze.
+func (s *Server) handleSnooze(w http.ResponseWriter, r *http.Request, repoName string) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		jsonEncoder(w, r).Encode(map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	days := 30
+	if v := r.URL.Query().Get("days"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 || n > 365 {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			jsonEncoder(w, r).Encode(map[string]string{"error": "days must be between 0 and 365"})
+			return
+		}
+		days = n
+	}
+
+	// Repo must be known
+	repos, err := cache.ReadRepos()
+	if err != nil {
+		http.Error(w, "Failed to read cache", http.StatusInternalServerError)
+		return
+	}
+	found := false
+	for _, repo := range repos {
+		if repo.Name == repoName {
+			found = true
+			break
+		}
+	}
+	if !found {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		jsonEncoder(w, r).Encode(map[string]string{"error": "repository not found"})
+		return
+	}
+
+	var until time.Time
+	if days > 0 {
+		until = time.Now().UTC().Add(time.Duration(days) * 24 * time.Hour)
+	}
+
+	if err := s.poller.Snooze(repoName, until); err != nil {
+		slog.Error("snooze failed", "repo", repoName, "err", err)
+		http.Error(w, "Failed to snooze repo", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	jsonEncoder(w, r).Encode(map[string]interface{}{
+		"repo":        repoName,
+		"snoozeUntil": until,
+	})
+}
+
+// handleHide handles POST /api/repos/:name/hide and /unhide, dismissing a
+// repo from the default repo list or bringing it back.
+func (s *Server) handleHide(w http.ResponseWriter, r *http.Request, repoName string, hidden bool) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		jsonEncoder(w, r).Encode(map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	// Repo must be known
+	repos, err := cache.ReadRepos()
+	if err != nil {
+		http.Error(w, "Failed to read cache", http.StatusInternalServerError)
+		return
+	}
+	if !slices.ContainsFunc(repos, func(repo model.Repo) bool { return repo.Name == repoName }) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		jsonEncoder(w, r).Encode(map[string]string{"error": "repository not found"})
+		return
+	}
+
+	if err := s.poller.SetHidden(repoName, hidden); err != nil {
+		slog.Error("set hidden failed", "repo", repoName, "hidden", hidden, "err", err)
+		http.Error(w, "Failed to update repo state", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	jsonEncoder(w, r).Encode(map[string]interface{}{
+		"repo":   repoName,
+		"hidden": hidden,
+	})
+}
+
+// RepoAnnotations is the body of PUT /api/repos/:name/state.
+type RepoAnnotations struct {
+	Tags []string `json:"tags"`
+	Note string   `json:"note"`
+}
+
+// maxNoteLength bounds a repo note, in bytes.
+const maxNoteLength = 4096
+
+// handleRepoState handles PUT /api/repos/:name/state, replacing the repo's
+// user tags and note. Tags are trimmed and deduplicated; blank tags are
+// dropped.
+func (s *Server) handleRepoState(w http.ResponseWriter, r *http.Request, repoName string) {
+	if r.Method != http.MethodPut {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		jsonEncoder(w, r).Encode(map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	var body RepoAnnotations
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxImportBytes)).Decode(&body); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		jsonEncoder(w, r).Encode(map[string]string{"error": "invalid request body: " + err.Error()})
+		return
+	}
+	if len(body.Note) > maxNoteLength {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		jsonEncoder(w, r).Encode(map[string]string{"error": fmt.Sprintf("note cannot exceed %d bytes", maxNoteLength)})
+		return
+	}
+	var tags []string
+	for _, tag := range body.Tags {
+		tag = strings.TrimSpace(tag)
+		if tag != "" && !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+
+	// Repo must be known
+	repos, err := cache.ReadRepos()
+	if err != nil {
+		http.Error(w, "Failed to read cache", http.StatusInternalServerError)
+		return
+	}
+	if !slices.ContainsFunc(repos, func(repo model.Repo) bool { return repo.Name == repoName }) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		jsonEncoder(w, r).Encode(map[string]string{"error": "repository not found"})
+		return
+	}
+
+	if err := s.poller.SetAnnotations(repoName, tags, body.Note); err != nil {
+		slog.Error("set state failed", "repo", repoName, "err", err)
+		http.Error(w, "Failed to update repo state", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	jsonEncoder(w, r).Encode(map[string]interface{}{
+		"repo": repoName,
+		"tags": tags,
+		"note": body.Note,
+	})
+}
+
+// RepoExport is the document exchanged by the single-repo export and
+// import endpoints: the repo's data plus its persisted user state.
+type RepoExport struct {
+	Repo  model.Repo            `json:"repo"`
+	State *cache.RepoStateEntry `json:"state,omitempty"`
+}
+
+// maxImportBytes bounds the request body accepted by the import endpoint.
+const maxImportBytes = 1 << 20
+
+// handleExport handles GET /api/repos/:name/export.
+func (s *Server) handleExport(w http.ResponseWriter, r *http.Request, repoName string) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		jsonEncoder(w, r).Encode(map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	repos, err := cache.ReadRepos()
+	if err != nil {
+		http.Error(w, "Failed to read cache", http.StatusInternalServerError)
+		return
+	}
+	for _, repo := range repos {
+		if repo.Name == repoName {
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", repoName+".json"))
+			jsonEncoder(w, r).Encode(RepoExport{
+				Repo:  repo,
+				State: s.poller.RepoState(repoName),
+			})
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusNotFound)
+	jsonEncoder(w, r).Encode(map[string]string{"error": "repository not found"})
+}
+
+// handleImport handles POST /api/repos/:name/import.
+// The body is a RepoExport whose repo name must match the path.
+func (s *Server) handleImport(w http.ResponseWriter, r *http.Request, repoName string) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		jsonEncoder(w, r).Encode(map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	var doc RepoExport
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxImportBytes)).Decode(&doc); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		jsonEncoder(w, r).Encode(map[string]string{"error": "invalid export document: " + err.Error()})
+		return
+	}
+	if doc.Repo.Name != repoName {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		jsonEncoder(w, r).Encode(map[string]string{"error": fmt.Sprintf("document is for repo %q, not %q", doc.Repo.Name, repoName)})
+		return
+	}
+
+	if err := s.poller.ImportRepo(doc.Repo, doc.State); err != nil {
+		slog.Error("import failed", "repo", repoName, "err", err)
+		http.Error(w, "Failed to import repo", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	jsonEncoder(w, r).Encode(map[string]string{"status": "imported", "repo": repoName})
+}
+
+// handleArchive handles POST /api/repos/:name/archive?confirm=true.
+// Archives the repo on GitHub. Rejected in read-only mode.
+func (s *Server) handleArchive(w http.ResponseWriter, r *http.Request, repoName string) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		jsonEncoder(w, r).Encode(map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	s.mu.RLock()
+	readOnly := s.cfg.ReadOnly
+	owner := s.cfg.GitHubOwner
+	s.mu.RUnlock()
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if readOnly {
+		w.WriteHeader(http.StatusForbidden)
+		jsonEncoder(w, r).Encode(map[string]string{"error": "archiving is disabled in read-only mode"})
+		return
+	}
+
+	// Archiving is hard to undo, so require an explicit confirmation
+	if r.URL.Query().Get("confirm") != "true" {
+		w.WriteHeader(http.StatusBadRequest)
+		jsonEncoder(w, r).Encode(map[string]string{"error": "confirm=true is required to archive"})
+		return
+	}
+
+	// Repo must be known and on GitHub
+	repos, err := cache.ReadRepos()
+	if err != nil {
+		http.Error(w, "Failed to read cache", http.StatusInternalServerError)
+		return
+	}
+	var target *model.Repo
+	for i := range repos {
+		if repos[i].Name == repoName {
+			target = &repos[i]
+			break
+		}
+	}
+	if target == nil || target.Visibility == "" {
+		w.WriteHeader(http.StatusNotFound)
+		jsonEncoder(w, r).Encode(map[string]string{"error": "repository not found"})
+		return
+	}
+	if target.Archived {
+		w.WriteHeader(http.StatusConflict)
+		jsonEncoder(w, r).Encode(map[string]string{"error": "repository is already archived"})
+		return
+	}
+
+	if err := archiveRepo(cmp.Or(target.Owner, owner), repoName); err != nil {
+		slog.Error("archive failed", "repo", repoName, "err", err)
+		w.WriteHeader(http.StatusBadGateway)
+		jsonEncoder(w, r).Encode(map[string]string{"error": "failed to archive repository"})
+		return
+	}
+
+	if err := s.poller.MarkArchived(repoName); err != nil {
+		slog.Error("mark archived failed", "repo", repoName, "err", err)
+	}
+
+	jsonEncoder(w, r).Encode(map[string]string{"status": "archived"})
+}
+
+// handleAcknowledgeAllActions handles POST /api/actions/acknowledge-all.
+// Every repo's current Actions status is acknowledged, muting notifications
+// until that status changes.
+func (s *Server) handleAcknowledgeAllActions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		jsonEncoder(w, r).Encode(map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	count, err := s.poller.AcknowledgeAllActions()
+	if err != nil {
+		slog.Error("acknowledge actions failed", "err", err)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		jsonEncoder(w, r).Encode(map[string]string{"error": "acknowledge failed"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	jsonEncoder(w, r).Encode(map[string]int{"acknowledged": count})
+}
+
+// handleRefresh handles POST /api/refresh?field=actions|prs|issues|files|releases.
+// Re-fetches a single field for all repos without a full poll.
+func (s *Server) handleRefresh(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		jsonEncoder(w, r).Encode(map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	field := r.URL.Query().Get("field")
+	if !poller.IsValidRefreshField(field) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		jsonEncoder(w, r).Encode(map[string]string{"error": "field must be one of actions, prs, issues, files, releases"})
+		return
+	}
+
+	count, err := s.poller.RefreshField(r.Context(), field)
+	if err != nil {
+		slog.Error("refresh failed", "field", field, "err", err)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		jsonEncoder(w, r).Encode(map[string]string{"error": "refresh failed"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	jsonEncoder(w, r).Encode(map[string]interface{}{
+		"field":     field,
+		"refreshed": count,
+	})
+}
+
+// handleConfig handles GET/PUT /api/config.
+func (s *Server) handleConfig(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.handleGetConfig(w, r)
+	case http.MethodPut:
+		s.handlePutConfig(w, r)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		jsonEncoder(w, r).Encode(map[string]string{"error": "method not allowed"})
+	}
+}
+
+// handleGetConfig handles GET /api/config.
+func (s *Server) handleGetConfig(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	jsonEncoder(w, r).Encode(redactConfig(s.config()))
+}
+
+// redactConfig returns a copy of cfg safe to send to clients: the auth
+// token is blanked, so it is left out of the JSON.
+func redactConfig(cfg *config.Config) config.Config {
+	redacted := *cfg
+	redacted.AuthToken = ""
+	return redacted
+}
+
+// handlePutConfig handles PUT /api/config.
+func (s *Server) handlePutConfig(w http.ResponseWriter, r *http.Request) {
+	var newCfg config.Config
+	if err := json.NewDecoder(r.Body).Decode(&newCfg); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		jsonEncoder(w, r).Encode(map[string]string{"error": "invalid JSON"})
+		return
+	}
+
+	// Clients never see the token (see redactConfig), so an empty one keeps
+	// the current token rather than turning auth off
+	if newCfg.AuthToken == "" {
+		newCfg.AuthToken = s.config().AuthToken
+	}
+
+	// Validate config
+	if err := config.Validate(&newCfg); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		jsonEncoder(w, r).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	// Save config
+	if err := config.Save(newCfg); err != nil {
+		http.Error(w, "Failed to save config", http.StatusInternalServerError)
+		return
+	}
+
+	if err := s.Reconfigure(newCfg); err != nil {
+		http.Error(w, "Failed to apply config", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	jsonEncoder(w, r).Encode(redactConfig(&newCfg))
+}
+
+// Reconfigure validates cfg and makes it the active config for the server
+// and poller, then notifies connected clients. This is the only path that
+// changes the running config; an invalid config is rejected and the current
+// config is kept.
+func (s *Server) Reconfigure(cfg config.Config) error {
+	if err := config.Validate(&cfg); err != nil {
+		return err
+	}
+
+	// The fixture dir comes from a flag, not the config file or dashboard
+	s.mu.Lock()
+	cfg.GitHubFixtureDir = s.cfg.GitHubFixtureDir
+	s.cfg = &cfg
+	s.mu.Unlock()
+
+	logging.Setup(&cfg)
+	s.poller.Reconfigure(&cfg)
+
+	// Notify connected clients that config changed
+	s.hub.Broadcast("config_updated", redactConfig(&cfg))
+	return nil
+}
+
+// reloadConfig applies a config reloaded from disk. Invalid configs are
+// logged and ignored, and unchanged configs (such as the file written by
+// PUT /api/config) are skipped.
+func (s *Server) reloadConfig(cfg config.Config) {
+	s.mu.RLock()
+	cfg.GitHubFixtureDir = s.cfg.GitHubFixtureDir
+	unchanged := reflect.DeepEqual(*s.cfg, cfg)
+	s.mu.RUnlock()
+	if unchanged {
+		return
+	}
+
+	if err := s.Reconfigure(cfg); err != nil {
+		slog.Warn("ignoring invalid config file", "err", err)
+		return
+	}
+	slog.Info("config reloaded from disk")
+}
+
+// WatchConfig reloads the config file whenever it changes on disk, until the
+// server shuts down. Port changes still require a restart.
+func (s *Server) WatchConfig() {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		config.Watch(s.shutdownCtx, config.DefaultWatchInterval, s.reloadConfig)
+	}()
+}
+
+// RepoStats holds aggregate counts over the cached repo list.
+// Repos without a detected language are counted under "unknown".
+type RepoStats struct {
+	Total        int            `json:"total"`
+	Cloned       int            `json:"cloned"`
+	NotCloned    int            `json:"notCloned"`
+	Dirty        int            `json:"dirty"`
+	WithOpenPRs  int            `json:"withOpenPRs"`
+	ByLifecycle  map[string]int `json:"byLifecycle"`
+	ByLanguage   map[string]int `json:"byLanguage"`
+	ByVisibility map[string]int `json:"byVisibility"`
+}
+
+// computeStats counts repos into every RepoStats bucket in a single pass.
+func computeStats(repos []model.Repo) RepoStats {
+	stats := RepoStats{
+		Total:        len(repos),
+		ByLifecycle:  make(map[string]int),
+		ByLanguage:   make(map[string]int),
+		ByVisibility: make(map[string]int),
+	}
+	for _, repo := range repos {
+		if repo.Cloned {
+			stats.Cloned++
+		} else {
+			stats.NotCloned++
+		}
+		if repo.Dirty {
+			stats.Dirty++
+		}
+		if repo.OpenPRs > 0 {
+			stats.WithOpenPRs++
+		}
+		stats.ByLifecycle[string(repo.Lifecycle)]++
+		language := repo.Language
+		if language == "" {
+			language = "unknown"
+		}
+		stats.ByLanguage[language]++
+		stats.ByVisibility[string(repo.Visibility)]++
+	}
+	return stats
+}
+
+// handleStats handles GET /api/stats.
+func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		jsonEncoder(w, r).Encode(map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	repos, err := cache.ReadRepos()
+	if err != nil {
+		http.Error(w, "Failed to read cache", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	jsonEncoder(w, r).Encode(computeStats(repos))
+}
+
+// handleHealth handles GET /api/health.
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		jsonEncoder(w, r).Encode(map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	// Get repo count
+	repos, _ := cache.ReadRepos()
+
+	// Check gh CLI availability, authentication, and token scopes
+	auth, err := s.checkGHAuthCached()
+	ghAvailable := err == nil || !scanner.IsGHNotFound(err)
+	ghAuthenticated := err == nil
+	var ghLogin, ghHost string
+	missingScopes := []string{}
+	warnings := []string{}
+	if ghAuthenticated {
+		ghLogin, ghHost = auth.Login, auth.Host
+		if len(auth.MissingScopes) > 0 {
+			missingScopes = auth.MissingScopes
+			warnings = append(warnings, scanner.GHScopeWarning(auth.MissingScopes))
+		}
+	}
+
+	// Get poll times
 	lastLocal := s.poller.GetLastLocalPoll()
 	lastGitHub := s.poller.GetLastGitHubPoll()
 
+	s.mu.RLock()
+	instanceName := s.cfg.InstanceName
+	s.mu.RUnlock()
+
 	health := map[string]interface{}{
-		"Uptime":          time.Since(s.startTime).String(),
-		"LastLocalPoll":   lastLocal.Format(time.RFC3339),
-		"LastGitHubPoll":  lastGitHub.Format(time.RFC3339),
-		"TotalRepos":      len(repos),
-		"GhAvailable":     ghAvailable,
-		"GhAuthenticated": ghAuthenticated,
+		"InstanceName":        instanceName,
+		"Uptime":              time.Since(s.startTime).String(),
+		"LastLocalPoll":       lastLocal.Format(time.RFC3339),
+		"LastGitHubPoll":      lastGitHub.Format(time.RFC3339),
+		"LastLocalPollError":  s.poller.GetLastLocalPollError(),
+		"LastGitHubPollError": s.poller.GetLastGitHubPollError(),
+		"TotalRepos":          len(repos),
+		"GhAvailable":         ghAvailable,
+		"GhAuthenticated":     ghAuthenticated,
+		"GhLogin":             ghLogin,
+		"GhHost":              ghHost,
+		"GhMissingScopes":     missingScopes,
+		"GitHubHealthy":       s.poller.GitHubHealthy(),
+		"GitHubFailures":      s.poller.GitHubFailures(),
+		"SSEClients":          s.hub.ClientCount(),
+		"SSEMaxClients":       s.hub.MaxClients(),
+		"Warnings":            warnings,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	jsonEncoder(w, r).Encode(health)
+}
+
+// handleVersion handles GET /api/version.
+func (s *Server) handleVersion(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		jsonEncoder(w, r).Encode(map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	s.mu.RLock()
+	instanceName := s.cfg.InstanceName
+	s.mu.RUnlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	jsonEncoder(w, r).Encode(map[string]string{
+		"version":      Version,
+		"instanceName": instanceName,
+	})
+}
+
+// checkGHAuthCached returns the result of checkGHAuth, reusing the last
+// one for ghAuthCacheTTL.
+func (s *Server) checkGHAuthCached() (*scanner.GHAuthStatus, error) {
+	s.ghAuthMu.Lock()
+	defer s.ghAuthMu.Unlock()
+
+	if s.ghAuthAt.IsZero() || time.Since(s.ghAuthAt) >= ghAuthCacheTTL {
+		s.ghAuth, s.ghAuthErr = checkGHAuth()
+		s.ghAuthAt = time.Now()
+	}
+	return s.ghAuth, s.ghAuthErr
+}
+
+// handleOwners handles GET /api/owners.
+// Returns the profile of the configured owner, followed by each org that
+// cached repos were listed from, so the UI never calls GitHub directly.
+func (s *Server) handleOwners(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		jsonEncoder(w, r).Encode(map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	s.mu.RLock()
+	owners := []string{s.cfg.GitHubOwner}
+	s.mu.RUnlock()
+
+	// With includeAllOrgs, org repos carry their org in Owner
+	if repos, err := cache.ReadRepos(); err == nil {
+		var orgs []string
+		for _, repo := range repos {
+			if repo.Owner != "" && !strings.EqualFold(repo.Owner, owners[0]) {
+				orgs = append(orgs, repo.Owner)
+			}
+		}
+		slices.Sort(orgs)
+		owners = append(owners, slices.Compact(orgs)...)
+	}
+
+	result := make([]scanner.OwnerInfo, 0, len(owners))
+	for _, owner := range owners {
+		if owner == "" {
+			continue
+		}
+		info, err := s.getOwnerInfo(owner)
+		if err != nil {
+			slog.Warn("fetching owner info failed", "owner", owner, "err", err)
+			// Unknown or unreachable owners still appear, just without a profile
+			info = &scanner.OwnerInfo{Login: owner}
+		}
+		result = append(result, *info)
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(health)
+	jsonEncoder(w, r).Encode(result)
+}
+
+// getOwnerInfo returns the cached owner profile, fetching it if missing or expired.
+// Fetch errors are not cached so a transient gh failure is retried next time.
+func (s *Server) getOwnerInfo(owner string) (*scanner.OwnerInfo, error) {
+	s.ownersMu.Lock()
+	defer s.ownersMu.Unlock()
+
+	if entry, ok := s.owners[owner]; ok && time.Since(entry.fetchedAt) < ownerCacheTTL {
+		return entry.info, nil
+	}
+
+	info, err := fetchOwnerInfo(owner)
+	if err != nil {
+		return nil, err
+	}
+
+	s.owners[owner] = ownerCacheEntry{info: info, fetchedAt: time.Now()}
+	return info, nil
 }
 
 // handleEvents handles GET /api/events for SSE connections.
@@ -426,44 +1752,104 @@ func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
 
 	// Create SSE handler
 	handler := sse.NewHandler(s.hub, clientID)
-
-	// Send current repo list immediately
-	repos, err := cache.ReadRepos()
-	if err == nil && len(repos) > 0 {
-		// Send directly to the client
-		handler.GetClient().Chan <- sse.Event{
-			Type: "repos_updated",
-			Data: repos,
+	s.mu.RLock()
+	handler.SetInstanceName(s.cfg.InstanceName)
+	s.mu.RUnlock()
+
+	// ?events=new_release,actions_changed limits the client to those types
+	if v := r.URL.Query().Get("events"); v != "" {
+		var types []string
+		for _, t := range strings.Split(v, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				types = append(types, t)
+			}
 		}
+		handler.SetEventTypes(types)
 	}
 
+	// Send current repo list once the client is registered. It bypasses the
+	// client channel, so a briefly full buffer can't leave the client without data.
+	handler.SetSnapshot(func() []sse.Event {
+		repos, err := cache.ReadRepos()
+		if err != nil {
+			return nil
+		}
+		return snapshotEvents(repos, snapshotPageSize)
+	})
+
 	// Serve SSE connection
 	handler.ServeHTTP(w, r)
 }
 
+// snapshotPageSize is the number of repos per repos_snapshot frame.
+const snapshotPageSize = 100
+
+// snapshotEvents splits the repo list into repos_snapshot events so clients
+// can render large lists progressively. Pages are numbered from 1 and total
+// is the number of pages. Returns nil for an empty list.
+func snapshotEvents(repos []model.Repo, pageSize int) []sse.Event {
+	total := (len(repos) + pageSize - 1) / pageSize
+	events := make([]sse.Event, 0, total)
+	for page := 1; page <= total; page++ {
+		start := (page - 1) * pageSize
+		end := min(start+pageSize, len(repos))
+		events = append(events, sse.Event{
+			Type: "repos_snapshot",
+			Data: map[string]interface{}{
+				"page":  page,
+				"total": total,
+				"repos": repos[start:end],
+			},
+		})
+	}
+	return events
+}
+
 // filterRepos applies query parameter filters to the repo list.
 func (s *Server) filterRepos(repos []model.Repo, query url.Values) []model.Repo {
 	var result []model.Repo
 
 	// Filter by lifecycle
 	if lifecycle := query.Get("lifecycle"); lifecycle != "" {
-		lifecycles := strings.Split(lifecycle, ",")
+		lifecycles := splitFilterValues(lifecycle)
+		for _, repo := range repos {
+			if slices.Contains(lifecycles, string(repo.Lifecycle)) {
+				result = append(result, repo)
+			}
+		}
+		repos = result
+		result = nil
+	}
+
+	// Archived repos are hidden unless requested with includeArchived=true
+	// or by filtering on the archived lifecycle
+	if query.Get("includeArchived") != "true" && !strings.Contains(query.Get("lifecycle"), string(model.LifecycleArchived)) {
 		for _, repo := range repos {
-			for _, lc := range lifecycles {
-				if string(repo.Lifecycle) == strings.TrimSpace(lc) {
-					result = append(result, repo)
-					break
-				}
+			if !repo.Archived {
+				result = append(result, repo)
+			}
+		}
+		repos = result
+		result = nil
+	}
+
+	// Hidden repos, whether dismissed or auto-hidden, are left out unless
+	// requested with includeHidden=true
+	if query.Get("includeHidden") != "true" {
+		for _, repo := range repos {
+			if !repo.Hidden {
+				result = append(result, repo)
 			}
 		}
 		repos = result
 		result = nil
 	}
 
-	// Filter by visibility
+	// Filter by visibility; a comma list matches any of the values
 	if visibility := query.Get("visibility"); visibility != "" {
+		visibilities := splitFilterValues(visibility)
 		for _, repo := range repos {
-			if string(repo.Visibility) == visibility {
+			if slices.Contains(visibilities, string(repo.Visibility)) {
 				result = append(result, repo)
 			}
 		}
@@ -471,11 +1857,174 @@ func (s *Server) filterRepos(repos []model.Repo, query url.Values) []model.Repo
 		result = nil
 	}
 
-	// Filter by cloned status
+	// Filter by cloned status; "true,false" matches both
 	if cloned := query.Get("cloned"); cloned != "" {
-		clonedBool := cloned == "true"
+		clonedValues := splitFilterValues(cloned)
+		for _, repo := range repos {
+			if slices.Contains(clonedValues, strconv.FormatBool(repo.Cloned)) {
+				result = append(result, repo)
+			}
+		}
+		repos = result
+		result = nil
+	}
+
+	// Filter by whether the repo has open issues
+	if hasIssues := query.Get("hasIssues"); hasIssues != "" {
+		wantIssues := hasIssues == "true"
+		for _, repo := range repos {
+			if (repo.OpenIssues > 0) == wantIssues {
+				result = append(result, repo)
+			}
+		}
+		repos = result
+		result = nil
+	}
+
+	// Filter by whether the repo has a wiki
+	if hasWiki := query.Get("hasWiki"); hasWiki != "" {
+		wantWiki := hasWiki == "true"
+		for _, repo := range repos {
+			if repo.HasWiki == wantWiki {
+				result = append(result, repo)
+			}
+		}
+		repos = result
+		result = nil
+	}
+
+	// Filter by whether the repo was created recently
+	if isNew := query.Get("isNew"); isNew != "" {
+		wantNew := isNew == "true"
+		for _, repo := range repos {
+			if repo.IsNew == wantNew {
+				result = append(result, repo)
+			}
+		}
+		repos = result
+		result = nil
+	}
+
+	// Filter by whether the repo has Discussions
+	if hasDiscussions := query.Get("hasDiscussions"); hasDiscussions != "" {
+		wantDiscussions := hasDiscussions == "true"
+		for _, repo := range repos {
+			if repo.HasDiscussions == wantDiscussions {
+				result = append(result, repo)
+			}
+		}
+		repos = result
+		result = nil
+	}
+
+	// Filter by maximum completeness score; non-numeric values are ignored
+	if maxCompleteness, err := strconv.Atoi(query.Get("maxCompleteness")); err == nil {
+		for _, repo := range repos {
+			if repo.CompletenessScore() <= maxCompleteness {
+				result = append(result, repo)
+			}
+		}
+		repos = result
+		result = nil
+	}
+
+	// Filter by minimum open issue count; non-numeric values are ignored
+	if minIssues, err := strconv.Atoi(query.Get("minIssues")); err == nil {
+		for _, repo := range repos {
+			if repo.OpenIssues >= minIssues {
+				result = append(result, repo)
+			}
+		}
+		repos = result
+		result = nil
+	}
+
+	// Filter by .project.json group
+	if group := query.Get("projectGroup"); group != "" {
+		for _, repo := range repos {
+			if repo.ProjectGroup == group {
+				result = append(result, repo)
+			}
+		}
+		repos = result
+		result = nil
+	}
+
+	// Filter by .project.json status
+	if status := query.Get("projectStatus"); status != "" {
+		for _, repo := range repos {
+			if repo.ProjectStatus == status {
+				result = append(result, repo)
+			}
+		}
+		repos = result
+		result = nil
+	}
+
+	// Full-text search over name, full name, description, and topics
+	if q := strings.TrimSpace(query.Get("q")); q != "" {
+		needle := strings.ToLower(q)
+		for _, repo := range repos {
+			if repoMatchesSearch(repo, needle) {
+				result = append(result, repo)
+			}
+		}
+		repos = result
+		result = nil
+	}
+
+	// Filter by uncommitted changes in the local clone
+	if dirty := query.Get("dirty"); dirty != "" {
+		wantDirty := dirty == "true"
+		for _, repo := range repos {
+			if repo.Dirty == wantDirty {
+				result = append(result, repo)
+			}
+		}
+		repos = result
+		result = nil
+	}
+
+	// Filter by whether the repo has a README
+	if hasReadme := query.Get("hasReadme"); hasReadme != "" {
+		wantReadme := hasReadme == "true"
+		for _, repo := range repos {
+			if repo.Completeness.HasReadme == wantReadme {
+				result = append(result, repo)
+			}
+		}
+		repos = result
+		result = nil
+	}
+
+	// Filter by whether the repo has a license
+	if hasLicense := query.Get("hasLicense"); hasLicense != "" {
+		wantLicense := hasLicense == "true"
+		for _, repo := range repos {
+			if repo.Completeness.HasLicense == wantLicense {
+				result = append(result, repo)
+			}
+		}
+		repos = result
+		result = nil
+	}
+
+	// Filter by topic; matches any repo whose topics include it exactly
+	if topic := query.Get("topic"); topic != "" {
+		for _, repo := range repos {
+			if slices.Contains(repo.Topics, topic) {
+				result = append(result, repo)
+			}
+		}
+		repos = result
+		result = nil
+	}
+
+	// Filter by user tag; a comma list matches any of the values
+	if tag := query.Get("tag"); tag != "" {
+		tags := splitFilterValues(tag)
 		for _, repo := range repos {
-			if repo.Cloned == clonedBool {
+			if slices.ContainsFunc(repo.Tags, func(t string) bool { return slices.Contains(tags, t) }) {
 				result = append(result, repo)
 			}
 		}
@@ -483,23 +2032,51 @@ func (s *Server) filterRepos(repos []model.Repo, query url.Values) []model.Repo
 		result = nil
 	}
 
-	// Filter by language
+	// Filter by language; a comma list matches any of the values
 	if language := query.Get("language"); language != "" {
+		languages := splitFilterValues(language)
 		for _, repo := range repos {
-			if repo.Language == language {
+			if slices.Contains(languages, repo.Language) {
 				result = append(result, repo)
 			}
 		}
 		repos = result
 	}
 
-	if result == nil {
-		return repos
+	return repos
+}
+
+// splitFilterValues splits a comma-separated filter value into its
+// trimmed parts. Values within one filter are ORed together.
+func splitFilterValues(v string) []string {
+	values := strings.Split(v, ",")
+	for i := range values {
+		values[i] = strings.TrimSpace(values[i])
+	}
+	return values
+}
+
+// repoMatchesSearch reports whether any searchable field contains needle.
+// needle must already be lowercased.
+func repoMatchesSearch(repo model.Repo, needle string) bool {
+	if strings.Contains(strings.ToLower(repo.Name), needle) ||
+		strings.Contains(strings.ToLower(repo.FullName), needle) ||
+		strings.Contains(strings.ToLower(repo.Description), needle) {
+		return true
+	}
+	for _, topic := range repo.Topics {
+		if strings.Contains(strings.ToLower(topic), needle) {
+			return true
+		}
 	}
-	return result
+	return false
 }
 
 // sortRepos applies sorting to the repo list.
+// sort may list several fields (e.g. "lifecycle,lastUpdate"), compared in
+// turn and all in the requested order. Remaining ties are broken by the
+// configured tiebreaker (name by default) and finally by name, so the order
+// is stable between polls even though merge builds the list from a map.
 func (s *Server) sortRepos(repos []model.Repo, query url.Values) []model.Repo {
 	// Get sort field and order
 	sortField := query.Get("sort")
@@ -511,39 +2088,98 @@ func (s *Server) sortRepos(repos []model.Repo, query url.Values) []model.Repo {
 		order = "asc"
 	}
 
-	// Sort using stdlib sort.Slice
+	s.mu.RLock()
+	tiebreaker := s.cfg.SortTiebreaker
+	priority := s.cfg.LifecyclePriority
+	s.mu.RUnlock()
+	if tiebreaker == "" {
+		tiebreaker = config.SortTiebreakerName
+	}
+	if len(priority) == 0 {
+		priority = config.DefaultLifecyclePriority()
+	}
+
+	// lifecyclePriority needs the configured order, so it is compared here
+	// rather than in compareRepos
+	rank := func(repo model.Repo) int {
+		if i := slices.Index(priority, string(repo.Lifecycle)); i >= 0 {
+			return i
+		}
+		return len(priority)
+	}
+	compare := func(a, b model.Repo, field string) int {
+		if field == "lifecyclePriority" {
+			return rank(a) - rank(b)
+		}
+		return compareRepos(a, b, field)
+	}
+
+	// Sort using stdlib sort.SliceStable
 	sorted := make([]model.Repo, len(repos))
 	copy(sorted, repos)
 
+	fields := splitFilterValues(sortField)
 	desc := order == "desc"
-	switch sortField {
-	case "name":
-		sort.Slice(sorted, func(i, j int) bool {
-			if desc {
-				return sorted[i].Name > sorted[j].Name
-			}
-			return sorted[i].Name < sorted[j].Name
-		})
-	case "lastUpdate":
-		sort.Slice(sorted, func(i, j int) bool {
+	sort.SliceStable(sorted, func(i, j int) bool {
+		for _, field := range fields {
+			c := compare(sorted[i], sorted[j], field)
 			if desc {
-				return sorted[i].GitHubLastPush.After(sorted[j].GitHubLastPush)
+				c = -c
 			}
-			return sorted[i].GitHubLastPush.Before(sorted[j].GitHubLastPush)
-		})
-	case "lifecycle":
-		sort.Slice(sorted, func(i, j int) bool {
-			if desc {
-				return sorted[i].Lifecycle > sorted[j].Lifecycle
+			if c != 0 {
+				return c < 0
 			}
-			return sorted[i].Lifecycle < sorted[j].Lifecycle
-		})
-	}
+		}
+		// Tiebreakers always apply in their natural order
+		if c := compareRepos(sorted[i], sorted[j], tiebreaker); c != 0 {
+			return c < 0
+		}
+		return compareRepos(sorted[i], sorted[j], "name") < 0
+	})
 	repos = sorted
 
 	return repos
 }
 
+// compareRepos compares two repos by the given field in ascending order.
+// Returns a negative number if a sorts before b, positive if after, 0 if equal.
+// Unknown fields compare as equal.
+func compareRepos(a, b model.Repo, field string) int {
+	switch field {
+	case "name":
+		return strings.Compare(a.Name, b.Name)
+	case "lastUpdate":
+		return a.GitHubLastPush.Compare(b.GitHubLastPush)
+	case "createdAt":
+		return a.CreatedAt.Compare(b.CreatedAt)
+	case "lifecycle":
+		return strings.Compare(string(a.Lifecycle), string(b.Lifecycle))
+	case "issues", "openIssues":
+		return a.OpenIssues - b.OpenIssues
+	case "openPRs":
+		return a.OpenPRs - b.OpenPRs
+	case "completeness":
+		return a.CompletenessScore() - b.CompletenessScore()
+	case "dirty":
+		// Clean sorts before dirty, so desc puts dirty repos first
+		return boolToInt(a.Dirty) - boolToInt(b.Dirty)
+	case "stars":
+		return a.Stars - b.Stars
+	case "forks":
+		return a.Forks - b.Forks
+	default:
+		return 0
+	}
+}
+
+// boolToInt returns 1 for true and 0 for false.
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
 // generateClientID generates a unique client ID for SSE connections.
 func generateClientID() string {
 	return fmt.Sprintf("%d", time.Now().UnixNano())
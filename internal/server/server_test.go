@@ -1,20 +1,31 @@
 package server
 
 import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/json"
 	"fmt"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/alexcatdad/catscan/internal/cache"
 	"github.com/alexcatdad/catscan/internal/config"
 	"github.com/alexcatdad/catscan/internal/model"
+	"github.com/alexcatdad/catscan/internal/poller"
+	"github.com/alexcatdad/catscan/internal/scanner"
 	"github.com/alexcatdad/catscan/internal/sse"
 )
 
@@ -128,6 +139,10 @@ func TestReposListFiltering(t *testing.T) {
 			Cloned:     true,
 			Lifecycle:  model.LifecycleOngoing,
 			Language:   "Go",
+			HasWiki:    true,
+
+			ProjectGroup:  "tools",
+			ProjectStatus: "active",
 		},
 		{
 			Name:       "private-repo",
@@ -135,6 +150,7 @@ func TestReposListFiltering(t *testing.T) {
 			Cloned:     false,
 			Lifecycle:  model.LifecycleStale,
 			Language:   "TypeScript",
+			OpenIssues: 4,
 		},
 		{
 			Name:       "another-public",
@@ -142,6 +158,16 @@ func TestReposListFiltering(t *testing.T) {
 			Cloned:     false,
 			Lifecycle:  model.LifecycleMaintenance,
 			Language:   "Go",
+			HasWiki:    true,
+
+			HasDiscussions: true,
+		},
+		{
+			Name:       "archived-repo",
+			Visibility: model.VisibilityPublic,
+			Lifecycle:  model.LifecycleArchived,
+			Language:   "Go",
+			Archived:   true,
 		},
 	}
 
@@ -211,6 +237,89 @@ func TestReposListFiltering(t *testing.T) {
 		}
 	})
 
+	// Test archived repos are hidden by default
+	t.Run("archived hidden by default", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/repos", nil)
+		filtered := s.filterRepos(testRepos, req.URL.Query())
+		if len(filtered) != 3 {
+			t.Errorf("len(filtered) = %d, want 3", len(filtered))
+		}
+
+		req = httptest.NewRequest(http.MethodGet, "/api/repos?includeArchived=true", nil)
+		filtered = s.filterRepos(testRepos, req.URL.Query())
+		if len(filtered) != 4 {
+			t.Errorf("includeArchived: len(filtered) = %d, want 4", len(filtered))
+		}
+
+		req = httptest.NewRequest(http.MethodGet, "/api/repos?lifecycle=archived", nil)
+		filtered = s.filterRepos(testRepos, req.URL.Query())
+		if len(filtered) != 1 || filtered[0].Name != "archived-repo" {
+			t.Errorf("lifecycle=archived: filtered = %v, want [archived-repo]", filtered)
+		}
+	})
+
+	// Test open issues filter
+	t.Run("filter by hasIssues", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/repos?hasIssues=true", nil)
+		filtered := s.filterRepos(testRepos, req.URL.Query())
+
+		if len(filtered) != 1 {
+			t.Fatalf("len(filtered) = %d, want 1", len(filtered))
+		}
+		if filtered[0].Name != "private-repo" {
+			t.Errorf("filtered[0].Name = %s, want private-repo", filtered[0].Name)
+		}
+	})
+
+	// Test wiki and discussions filters
+	t.Run("filter by hasWiki and hasDiscussions", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/repos?hasWiki=true", nil)
+		filtered := s.filterRepos(testRepos, req.URL.Query())
+		if len(filtered) != 2 {
+			t.Errorf("hasWiki=true: len(filtered) = %d, want 2", len(filtered))
+		}
+
+		req = httptest.NewRequest(http.MethodGet, "/api/repos?hasWiki=false", nil)
+		filtered = s.filterRepos(testRepos, req.URL.Query())
+		if len(filtered) != 1 || filtered[0].Name != "private-repo" {
+			t.Errorf("hasWiki=false: filtered = %v, want [private-repo]", filtered)
+		}
+
+		req = httptest.NewRequest(http.MethodGet, "/api/repos?hasWiki=true&hasDiscussions=true", nil)
+		filtered = s.filterRepos(testRepos, req.URL.Query())
+		if len(filtered) != 1 || filtered[0].Name != "another-public" {
+			t.Errorf("hasWiki&hasDiscussions: filtered = %v, want [another-public]", filtered)
+		}
+	})
+
+	// Test minimum open issues filter
+	t.Run("filter by minIssues", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/repos?minIssues=4", nil)
+		filtered := s.filterRepos(testRepos, req.URL.Query())
+		if len(filtered) != 1 || filtered[0].Name != "private-repo" {
+			t.Errorf("minIssues=4: filtered = %v, want [private-repo]", filtered)
+		}
+
+		req = httptest.NewRequest(http.MethodGet, "/api/repos?minIssues=5", nil)
+		filtered = s.filterRepos(testRepos, req.URL.Query())
+		if len(filtered) != 0 {
+			t.Errorf("minIssues=5: len(filtered) = %d, want 0", len(filtered))
+		}
+	})
+
+	// Test project group and status filters
+	t.Run("filter by project group and status", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/repos?projectGroup=tools&projectStatus=active", nil)
+		filtered := s.filterRepos(testRepos, req.URL.Query())
+
+		if len(filtered) != 1 {
+			t.Fatalf("len(filtered) = %d, want 1", len(filtered))
+		}
+		if filtered[0].Name != "public-repo" {
+			t.Errorf("filtered[0].Name = %s, want public-repo", filtered[0].Name)
+		}
+	})
+
 	// Test multiple lifecycle filter
 	t.Run("filter by multiple lifecycles", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodGet, "/api/repos?lifecycle=ongoing,maintenance", nil)
@@ -222,6 +331,333 @@ func TestReposListFiltering(t *testing.T) {
 	})
 }
 
+// TestReposListSearch tests the q full-text search parameter.
+func TestReposListSearch(t *testing.T) {
+	testRepos := []model.Repo{
+		{
+			Name:        "catscan",
+			Description: "Local dashboard for GitHub repos",
+			Topics:      []string{"dashboard", "golang"},
+			Visibility:  model.VisibilityPublic,
+		},
+		{
+			Name:        "dotfiles",
+			Description: "Shell configuration",
+			Topics:      []string{"zsh"},
+			Visibility:  model.VisibilityPrivate,
+		},
+	}
+
+	cfg := &config.Config{
+		ScanPath:              "/tmp/test",
+		Port:                  8080,
+		LocalIntervalSeconds:  30,
+		GitHubIntervalSeconds: 300,
+		StaleDays:             30,
+		AbandonedDays:         90,
+	}
+	s, _ := NewServer(cfg)
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{name: "description substring", query: "q=shell", want: []string{"dotfiles"}},
+		{name: "topic", query: "q=golang", want: []string{"catscan"}},
+		{name: "case-insensitive", query: "q=DASHBOARD", want: []string{"catscan"}},
+		{name: "combined with visibility", query: "q=s&visibility=private", want: []string{"dotfiles"}},
+		{name: "no match", query: "q=nothing-matches", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/repos?"+tt.query, nil)
+			filtered := s.filterRepos(testRepos, req.URL.Query())
+
+			if len(filtered) != len(tt.want) {
+				t.Fatalf("len(filtered) = %d, want %d", len(filtered), len(tt.want))
+			}
+			for i, name := range tt.want {
+				if filtered[i].Name != name {
+					t.Errorf("filtered[%d].Name = %s, want %s", i, filtered[i].Name, name)
+				}
+			}
+		})
+	}
+}
+
+// TestReposListFieldProjection tests that ?fields= returns only the
+// requested repo fields and rejects unknown ones.
+func TestReposListFieldProjection(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalCachePath := cache.GetCachePath()
+	defer cache.SetCachePath(originalCachePath)
+	cache.SetCachePath(filepath.Join(tmpDir, "cache.json"))
+
+	testRepos := []model.Repo{{
+		Name:          "catscan",
+		Description:   "Local dashboard",
+		Topics:        []string{"dashboard"},
+		Lifecycle:     model.LifecycleOngoing,
+		Cloned:        true,
+		Dirty:         true,
+		OpenPRs:       2,
+		LatestRelease: &model.ReleaseInfo{TagName: "v1.0.0"},
+		Completeness:  model.CompletenessInfo{HasReadme: true},
+	}}
+	if err := cache.WriteRepos(testRepos); err != nil {
+		t.Fatalf("WriteRepos failed: %v", err)
+	}
+
+	cfg := &config.Config{
+		ScanPath:              tmpDir,
+		Port:                  8080,
+		LocalIntervalSeconds:  30,
+		GitHubIntervalSeconds: 300,
+		StaleDays:             30,
+		AbandonedDays:         90,
+	}
+	s, _ := NewServer(cfg)
+
+	getKeys := func(query string) []string {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/api/repos?"+query, nil)
+		w := httptest.NewRecorder()
+		s.handleReposList(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d, want 200", query, w.Code)
+		}
+		var repos []map[string]json.RawMessage
+		if err := json.NewDecoder(w.Body).Decode(&repos); err != nil {
+			t.Fatalf("%s: failed to decode response: %v", query, err)
+		}
+		if len(repos) != 1 {
+			t.Fatalf("%s: len(repos) = %d, want 1", query, len(repos))
+		}
+		var keys []string
+		for key := range repos[0] {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		return keys
+	}
+
+	if got := strings.Join(getKeys("fields=compact"), ","); got != "Cloned,Dirty,GitHubLastPush,Lifecycle,Name,OpenPRs" {
+		t.Errorf("fields=compact keys = %s", got)
+	}
+	if got := strings.Join(getKeys("fields=name,lifecycle"), ","); got != "Lifecycle,Name" {
+		t.Errorf("fields=name,lifecycle keys = %s, want Lifecycle,Name", got)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/repos?fields=name,bogus", nil)
+	w := httptest.NewRecorder()
+	s.handleReposList(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("unknown field: status = %d, want 400", w.Code)
+	}
+}
+
+// TestReposListTriageFilters tests the dirty, completeness, and topic filters.
+func TestReposListTriageFilters(t *testing.T) {
+	testRepos := []model.Repo{
+		{
+			Name:         "catscan",
+			Dirty:        true,
+			Topics:       []string{"dashboard", "golang"},
+			Completeness: model.CompletenessInfo{HasReadme: true, HasLicense: true},
+		},
+		{
+			Name:         "dotfiles",
+			Topics:       []string{"zsh"},
+			Completeness: model.CompletenessInfo{HasReadme: true},
+		},
+		{
+			Name:   "scratch",
+			Dirty:  true,
+			Topics: []string{"golang"},
+		},
+	}
+
+	cfg := &config.Config{
+		ScanPath:              "/tmp/test",
+		Port:                  8080,
+		LocalIntervalSeconds:  30,
+		GitHubIntervalSeconds: 300,
+		StaleDays:             30,
+		AbandonedDays:         90,
+	}
+	s, _ := NewServer(cfg)
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{name: "dirty", query: "dirty=true", want: []string{"catscan", "scratch"}},
+		{name: "clean", query: "dirty=false", want: []string{"dotfiles"}},
+		{name: "missing readme", query: "hasReadme=false", want: []string{"scratch"}},
+		{name: "missing license", query: "hasLicense=false", want: []string{"dotfiles", "scratch"}},
+		{name: "topic", query: "topic=golang", want: []string{"catscan", "scratch"}},
+		{name: "topic is exact", query: "topic=go", want: nil},
+		{name: "combined", query: "dirty=true&hasLicense=false&topic=golang", want: []string{"scratch"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/repos?"+tt.query, nil)
+			filtered := s.filterRepos(testRepos, req.URL.Query())
+
+			if len(filtered) != len(tt.want) {
+				t.Fatalf("len(filtered) = %d, want %d", len(filtered), len(tt.want))
+			}
+			for i, name := range tt.want {
+				if filtered[i].Name != name {
+					t.Errorf("filtered[%d].Name = %s, want %s", i, filtered[i].Name, name)
+				}
+			}
+		})
+	}
+}
+
+// TestReposListCompleteness tests the maxCompleteness filter and completeness sort.
+func TestReposListCompleteness(t *testing.T) {
+	testRepos := []model.Repo{
+		{Name: "documented", Completeness: model.CompletenessInfo{
+			HasDescription: true, HasReadme: true, HasLicense: true,
+			HasTopics: true, HasProjectJson: true, HasClaudeMd: true,
+		}},
+		{Name: "bare"},
+		{Name: "partial", Completeness: model.CompletenessInfo{HasDescription: true, HasReadme: true, HasLicense: true}},
+	}
+
+	cfg := &config.Config{
+		ScanPath:              "/tmp/test",
+		Port:                  8080,
+		LocalIntervalSeconds:  30,
+		GitHubIntervalSeconds: 300,
+		StaleDays:             30,
+		AbandonedDays:         90,
+	}
+	s, _ := NewServer(cfg)
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{query: "maxCompleteness=50&sort=name", want: []string{"bare", "partial"}},
+		{query: "maxCompleteness=0", want: []string{"bare"}},
+		{query: "maxCompleteness=100&sort=completeness", want: []string{"bare", "partial", "documented"}},
+		{query: "sort=completeness&order=desc", want: []string{"documented", "partial", "bare"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/repos?"+tt.query, nil)
+			got := s.sortRepos(s.filterRepos(testRepos, req.URL.Query()), req.URL.Query())
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("len(got) = %d, want %d", len(got), len(tt.want))
+			}
+			for i, name := range tt.want {
+				if got[i].Name != name {
+					t.Errorf("got[%d].Name = %s, want %s", i, got[i].Name, name)
+				}
+			}
+		})
+	}
+}
+
+// TestReposListMultiValueFilters tests comma-separated filter values: OR
+// within a field and AND across fields.
+func TestReposListMultiValueFilters(t *testing.T) {
+	testRepos := []model.Repo{
+		{Name: "go-public", Language: "Go", Visibility: model.VisibilityPublic, Cloned: true},
+		{Name: "rust-private", Language: "Rust", Visibility: model.VisibilityPrivate},
+		{Name: "ts-public", Language: "TypeScript", Visibility: model.VisibilityPublic},
+		{Name: "go-private", Language: "Go", Visibility: model.VisibilityPrivate, Cloned: true},
+	}
+
+	cfg := &config.Config{
+		ScanPath:              "/tmp/test",
+		Port:                  8080,
+		LocalIntervalSeconds:  30,
+		GitHubIntervalSeconds: 300,
+		StaleDays:             30,
+		AbandonedDays:         90,
+	}
+	s, _ := NewServer(cfg)
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{name: "single language", query: "language=Go", want: []string{"go-public", "go-private"}},
+		{name: "multiple languages", query: "language=Go,Rust", want: []string{"go-public", "rust-private", "go-private"}},
+		{name: "spaces around values", query: "language=Rust,%20TypeScript", want: []string{"rust-private", "ts-public"}},
+		{name: "single visibility", query: "visibility=private", want: []string{"rust-private", "go-private"}},
+		{name: "multiple visibilities", query: "visibility=public,private", want: []string{"go-public", "rust-private", "ts-public", "go-private"}},
+		{name: "both cloned values", query: "cloned=true,false", want: []string{"go-public", "rust-private", "ts-public", "go-private"}},
+		{name: "AND across fields", query: "language=Go,Rust&visibility=private", want: []string{"rust-private", "go-private"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/repos?"+tt.query, nil)
+			filtered := s.filterRepos(testRepos, req.URL.Query())
+
+			if len(filtered) != len(tt.want) {
+				t.Fatalf("len(filtered) = %d, want %d", len(filtered), len(tt.want))
+			}
+			for i, name := range tt.want {
+				if filtered[i].Name != name {
+					t.Errorf("filtered[%d].Name = %s, want %s", i, filtered[i].Name, name)
+				}
+			}
+		})
+	}
+}
+
+// TestReposListEmptyMatchIsEmptyArray tests that a filter excluding every
+// repo yields [] rather than null.
+func TestReposListEmptyMatchIsEmptyArray(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalCachePath := cache.GetCachePath()
+	defer cache.SetCachePath(originalCachePath)
+	cache.SetCachePath(filepath.Join(tmpDir, "cache.json"))
+
+	testRepos := []model.Repo{
+		{Name: "go-repo", Language: "Go", Lifecycle: model.LifecycleOngoing},
+	}
+	if err := cache.WriteRepos(testRepos); err != nil {
+		t.Fatalf("WriteRepos failed: %v", err)
+	}
+
+	cfg := &config.Config{
+		ScanPath:              tmpDir,
+		Port:                  8080,
+		LocalIntervalSeconds:  30,
+		GitHubIntervalSeconds: 300,
+		StaleDays:             30,
+		AbandonedDays:         90,
+	}
+	s, _ := NewServer(cfg)
+
+	for _, query := range []string{"lifecycle=stale", "language=Rust", "lifecycle=stale&language=Go"} {
+		req := httptest.NewRequest(http.MethodGet, "/api/repos?"+query, nil)
+		w := httptest.NewRecorder()
+		s.handleReposList(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d, want 200", query, w.Code)
+		}
+		if body := strings.TrimSpace(w.Body.String()); body != "[]" {
+			t.Errorf("%s: body = %s, want []", query, body)
+		}
+	}
+}
+
 // TestReposListSorting tests that sorting works correctly.
 func TestReposListSorting(t *testing.T) {
 	now := time.Now().UTC()
@@ -230,16 +666,21 @@ func TestReposListSorting(t *testing.T) {
 			Name:           "zebra-repo",
 			GitHubLastPush: now.Add(-24 * time.Hour),
 			Lifecycle:      model.LifecycleAbandoned,
+			Stars:          5,
+			OpenIssues:     2,
 		},
 		{
 			Name:           "alpha-repo",
 			GitHubLastPush: now.Add(-1 * time.Hour),
 			Lifecycle:      model.LifecycleOngoing,
+			Stars:          42,
+			OpenIssues:     7,
 		},
 		{
 			Name:           "middle-repo",
 			GitHubLastPush: now.Add(-12 * time.Hour),
 			Lifecycle:      model.LifecycleStale,
+			Stars:          17,
 		},
 	}
 
@@ -295,6 +736,32 @@ func TestReposListSorting(t *testing.T) {
 		}
 	})
 
+	// Test sort by stars
+	t.Run("sort by stars desc", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/repos?sort=stars&order=desc", nil)
+		sorted := s.sortRepos(testRepos, req.URL.Query())
+
+		want := []string{"alpha-repo", "middle-repo", "zebra-repo"}
+		for i, name := range want {
+			if sorted[i].Name != name {
+				t.Errorf("sorted[%d].Name = %s, want %s", i, sorted[i].Name, name)
+			}
+		}
+	})
+
+	// Test sort by open issues
+	t.Run("sort by openIssues desc", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/repos?sort=openIssues&order=desc", nil)
+		sorted := s.sortRepos(testRepos, req.URL.Query())
+
+		want := []string{"alpha-repo", "zebra-repo", "middle-repo"}
+		for i, name := range want {
+			if sorted[i].Name != name {
+				t.Errorf("sorted[%d].Name = %s, want %s", i, sorted[i].Name, name)
+			}
+		}
+	})
+
 	// Test sort by lifecycle (alphabetical: abandoned < maintenance < ongoing < stale)
 	t.Run("sort by lifecycle asc", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodGet, "/api/repos?sort=lifecycle&order=asc", nil)
@@ -313,66 +780,228 @@ func TestReposListSorting(t *testing.T) {
 	})
 }
 
-// TestSingleRepoReturnsCorrectData tests getting a single repo.
-func TestSingleRepoReturnsCorrectData(t *testing.T) {
+// TestReposListSortTiebreaker tests that ties on the sort field are ordered by the tiebreaker.
+func TestReposListSortTiebreaker(t *testing.T) {
+	now := time.Now().UTC()
 	testRepos := []model.Repo{
-		{
-			Name:        "test-repo",
-			Description: "Test repo",
-			Visibility:  model.VisibilityPublic,
-		},
+		{Name: "charlie", Lifecycle: model.LifecycleOngoing, GitHubLastPush: now.Add(-3 * time.Hour)},
+		{Name: "alpha", Lifecycle: model.LifecycleOngoing, GitHubLastPush: now.Add(-1 * time.Hour)},
+		{Name: "delta", Lifecycle: model.LifecycleStale, GitHubLastPush: now},
+		{Name: "bravo", Lifecycle: model.LifecycleOngoing, GitHubLastPush: now.Add(-2 * time.Hour)},
 	}
 
-	// Create temp directory for cache
-	tmpDir := t.TempDir()
-	cachePath := filepath.Join(tmpDir, "cache.json")
-
-	// Write test cache
-	data, _ := json.MarshalIndent(testRepos, "", "  ")
-	os.WriteFile(cachePath, data, 0644)
-
 	cfg := &config.Config{
-		ScanPath:            tmpDir,
-		Port:                8080,
-		LocalIntervalSeconds: 30,
+		ScanPath:              "/tmp/test",
+		Port:                  8080,
+		LocalIntervalSeconds:  30,
 		GitHubIntervalSeconds: 300,
-		StaleDays:           30,
-		AbandonedDays:       90,
+		StaleDays:             30,
+		AbandonedDays:         90,
 	}
 	s, _ := NewServer(cfg)
 
-	// Override cache path
-	originalCachePath := cache.GetCachePath()
-	defer cache.SetCachePath(originalCachePath)
-	cache.SetCachePath(cachePath)
+	t.Run("default name tiebreak", func(t *testing.T) {
+		for _, order := range []string{"asc", "desc"} {
+			req := httptest.NewRequest(http.MethodGet, "/api/repos?sort=lifecycle&order="+order, nil)
+			sorted := s.sortRepos(testRepos, req.URL.Query())
 
-	// Create request
-	req := httptest.NewRequest(http.MethodGet, "/api/repos/test-repo", nil)
-	w := httptest.NewRecorder()
+			var ongoing []string
+			for _, repo := range sorted {
+				if repo.Lifecycle == model.LifecycleOngoing {
+					ongoing = append(ongoing, repo.Name)
+				}
+			}
+			want := []string{"alpha", "bravo", "charlie"}
+			for i := range want {
+				if ongoing[i] != want[i] {
+					t.Errorf("order=%s: ongoing = %v, want %v", order, ongoing, want)
+					break
+				}
+			}
+		}
+	})
 
-	s.handleRepoByName(w, req)
+	t.Run("lastUpdate tiebreak", func(t *testing.T) {
+		cfg.SortTiebreaker = config.SortTiebreakerLastUpdate
+		defer func() { cfg.SortTiebreaker = "" }()
 
-	// Check response
-	if w.Code != http.StatusOK {
-		t.Errorf("status = %d, want %d", w.Code, http.StatusOK)
-	}
+		req := httptest.NewRequest(http.MethodGet, "/api/repos?sort=lifecycle", nil)
+		sorted := s.sortRepos(testRepos, req.URL.Query())
 
-	// Parse response
-	var repo model.Repo
-	if err := json.NewDecoder(w.Body).Decode(&repo); err != nil {
-		t.Fatalf("failed to decode response: %v", err)
-	}
+		want := []string{"charlie", "bravo", "alpha", "delta"}
+		for i, name := range want {
+			if sorted[i].Name != name {
+				t.Errorf("sorted[%d].Name = %s, want %s", i, sorted[i].Name, name)
+			}
+		}
+	})
 
-	if repo.Name != "test-repo" {
-		t.Errorf("repo.Name = %s, want test-repo", repo.Name)
-	}
-}
+	t.Run("name breaks ties left by lastUpdate tiebreak", func(t *testing.T) {
+		cfg.SortTiebreaker = config.SortTiebreakerLastUpdate
+		defer func() { cfg.SortTiebreaker = "" }()
 
-// TestSingleRepo404ForUnknownName tests that unknown repo returns 404.
-func TestSingleRepo404ForUnknownName(t *testing.T) {
-	testRepos := []model.Repo{
-		{Name: "known-repo"},
-	}
+		tied := []model.Repo{
+			{Name: "zulu", Lifecycle: model.LifecycleOngoing, GitHubLastPush: now},
+			{Name: "yankee", Lifecycle: model.LifecycleOngoing, GitHubLastPush: now},
+			{Name: "xray", Lifecycle: model.LifecycleOngoing, GitHubLastPush: now},
+		}
+		req := httptest.NewRequest(http.MethodGet, "/api/repos?sort=lifecycle", nil)
+		sorted := s.sortRepos(tied, req.URL.Query())
+
+		want := []string{"xray", "yankee", "zulu"}
+		for i, name := range want {
+			if sorted[i].Name != name {
+				t.Errorf("sorted[%d].Name = %s, want %s", i, sorted[i].Name, name)
+			}
+		}
+	})
+
+	t.Run("lifecycle priority", func(t *testing.T) {
+		mixed := []model.Repo{
+			{Name: "a-ongoing", Lifecycle: model.LifecycleOngoing},
+			{Name: "b-abandoned", Lifecycle: model.LifecycleAbandoned},
+			{Name: "c-maintenance", Lifecycle: model.LifecycleMaintenance},
+			{Name: "d-stale", Lifecycle: model.LifecycleStale},
+			{Name: "e-abandoned", Lifecycle: model.LifecycleAbandoned},
+		}
+
+		req := httptest.NewRequest(http.MethodGet, "/api/repos?sort=lifecyclePriority", nil)
+		got := s.sortRepos(mixed, req.URL.Query())
+		want := []string{"b-abandoned", "e-abandoned", "d-stale", "a-ongoing", "c-maintenance"}
+		for i, name := range want {
+			if got[i].Name != name {
+				t.Errorf("default priority: sorted[%d].Name = %s, want %s", i, got[i].Name, name)
+			}
+		}
+
+		cfg.LifecyclePriority = []string{"stale", "maintenance"}
+		defer func() { cfg.LifecyclePriority = nil }()
+
+		got = s.sortRepos(mixed, req.URL.Query())
+		want = []string{"d-stale", "c-maintenance", "a-ongoing", "b-abandoned", "e-abandoned"}
+		for i, name := range want {
+			if got[i].Name != name {
+				t.Errorf("custom priority: sorted[%d].Name = %s, want %s", i, got[i].Name, name)
+			}
+		}
+	})
+
+	t.Run("multiple sort fields", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/repos?sort=lifecycle,lastUpdate&order=desc", nil)
+		sorted := s.sortRepos(testRepos, req.URL.Query())
+
+		want := []string{"delta", "alpha", "bravo", "charlie"}
+		for i, name := range want {
+			if sorted[i].Name != name {
+				t.Errorf("sorted[%d].Name = %s, want %s", i, sorted[i].Name, name)
+			}
+		}
+	})
+}
+
+// TestReposListSortByOpenPRsAndDirty tests the openPRs and dirty sort fields.
+func TestReposListSortByOpenPRsAndDirty(t *testing.T) {
+	testRepos := []model.Repo{
+		{Name: "delta", OpenPRs: 1},
+		{Name: "alpha", OpenPRs: 3, Dirty: true},
+		{Name: "charlie", OpenPRs: 1, Dirty: true},
+		{Name: "bravo"},
+	}
+
+	cfg := &config.Config{
+		ScanPath:              "/tmp/test",
+		Port:                  8080,
+		LocalIntervalSeconds:  30,
+		GitHubIntervalSeconds: 300,
+		StaleDays:             30,
+		AbandonedDays:         90,
+	}
+	s, _ := NewServer(cfg)
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{query: "sort=openPRs&order=desc", want: []string{"alpha", "charlie", "delta", "bravo"}},
+		{query: "sort=openPRs&order=asc", want: []string{"bravo", "charlie", "delta", "alpha"}},
+		{query: "sort=dirty&order=desc", want: []string{"alpha", "charlie", "bravo", "delta"}},
+		{query: "sort=dirty&order=asc", want: []string{"bravo", "delta", "alpha", "charlie"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/repos?"+tt.query, nil)
+			sorted := s.sortRepos(testRepos, req.URL.Query())
+
+			for i, name := range tt.want {
+				if sorted[i].Name != name {
+					t.Errorf("sorted[%d].Name = %s, want %s", i, sorted[i].Name, name)
+				}
+			}
+		})
+	}
+}
+
+// TestSingleRepoReturnsCorrectData tests getting a single repo.
+func TestSingleRepoReturnsCorrectData(t *testing.T) {
+	testRepos := []model.Repo{
+		{
+			Name:        "test-repo",
+			Description: "Test repo",
+			Visibility:  model.VisibilityPublic,
+		},
+	}
+
+	// Create temp directory for cache
+	tmpDir := t.TempDir()
+	cachePath := filepath.Join(tmpDir, "cache.json")
+
+	// Write test cache
+	data, _ := json.MarshalIndent(testRepos, "", "  ")
+	os.WriteFile(cachePath, data, 0644)
+
+	cfg := &config.Config{
+		ScanPath:            tmpDir,
+		Port:                8080,
+		LocalIntervalSeconds: 30,
+		GitHubIntervalSeconds: 300,
+		StaleDays:           30,
+		AbandonedDays:       90,
+	}
+	s, _ := NewServer(cfg)
+
+	// Override cache path
+	originalCachePath := cache.GetCachePath()
+	defer cache.SetCachePath(originalCachePath)
+	cache.SetCachePath(cachePath)
+
+	// Create request
+	req := httptest.NewRequest(http.MethodGet, "/api/repos/test-repo", nil)
+	w := httptest.NewRecorder()
+
+	s.handleRepoByName(w, req)
+
+	// Check response
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	// Parse response
+	var repo model.Repo
+	if err := json.NewDecoder(w.Body).Decode(&repo); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if repo.Name != "test-repo" {
+		t.Errorf("repo.Name = %s, want test-repo", repo.Name)
+	}
+}
+
+// TestSingleRepo404ForUnknownName tests that unknown repo returns 404.
+func TestSingleRepo404ForUnknownName(t *testing.T) {
+	testRepos := []model.Repo{
+		{Name: "known-repo"},
+	}
 
 	// Create temp directory for cache
 	tmpDir := t.TempDir()
@@ -409,6 +1038,133 @@ func TestSingleRepo404ForUnknownName(t *testing.T) {
 	}
 }
 
+// TestPullNotCloned tests that pulling an uncloned repo returns 404.
+func TestPullNotCloned(t *testing.T) {
+	cfg := &config.Config{
+		ScanPath:              t.TempDir(),
+		Port:                  8080,
+		LocalIntervalSeconds:  30,
+		GitHubIntervalSeconds: 300,
+		StaleDays:             30,
+		AbandonedDays:         90,
+	}
+	s, _ := NewServer(cfg)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/repos/missing-repo/pull", nil)
+	w := httptest.NewRecorder()
+
+	s.handleRepoByName(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+// TestPullDirtyTreeRejected tests that pulling a repo with uncommitted changes returns 409.
+func TestPullDirtyTreeRejected(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	scanPath := t.TempDir()
+	repoPath := filepath.Join(scanPath, "dirty-repo")
+	runGit(t, scanPath, "init", repoPath)
+	if err := os.WriteFile(filepath.Join(repoPath, "untracked.txt"), []byte("wip"), 0o644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	cfg := &config.Config{
+		ScanPath:              scanPath,
+		Port:                  8080,
+		LocalIntervalSeconds:  30,
+		GitHubIntervalSeconds: 300,
+		StaleDays:             30,
+		AbandonedDays:         90,
+	}
+	s, _ := NewServer(cfg)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/repos/dirty-repo/pull", nil)
+	w := httptest.NewRecorder()
+
+	s.handleRepoByName(w, req)
+
+	if w.Code != http.StatusConflict {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusConflict)
+	}
+}
+
+// TestRepoDiff tests that the diff endpoint returns working-tree and staged changes.
+func TestRepoDiff(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	scanPath := t.TempDir()
+	repoPath := filepath.Join(scanPath, "diff-repo")
+	runGit(t, scanPath, "init", repoPath)
+	runGit(t, repoPath, "config", "user.email", "test@example.com")
+	runGit(t, repoPath, "config", "user.name", "Test User")
+	for _, name := range []string{"unstaged.txt", "staged.txt"} {
+		if err := os.WriteFile(filepath.Join(repoPath, name), []byte("original\n"), 0o644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+	}
+	runGit(t, repoPath, "add", ".")
+	runGit(t, repoPath, "commit", "-m", "initial")
+
+	cfg := &config.Config{
+		ScanPath:              scanPath,
+		Port:                  8080,
+		LocalIntervalSeconds:  30,
+		GitHubIntervalSeconds: 300,
+		StaleDays:             30,
+		AbandonedDays:         90,
+	}
+	s, _ := NewServer(cfg)
+
+	getDiff := func(path string) (int, RepoDiff) {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		s.handleRepoByName(w, req)
+		var diff RepoDiff
+		if w.Code == http.StatusOK {
+			if err := json.NewDecoder(w.Body).Decode(&diff); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+		}
+		return w.Code, diff
+	}
+
+	if code, diff := getDiff("/api/repos/diff-repo/diff"); code != http.StatusOK || diff.Diff != "" {
+		t.Fatalf("clean tree: status = %d, diff = %q, want 200 and empty", code, diff.Diff)
+	}
+
+	if err := os.WriteFile(filepath.Join(repoPath, "unstaged.txt"), []byte("unstaged change\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(repoPath, "staged.txt"), []byte("staged change\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	runGit(t, repoPath, "add", "staged.txt")
+
+	code, diff := getDiff("/api/repos/diff-repo/diff")
+	if code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", code)
+	}
+	for _, want := range []string{"+unstaged change", "+staged change", "-original"} {
+		if !strings.Contains(diff.Diff, want) {
+			t.Errorf("diff missing %q:\n%s", want, diff.Diff)
+		}
+	}
+	if diff.Truncated {
+		t.Error("Truncated = true, want false")
+	}
+
+	if code, _ := getDiff("/api/repos/not-cloned/diff"); code != http.StatusNotFound {
+		t.Errorf("not cloned: status = %d, want 404", code)
+	}
+}
+
 // TestHealthEndpointShape tests the health endpoint returns correct shape.
 func TestHealthEndpointShape(t *testing.T) {
 	cfg := &config.Config{
@@ -439,7 +1195,7 @@ func TestHealthEndpointShape(t *testing.T) {
 	}
 
 	// Check required fields
-	requiredFields := []string{"Uptime", "LastLocalPoll", "LastGitHubPoll", "TotalRepos", "GhAvailable", "GhAuthenticated"}
+	requiredFields := []string{"Uptime", "LastLocalPoll", "LastGitHubPoll", "LastLocalPollError", "LastGitHubPollError", "TotalRepos", "GhAvailable", "GhAuthenticated", "GhLogin", "GhHost", "GhMissingScopes", "GitHubHealthy", "GitHubFailures", "SSEClients", "SSEMaxClients", "Warnings"}
 	for _, field := range requiredFields {
 		if _, ok := health[field]; !ok {
 			t.Errorf("response missing field: %s", field)
@@ -447,6 +1203,72 @@ func TestHealthEndpointShape(t *testing.T) {
 	}
 }
 
+// TestHealthGHAuth tests that health reports the gh login when
+// authenticated, reports unauthenticated when gh auth status fails, and
+// reuses a recent check instead of running gh again.
+func TestHealthGHAuth(t *testing.T) {
+	original := checkGHAuth
+	defer func() { checkGHAuth = original }()
+
+	cfg := &config.Config{
+		ScanPath:              "/tmp/test",
+		Port:                  8080,
+		LocalIntervalSeconds:  30,
+		GitHubIntervalSeconds: 300,
+		StaleDays:             30,
+		AbandonedDays:         90,
+	}
+
+	health := func(s *Server) map[string]interface{} {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/api/health", nil)
+		w := httptest.NewRecorder()
+		s.handleHealth(w, req)
+		var health map[string]interface{}
+		if err := json.NewDecoder(w.Body).Decode(&health); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		return health
+	}
+
+	t.Run("authenticated", func(t *testing.T) {
+		calls := 0
+		checkGHAuth = func() (*scanner.GHAuthStatus, error) {
+			calls++
+			return &scanner.GHAuthStatus{Login: "alexcatdad", Host: "github.com"}, nil
+		}
+		s, _ := NewServer(cfg)
+
+		got := health(s)
+		if got["GhAvailable"] != true || got["GhAuthenticated"] != true {
+			t.Errorf("GhAvailable = %v, GhAuthenticated = %v, want true", got["GhAvailable"], got["GhAuthenticated"])
+		}
+		if got["GhLogin"] != "alexcatdad" || got["GhHost"] != "github.com" {
+			t.Errorf("GhLogin = %v, GhHost = %v, want alexcatdad on github.com", got["GhLogin"], got["GhHost"])
+		}
+
+		health(s)
+		if calls != 1 {
+			t.Errorf("checkGHAuth called %d times, want 1 within the cache TTL", calls)
+		}
+	})
+
+	t.Run("not authenticated", func(t *testing.T) {
+		checkGHAuth = func() (*scanner.GHAuthStatus, error) {
+			return nil, scanner.NewGHAuthError("You are not logged into any GitHub hosts.")
+		}
+		s, _ := NewServer(cfg)
+
+		got := health(s)
+		if got["GhAvailable"] != true || got["GhAuthenticated"] != false {
+			t.Errorf("GhAvailable = %v, GhAuthenticated = %v, want true, false", got["GhAvailable"], got["GhAuthenticated"])
+		}
+		if got["GhLogin"] != "" {
+			t.Errorf("GhLogin = %v, want empty", got["GhLogin"])
+		}
+	})
+}
+
 // TestConfigGet tests getting config.
 func TestConfigGet(t *testing.T) {
 	cfg := &config.Config{
@@ -481,161 +1303,329 @@ func TestConfigGet(t *testing.T) {
 	}
 }
 
-// TestConfigValidation tests config validation.
-func TestConfigValidation(t *testing.T) {
+// TestPrettyJSON tests that ?pretty=true indents JSON responses and that
+// output is compact otherwise.
+func TestPrettyJSON(t *testing.T) {
 	cfg := &config.Config{
-		ScanPath:            "/tmp/test",
-		Port:                8080,
-		LocalIntervalSeconds: 30,
-		GitHubIntervalSeconds: 300,
-		StaleDays:           30,
-		AbandonedDays:       90,
+		ScanPath:              "/test/path",
+		Port:                  9999,
+		LocalIntervalSeconds:  45,
+		GitHubIntervalSeconds: 600,
+		StaleDays:             60,
+		AbandonedDays:         180,
 	}
 	s, _ := NewServer(cfg)
 
 	tests := []struct {
-		name        string
-		cfg         config.Config
-		wantErr     bool
-		errContains string
+		query  string
+		indent bool
 	}{
-		{
-			name: "valid config",
-			cfg: config.Config{
-				ScanPath:            "/tmp/test",
-				Port:                8080,
-				LocalIntervalSeconds: 30,
-				GitHubIntervalSeconds: 300,
-				StaleDays:           30,
-				AbandonedDays:       90,
-			},
-			wantErr: false,
-		},
-		{
-			name: "empty scan path",
-			cfg: config.Config{
-				ScanPath:            "",
-				Port:                8080,
-				LocalIntervalSeconds: 30,
-				GitHubIntervalSeconds: 300,
-				StaleDays:           30,
-				AbandonedDays:       90,
-			},
-			wantErr:     true,
-			errContains: "scanPath",
-		},
-		{
-			name: "port too low",
-			cfg: config.Config{
-				ScanPath:            "/tmp/test",
-				Port:                80,
-				LocalIntervalSeconds: 30,
-				GitHubIntervalSeconds: 300,
-				StaleDays:           30,
-				AbandonedDays:       90,
-			},
-			wantErr:     true,
-			errContains: "port",
-		},
-		{
-			name: "local interval too low",
-			cfg: config.Config{
-				ScanPath:            "/tmp/test",
-				Port:                8080,
-				LocalIntervalSeconds: 5,
-				GitHubIntervalSeconds: 300,
-				StaleDays:           30,
-				AbandonedDays:       90,
-			},
-			wantErr:     true,
-			errContains: "localIntervalSeconds",
-		},
-		{
-			name: "GitHub interval too low",
-			cfg: config.Config{
-				ScanPath:            "/tmp/test",
-				Port:                8080,
-				LocalIntervalSeconds: 30,
-				GitHubIntervalSeconds: 30,
-				StaleDays:           30,
-				AbandonedDays:       90,
-			},
-			wantErr:     true,
-			errContains: "githubIntervalSeconds",
-		},
-		{
-			name: "stale >= abandoned",
-			cfg: config.Config{
-				ScanPath:            "/tmp/test",
-				Port:                8080,
-				LocalIntervalSeconds: 30,
-				GitHubIntervalSeconds: 300,
-				StaleDays:           90,
-				AbandonedDays:       90,
-			},
-			wantErr:     true,
-			errContains: "staleDays",
-		},
+		{"", false},
+		{"?pretty=false", false},
+		{"?pretty=true", true},
+		{"?pretty=1", true},
 	}
 
 	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			err := s.validateConfig(&tt.cfg)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("validateConfig() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-			if err != nil && tt.errContains != "" {
-				if !contains(err.Error(), tt.errContains) {
-					t.Errorf("error = %v, want contain %s", err, tt.errContains)
-				}
-			}
-		})
+		req := httptest.NewRequest(http.MethodGet, "/api/config"+tt.query, nil)
+		w := httptest.NewRecorder()
+		s.handleGetConfig(w, req)
+
+		body := w.Body.String()
+		if got := strings.Contains(body, "\n  \"scanPath\""); got != tt.indent {
+			t.Errorf("query %q: indented = %v, want %v (body %q)", tt.query, got, tt.indent, body)
+		}
+
+		var returnedCfg config.Config
+		if err := json.Unmarshal([]byte(body), &returnedCfg); err != nil {
+			t.Errorf("query %q: invalid JSON: %v", tt.query, err)
+		}
 	}
 }
 
-// TestSSEConnectionReceivesEvents tests that SSE connections receive events.
-func TestSSEConnectionReceivesEvents(t *testing.T) {
-	hub := sse.NewHub()
+// TestConfigReloadFromDisk tests that editing the config file updates the
+// poller's interval without a restart.
+func TestConfigReloadFromDisk(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+
+	cfg := config.Config{
+		ScanPath:              t.TempDir(),
+		Port:                  8080,
+		LocalIntervalSeconds:  30,
+		GitHubIntervalSeconds: 300,
+		StaleDays:             30,
+		AbandonedDays:         90,
+	}
+	if err := config.Save(cfg); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	s, _ := NewServer(&cfg)
+
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
+	go config.Watch(ctx, 10*time.Millisecond, s.reloadConfig)
 
-	go hub.Run(ctx)
+	// Let the watcher record the initial file state
+	time.Sleep(30 * time.Millisecond)
 
-	// Register a client
-	client := &sse.Client{
-		ID:     "test-client",
-		Chan:   make(chan sse.Event, 10),
-		Ctx:    ctx,
-		Cancel: cancel,
+	updated := cfg
+	updated.LocalIntervalSeconds = 45
+	if err := config.Save(updated); err != nil {
+		t.Fatalf("Save failed: %v", err)
 	}
-	hub.Register(client)
-	time.Sleep(10 * time.Millisecond)
-
-	// Broadcast an event
-	hub.Broadcast("test_event", map[string]string{"message": "hello"})
 
-	// Check client received the event
-	select {
-	case event := <-client.Chan:
-		if event.Type != "test_event" {
-			t.Errorf("event.Type = %s, want test_event", event.Type)
+	deadline := time.Now().Add(2 * time.Second)
+	for s.poller.LocalInterval() != 45*time.Second {
+		if time.Now().After(deadline) {
+			t.Fatalf("LocalInterval = %v, want 45s", s.poller.LocalInterval())
 		}
-	case <-time.After(100 * time.Millisecond):
-		t.Error("did not receive event within timeout")
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// An invalid edit is ignored
+	updated.LocalIntervalSeconds = 1
+	if err := config.Save(updated); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if got := s.poller.LocalInterval(); got != 45*time.Second {
+		t.Errorf("LocalInterval after invalid edit = %v, want 45s", got)
 	}
 }
 
-// TestSSEBroadcastReachesAllClients tests that broadcast reaches all connected clients.
-func TestSSEBroadcastReachesAllClients(t *testing.T) {
-	hub := sse.NewHub()
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+// TestPutConfigKeepsEnvTokenOutOfFile tests that saving config from the
+// dashboard doesn't persist a token set through CATSCAN_AUTH_TOKEN.
+func TestPutConfigKeepsEnvTokenOutOfFile(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("CATSCAN_AUTH_TOKEN", "s3cret")
 
-	go hub.Run(ctx)
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	cfg.ScanPath = t.TempDir()
+	s, err := NewServer(&cfg)
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
 
-	// Create multiple clients
-	var clients []*sse.Client
+	updated := cfg
+	updated.StaleDays = 45
+	body, _ := json.Marshal(updated)
+	req := httptest.NewRequest(http.MethodPut, "/api/config", strings.NewReader(string(body)))
+	w := httptest.NewRecorder()
+	s.handleConfig(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("PUT status = %d, want 200: %s", w.Code, w.Body.String())
+	}
+
+	data, err := os.ReadFile(filepath.Join(home, ".config", "catscan", "config.json"))
+	if err != nil {
+		t.Fatalf("reading config file: %v", err)
+	}
+	if strings.Contains(string(data), "authToken") {
+		t.Errorf("config.json contains authToken:\n%s", data)
+	}
+	if s.config().AuthToken != "s3cret" {
+		t.Error("running config lost the env token")
+	}
+}
+
+// TestConfigRedactsAuthToken tests that the auth token is never sent to
+// clients, and that a PUT without it keeps the current token.
+func TestConfigRedactsAuthToken(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+
+	cfg := config.Config{
+		ScanPath:              t.TempDir(),
+		Port:                  8080,
+		LocalIntervalSeconds:  30,
+		GitHubIntervalSeconds: 300,
+		StaleDays:             30,
+		AbandonedDays:         90,
+		AuthToken:             "s3cret",
+	}
+	s, err := NewServer(&cfg)
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.hub.Run(ctx)
+	client := &sse.Client{ID: "reader", Chan: make(chan sse.Event, 10), Ctx: ctx, Cancel: cancel}
+	if err := s.hub.Register(client); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/config", nil)
+	w := httptest.NewRecorder()
+	s.handleConfig(w, req)
+	if strings.Contains(w.Body.String(), "s3cret") {
+		t.Errorf("GET /api/config exposes the token: %s", w.Body.String())
+	}
+
+	// Send back what GET returned, with an edit
+	var updated config.Config
+	if err := json.NewDecoder(w.Body).Decode(&updated); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	updated.StaleDays = 45
+	body, _ := json.Marshal(updated)
+	req = httptest.NewRequest(http.MethodPut, "/api/config", strings.NewReader(string(body)))
+	w = httptest.NewRecorder()
+	s.handleConfig(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("PUT status = %d, want 200: %s", w.Code, w.Body.String())
+	}
+	if strings.Contains(w.Body.String(), "s3cret") {
+		t.Errorf("PUT response exposes the token: %s", w.Body.String())
+	}
+	if got := s.config(); got.AuthToken != "s3cret" || got.StaleDays != 45 {
+		t.Errorf("after PUT token %q StaleDays %d, want the token kept and 45", got.AuthToken, got.StaleDays)
+	}
+
+	select {
+	case event := <-client.Chan:
+		if event.Type != "config_updated" {
+			t.Fatalf("event = %s, want config_updated", event.Type)
+		}
+		if event.Data.(config.Config).AuthToken != "" {
+			t.Error("config_updated event exposes the token")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("did not receive config_updated")
+	}
+}
+
+// TestReconfigureRejectsSubMinimumInterval tests that Reconfigure rejects an
+// interval below the minimum and keeps the running config.
+func TestReconfigureRejectsSubMinimumInterval(t *testing.T) {
+	cfg := config.Config{
+		ScanPath:              t.TempDir(),
+		Port:                  8080,
+		LocalIntervalSeconds:  30,
+		GitHubIntervalSeconds: 300,
+		StaleDays:             30,
+		AbandonedDays:         90,
+	}
+	s, err := NewServer(&cfg)
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+
+	updated := cfg
+	updated.LocalIntervalSeconds = config.MinLocalIntervalSeconds - 1
+	err = s.Reconfigure(updated)
+	if err == nil {
+		t.Fatal("Reconfigure accepted a sub-minimum interval")
+	}
+	if !strings.Contains(err.Error(), "localIntervalSeconds") {
+		t.Errorf("error = %q, want it to name localIntervalSeconds", err)
+	}
+
+	if got := s.poller.LocalInterval(); got != 30*time.Second {
+		t.Errorf("poller LocalInterval = %v, want 30s", got)
+	}
+	if s.cfg.LocalIntervalSeconds != 30 {
+		t.Errorf("server LocalIntervalSeconds = %d, want 30", s.cfg.LocalIntervalSeconds)
+	}
+
+	// The same rules apply at construction, e.g. for test mode or env overrides
+	updated.GitHubIntervalSeconds = config.MinGitHubIntervalSeconds - 1
+	if _, err := NewServer(&updated); err == nil {
+		t.Error("NewServer accepted a sub-minimum interval")
+	}
+}
+
+// TestReconfigureDuringRequests tests that handlers can read the config
+// while a reload swaps it. Run with -race to catch unguarded reads.
+func TestReconfigureDuringRequests(t *testing.T) {
+	cfg := config.Config{
+		ScanPath:              t.TempDir(),
+		Port:                  8080,
+		LocalIntervalSeconds:  30,
+		GitHubIntervalSeconds: 300,
+		StaleDays:             30,
+		AbandonedDays:         90,
+	}
+	s, err := NewServer(&cfg)
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := range 50 {
+			updated := cfg
+			updated.LocalIntervalSeconds = 30 + i
+			if err := s.Reconfigure(updated); err != nil {
+				t.Errorf("Reconfigure failed: %v", err)
+				return
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for range 50 {
+			req := httptest.NewRequest(http.MethodGet, "/api/config", nil)
+			s.handleConfig(httptest.NewRecorder(), req)
+			req = httptest.NewRequest(http.MethodPost, "/api/repos/missing/pull", nil)
+			s.handleRepoByName(httptest.NewRecorder(), req)
+		}
+	}()
+	wg.Wait()
+}
+
+// TestSSEConnectionReceivesEvents tests that SSE connections receive events.
+func TestSSEConnectionReceivesEvents(t *testing.T) {
+	hub := sse.NewHub()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go hub.Run(ctx)
+
+	// Register a client
+	client := &sse.Client{
+		ID:     "test-client",
+		Chan:   make(chan sse.Event, 10),
+		Ctx:    ctx,
+		Cancel: cancel,
+	}
+	hub.Register(client)
+	time.Sleep(10 * time.Millisecond)
+
+	// Broadcast an event
+	hub.Broadcast("test_event", map[string]string{"message": "hello"})
+
+	// Check client received the event
+	select {
+	case event := <-client.Chan:
+		if event.Type != "test_event" {
+			t.Errorf("event.Type = %s, want test_event", event.Type)
+		}
+	case <-time.After(100 * time.Millisecond):
+		t.Error("did not receive event within timeout")
+	}
+}
+
+// TestSSEBroadcastReachesAllClients tests that broadcast reaches all connected clients.
+func TestSSEBroadcastReachesAllClients(t *testing.T) {
+	hub := sse.NewHub()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go hub.Run(ctx)
+
+	// Create multiple clients
+	var clients []*sse.Client
 	for i := 0; i < 3; i++ {
 		client := &sse.Client{
 			ID:     fmt.Sprintf("client-%d", i),
@@ -743,6 +1733,99 @@ func TestHandleEventsSSE(t *testing.T) {
 	// The important thing is no panic occurred during handler startup
 }
 
+// TestHandleEventsPaginatedSnapshot tests that the initial repo list arrives
+// in repos_snapshot pages.
+func TestHandleEventsPaginatedSnapshot(t *testing.T) {
+	testRepos := make([]model.Repo, 2*snapshotPageSize+50)
+	for i := range testRepos {
+		testRepos[i] = model.Repo{Name: fmt.Sprintf("repo-%03d", i), Visibility: model.VisibilityPublic}
+	}
+
+	tmpDir := t.TempDir()
+	cachePath := filepath.Join(tmpDir, "cache.json")
+	data, _ := json.Marshal(testRepos)
+	os.WriteFile(cachePath, data, 0644)
+
+	originalCachePath := cache.GetCachePath()
+	defer cache.SetCachePath(originalCachePath)
+	cache.SetCachePath(cachePath)
+
+	cfg := &config.Config{
+		ScanPath:              tmpDir,
+		Port:                  8080,
+		LocalIntervalSeconds:  30,
+		GitHubIntervalSeconds: 300,
+		StaleDays:             30,
+		AbandonedDays:         90,
+	}
+	s, _ := NewServer(cfg)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.hub.Run(ctx)
+
+	srv := httptest.NewServer(http.HandlerFunc(s.handleEvents))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("connecting: %v", err)
+	}
+	defer resp.Body.Close()
+	reader := bufio.NewReader(resp.Body)
+
+	if typ, _ := readEvent(t, reader); typ != "connected" {
+		t.Fatalf("first event = %s, want connected", typ)
+	}
+
+	var received int
+	for page := 1; page <= 3; page++ {
+		typ, payload := readEvent(t, reader)
+		if typ != "repos_snapshot" {
+			t.Fatalf("event %d type = %s, want repos_snapshot", page, typ)
+		}
+
+		var frame struct {
+			Page  int          `json:"page"`
+			Total int          `json:"total"`
+			Repos []model.Repo `json:"repos"`
+		}
+		if err := json.Unmarshal([]byte(payload), &frame); err != nil {
+			t.Fatalf("decoding page %d: %v", page, err)
+		}
+		if frame.Page != page || frame.Total != 3 {
+			t.Errorf("frame page=%d total=%d, want page=%d total=3", frame.Page, frame.Total, page)
+		}
+		received += len(frame.Repos)
+	}
+
+	if received != len(testRepos) {
+		t.Errorf("received %d repos across pages, want %d", received, len(testRepos))
+	}
+}
+
+// readEvent reads a single SSE event and returns its type and data.
+func readEvent(t *testing.T, reader *bufio.Reader) (eventType, data string) {
+	t.Helper()
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("reading event: %v", err)
+		}
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case line == "":
+			if eventType != "" {
+				return eventType, data
+			}
+		case strings.HasPrefix(line, "event: "):
+			eventType = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
 // TestConcurrentRequests tests that the server handles concurrent requests safely.
 func TestConcurrentRequests(t *testing.T) {
 	testRepos := []model.Repo{
@@ -795,16 +1878,1500 @@ func TestConcurrentRequests(t *testing.T) {
 	wg.Wait()
 }
 
-// Helper function to check if a string contains a substring
-func contains(s, substr string) bool {
-	return len(s) >= len(substr) && (s == substr || len(s) > len(substr) && (s[:len(substr)] == substr || s[len(s)-len(substr):] == substr || indexOf(s, substr) >= 0))
-}
+// TestOwnersEndpoint tests that owner profiles are parsed and cached.
+func TestOwnersEndpoint(t *testing.T) {
+	originalCachePath := cache.GetCachePath()
+	defer cache.SetCachePath(originalCachePath)
+	cache.SetCachePath(filepath.Join(t.TempDir(), "cache.json"))
+
+	cfg := &config.Config{
+		ScanPath:              "/tmp/test",
+		GitHubOwner:           "octocat",
+		Port:                  8080,
+		LocalIntervalSeconds:  30,
+		GitHubIntervalSeconds: 300,
+		StaleDays:             30,
+		AbandonedDays:         90,
+	}
+	s, _ := NewServer(cfg)
+
+	// Fake gh api users/:owner payload
+	payload := []byte(`{"login":"octocat","name":"The Octocat","avatar_url":"https://avatars.example.com/u/1"}`)
+	calls := 0
+	original := fetchOwnerInfo
+	defer func() { fetchOwnerInfo = original }()
+	fetchOwnerInfo = func(owner string) (*scanner.OwnerInfo, error) {
+		calls++
+		return scanner.ParseOwnerInfo(owner, payload)
+	}
+
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/api/owners", nil)
+		w := httptest.NewRecorder()
+		s.handleOwners(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+		}
+
+		var owners []scanner.OwnerInfo
+		if err := json.NewDecoder(w.Body).Decode(&owners); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if len(owners) != 1 {
+			t.Fatalf("len(owners) = %d, want 1", len(owners))
+		}
+		if owners[0].Name != "The Octocat" || owners[0].AvatarURL != "https://avatars.example.com/u/1" || !owners[0].Found {
+			t.Errorf("owners[0] = %+v, want parsed profile", owners[0])
+		}
+	}
 
-func indexOf(s, substr string) int {
-	for i := 0; i <= len(s)-len(substr); i++ {
-		if s[i:i+len(substr)] == substr {
-			return i
+	if calls != 1 {
+		t.Errorf("fetchOwnerInfo called %d times, want 1 (cached)", calls)
+	}
+
+	// Unknown owners are returned without a profile rather than failing
+	t.Run("unknown owner", func(t *testing.T) {
+		info, err := scanner.ParseOwnerInfo("ghost", []byte(`{"message":"Not Found"}`))
+		if err != nil {
+			t.Fatalf("ParseOwnerInfo failed: %v", err)
 		}
+		if info.Found || info.Login != "ghost" {
+			t.Errorf("info = %+v, want Login=ghost Found=false", info)
+		}
+	})
+}
+
+// TestOwnersIncludesOrgs tests that /api/owners lists each org that cached
+// repos were listed from after the configured owner, once each.
+func TestOwnersIncludesOrgs(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalCachePath := cache.GetCachePath()
+	defer cache.SetCachePath(originalCachePath)
+	cache.SetCachePath(filepath.Join(tmpDir, "cache.json"))
+
+	if err := cache.WriteRepos([]model.Repo{
+		{Name: "catscan"},
+		{Name: "service", Owner: "org-b"},
+		{Name: "api", Owner: "org-a"},
+		{Name: "web", Owner: "org-b"},
+	}); err != nil {
+		t.Fatalf("WriteRepos failed: %v", err)
+	}
+
+	cfg := &config.Config{
+		ScanPath:              tmpDir,
+		GitHubOwner:           "octocat",
+		IncludeAllOrgs:        true,
+		Port:                  8080,
+		LocalIntervalSeconds:  30,
+		GitHubIntervalSeconds: 300,
+		StaleDays:             30,
+		AbandonedDays:         90,
+	}
+	s, _ := NewServer(cfg)
+
+	original := fetchOwnerInfo
+	defer func() { fetchOwnerInfo = original }()
+	fetchOwnerInfo = func(owner string) (*scanner.OwnerInfo, error) {
+		return &scanner.OwnerInfo{Login: owner, Found: true}, nil
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/owners", nil)
+	w := httptest.NewRecorder()
+	s.handleOwners(w, req)
+
+	var owners []scanner.OwnerInfo
+	if err := json.NewDecoder(w.Body).Decode(&owners); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	var logins []string
+	for _, owner := range owners {
+		logins = append(logins, owner.Login)
+	}
+	if want := []string{"octocat", "org-a", "org-b"}; !slices.Equal(logins, want) {
+		t.Errorf("owners = %v, want %v", logins, want)
+	}
+}
+
+// TestArchiveEndpoint tests the archive endpoint's gating and gh invocation.
+func TestArchiveEndpoint(t *testing.T) {
+	testRepos := []model.Repo{
+		{
+			Name:           "old-repo",
+			Visibility:     model.VisibilityPublic,
+			Lifecycle:      model.LifecycleAbandoned,
+			SuggestArchive: true,
+		},
+		{
+			Name:       "service",
+			Owner:      "org-a",
+			Visibility: model.VisibilityPrivate,
+		},
+	}
+
+	tmpDir := t.TempDir()
+	cachePath := filepath.Join(tmpDir, "cache.json")
+	data, _ := json.MarshalIndent(testRepos, "", "  ")
+	os.WriteFile(cachePath, data, 0644)
+
+	originalCachePath := cache.GetCachePath()
+	defer cache.SetCachePath(originalCachePath)
+	cache.SetCachePath(cachePath)
+
+	cfg := &config.Config{
+		ScanPath:              tmpDir,
+		GitHubOwner:           "octocat",
+		Port:                  8080,
+		LocalIntervalSeconds:  30,
+		GitHubIntervalSeconds: 300,
+		StaleDays:             30,
+		AbandonedDays:         90,
+	}
+	s, _ := NewServer(cfg)
+
+	// Fake runner records archive calls instead of running gh
+	var archived []string
+	original := archiveRepo
+	defer func() { archiveRepo = original }()
+	archiveRepo = func(owner, name string) error {
+		archived = append(archived, owner+"/"+name)
+		return nil
+	}
+
+	post := func(target string) int {
+		req := httptest.NewRequest(http.MethodPost, target, nil)
+		w := httptest.NewRecorder()
+		s.handleRepoByName(w, req)
+		return w.Code
+	}
+
+	cfg.ReadOnly = true
+	if code := post("/api/repos/old-repo/archive?confirm=true"); code != http.StatusForbidden {
+		t.Errorf("read-only: status = %d, want %d", code, http.StatusForbidden)
+	}
+	cfg.ReadOnly = false
+
+	if code := post("/api/repos/old-repo/archive"); code != http.StatusBadRequest {
+		t.Errorf("without confirm: status = %d, want %d", code, http.StatusBadRequest)
+	}
+	if code := post("/api/repos/missing/archive?confirm=true"); code != http.StatusNotFound {
+		t.Errorf("unknown repo: status = %d, want %d", code, http.StatusNotFound)
+	}
+	if len(archived) != 0 {
+		t.Fatalf("archiveRepo called %v before a valid request", archived)
+	}
+
+	if code := post("/api/repos/old-repo/archive?confirm=true"); code != http.StatusOK {
+		t.Fatalf("archive: status = %d, want %d", code, http.StatusOK)
+	}
+	if len(archived) != 1 || archived[0] != "octocat/old-repo" {
+		t.Errorf("archived = %v, want [octocat/old-repo]", archived)
+	}
+
+	repos, err := cache.ReadRepos()
+	if err != nil {
+		t.Fatalf("ReadRepos failed: %v", err)
+	}
+	if !repos[0].Archived || repos[0].SuggestArchive {
+		t.Errorf("cached repo Archived=%v SuggestArchive=%v, want true false", repos[0].Archived, repos[0].SuggestArchive)
+	}
+
+	if code := post("/api/repos/old-repo/archive?confirm=true"); code != http.StatusConflict {
+		t.Errorf("already archived: status = %d, want %d", code, http.StatusConflict)
+	}
+
+	// An org repo is archived under its own owner, not GitHubOwner
+	if code := post("/api/repos/service/archive?confirm=true"); code != http.StatusOK {
+		t.Fatalf("archive org repo: status = %d, want %d", code, http.StatusOK)
+	}
+	if len(archived) != 2 || archived[1] != "org-a/service" {
+		t.Errorf("archived = %v, want org-a/service archived second", archived)
+	}
+}
+
+// runGit runs a git command in dir and fails the test on error.
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v failed: %v (%s)", args, err, output)
+	}
+}
+
+// TestRepoExportImportRoundTrip tests that a single repo's export can be
+// imported back after its data and state are lost.
+func TestRepoExportImportRoundTrip(t *testing.T) {
+	tmpDir := t.TempDir()
+	cachePath := filepath.Join(tmpDir, "cache.json")
+	originalCachePath := cache.GetCachePath()
+	defer cache.SetCachePath(originalCachePath)
+	cache.SetCachePath(cachePath)
+
+	pushed := time.Now().UTC().Add(-48 * time.Hour).Truncate(time.Second)
+	testRepos := []model.Repo{
+		{
+			Name:           "meowtern",
+			Visibility:     model.VisibilityPublic,
+			Description:    "A cat-themed tool",
+			Topics:         []string{"cats", "go"},
+			OpenPRs:        2,
+			GitHubLastPush: pushed,
+			LatestRelease:  &model.ReleaseInfo{TagName: "v1.0.0", PublishedAt: pushed},
+			Lifecycle:      model.LifecycleOngoing,
+		},
+		{Name: "other-repo", Visibility: model.VisibilityPrivate},
+	}
+	if err := cache.WriteRepos(testRepos); err != nil {
+		t.Fatalf("WriteRepos failed: %v", err)
+	}
+
+	cfg := &config.Config{
+		ScanPath:              tmpDir,
+		Port:                  8080,
+		LocalIntervalSeconds:  30,
+		GitHubIntervalSeconds: 300,
+		StaleDays:             30,
+		AbandonedDays:         90,
+	}
+	s, _ := NewServer(cfg)
+
+	snoozeUntil := time.Now().UTC().Add(24 * time.Hour).Truncate(time.Second)
+	if err := s.poller.Snooze("meowtern", snoozeUntil); err != nil {
+		t.Fatalf("Snooze failed: %v", err)
+	}
+
+	export := func() []byte {
+		req := httptest.NewRequest(http.MethodGet, "/api/repos/meowtern/export", nil)
+		w := httptest.NewRecorder()
+		s.handleRepoByName(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("export status = %d, want 200 (%s)", w.Code, w.Body.String())
+		}
+		return w.Body.Bytes()
+	}
+	exported := export()
+
+	var doc RepoExport
+	if err := json.Unmarshal(exported, &doc); err != nil {
+		t.Fatalf("export is not a RepoExport: %v", err)
+	}
+	if doc.Repo.Name != "meowtern" || doc.State == nil || !doc.State.SnoozeUntil.Equal(snoozeUntil) {
+		t.Fatalf("export = %+v, want meowtern with snooze state", doc)
+	}
+
+	// Lose the repo and its state, then import the export
+	if err := cache.WriteRepos(testRepos[1:]); err != nil {
+		t.Fatalf("WriteRepos failed: %v", err)
+	}
+	if err := s.poller.Snooze("meowtern", time.Time{}); err != nil {
+		t.Fatalf("Snooze failed: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/repos/meowtern/import", bytes.NewReader(exported))
+	w := httptest.NewRecorder()
+	s.handleRepoByName(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("import status = %d, want 200 (%s)", w.Code, w.Body.String())
+	}
+
+	if reexported := export(); !bytes.Equal(reexported, exported) {
+		t.Errorf("re-export differs:\n got %s\nwant %s", reexported, exported)
+	}
+
+	// The other repo is untouched
+	repos, _ := cache.ReadRepos()
+	if len(repos) != 2 {
+		t.Errorf("len(repos) = %d, want 2", len(repos))
+	}
+
+	// A document for another repo is rejected
+	req = httptest.NewRequest(http.MethodPost, "/api/repos/other-repo/import", bytes.NewReader(exported))
+	w = httptest.NewRecorder()
+	s.handleRepoByName(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("mismatched import status = %d, want 400", w.Code)
+	}
+}
+
+func TestDeleteRepoThenList(t *testing.T) {
+	tmpDir := t.TempDir()
+	cachePath := filepath.Join(tmpDir, "cache.json")
+	originalCachePath := cache.GetCachePath()
+	defer cache.SetCachePath(originalCachePath)
+	cache.SetCachePath(cachePath)
+
+	testRepos := []model.Repo{
+		{Name: "meowtern", Visibility: model.VisibilityPublic},
+		{Name: "other-repo", Visibility: model.VisibilityPrivate},
+	}
+	if err := cache.WriteRepos(testRepos); err != nil {
+		t.Fatalf("WriteRepos failed: %v", err)
+	}
+
+	cfg := &config.Config{
+		ScanPath:              tmpDir,
+		Port:                  8080,
+		LocalIntervalSeconds:  30,
+		GitHubIntervalSeconds: 300,
+		StaleDays:             30,
+		AbandonedDays:         90,
+	}
+	s, _ := NewServer(cfg)
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/repos/meowtern", nil)
+	w := httptest.NewRecorder()
+	s.handleRepoByName(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("delete status = %d, want 200 (%s)", w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/repos", nil)
+	w = httptest.NewRecorder()
+	s.handleReposList(w, req)
+	var repos []model.Repo
+	if err := json.NewDecoder(w.Body).Decode(&repos); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(repos) != 1 || repos[0].Name != "other-repo" {
+		t.Errorf("repos after delete = %+v, want [other-repo]", repos)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/repos/meowtern", nil)
+	w = httptest.NewRecorder()
+	s.handleRepoByName(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("get deleted repo status = %d, want 404", w.Code)
+	}
+}
+
+func TestDeleteUnknownRepo(t *testing.T) {
+	tmpDir := t.TempDir()
+	cachePath := filepath.Join(tmpDir, "cache.json")
+	originalCachePath := cache.GetCachePath()
+	defer cache.SetCachePath(originalCachePath)
+	cache.SetCachePath(cachePath)
+
+	if err := cache.WriteRepos([]model.Repo{{Name: "meowtern"}}); err != nil {
+		t.Fatalf("WriteRepos failed: %v", err)
+	}
+
+	cfg := &config.Config{
+		ScanPath:              tmpDir,
+		Port:                  8080,
+		LocalIntervalSeconds:  30,
+		GitHubIntervalSeconds: 300,
+		StaleDays:             30,
+		AbandonedDays:         90,
+	}
+	s, _ := NewServer(cfg)
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/repos/nonexistent", nil)
+	w := httptest.NewRecorder()
+	s.handleRepoByName(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", w.Code)
+	}
+
+	repos, err := cache.ReadRepos()
+	if err != nil {
+		t.Fatalf("ReadRepos failed: %v", err)
+	}
+	if len(repos) != 1 {
+		t.Errorf("cache changed after deleting unknown repo: %+v", repos)
+	}
+}
+
+func TestIsNewFilterAndCreatedAtSort(t *testing.T) {
+	now := time.Now().UTC()
+	testRepos := []model.Repo{
+		{Name: "middle", CreatedAt: now.AddDate(0, 0, -3), IsNew: true},
+		{Name: "oldest", CreatedAt: now.AddDate(-2, 0, 0)},
+		{Name: "newest", CreatedAt: now.Add(-time.Hour), IsNew: true},
+	}
+
+	cfg := &config.Config{
+		ScanPath:              "/tmp/test",
+		Port:                  8080,
+		LocalIntervalSeconds:  30,
+		GitHubIntervalSeconds: 300,
+		StaleDays:             30,
+		AbandonedDays:         90,
+	}
+	s, _ := NewServer(cfg)
+
+	names := func(repos []model.Repo) string {
+		var out []string
+		for _, r := range repos {
+			out = append(out, r.Name)
+		}
+		return strings.Join(out, ",")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/repos?isNew=true", nil)
+	if got := names(s.filterRepos(testRepos, req.URL.Query())); got != "middle,newest" {
+		t.Errorf("isNew=true: got %s, want middle,newest", got)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/repos?isNew=false", nil)
+	if got := names(s.filterRepos(testRepos, req.URL.Query())); got != "oldest" {
+		t.Errorf("isNew=false: got %s, want oldest", got)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/repos?sort=createdAt&order=desc", nil)
+	if got := names(s.sortRepos(testRepos, req.URL.Query())); got != "newest,middle,oldest" {
+		t.Errorf("sort=createdAt desc: got %s, want newest,middle,oldest", got)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/repos?sort=createdAt", nil)
+	if got := names(s.sortRepos(testRepos, req.URL.Query())); got != "oldest,middle,newest" {
+		t.Errorf("sort=createdAt asc: got %s, want oldest,middle,newest", got)
+	}
+}
+
+func TestCloneUsesDefaultBranch(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalCachePath := cache.GetCachePath()
+	defer cache.SetCachePath(originalCachePath)
+	cache.SetCachePath(filepath.Join(tmpDir, "cache.json"))
+
+	// lib is as a local poll leaves it, app as caches written before
+	// DefaultBranch existed
+	if err := cache.WriteRepos([]model.Repo{
+		{Name: "app", Branch: "develop"},
+		{Name: "lib", DefaultBranch: "trunk"},
+	}); err != nil {
+		t.Fatalf("WriteRepos failed: %v", err)
+	}
+
+	branches := make(chan string, 3)
+	original := cloneRepo
+	defer func() { cloneRepo = original }()
+	cloneRepo = func(ctx context.Context, owner, name, scanPath string, opts scanner.CloneOptions) <-chan scanner.CloneStatus {
+		branches <- opts.Branch
+		statusChan := make(chan scanner.CloneStatus)
+		close(statusChan)
+		return statusChan
+	}
+
+	cfg := &config.Config{
+		ScanPath:              filepath.Join(tmpDir, "repos"),
+		GitHubOwner:           "alexcatdad",
+		Port:                  8080,
+		LocalIntervalSeconds:  30,
+		GitHubIntervalSeconds: 300,
+		StaleDays:             30,
+		AbandonedDays:         90,
+	}
+	s, _ := NewServer(cfg)
+
+	for _, tt := range []struct {
+		path string
+		want string
+	}{
+		{path: "/api/repos/app/clone", want: "develop"},
+		{path: "/api/repos/app/clone?branch=main", want: "main"},
+		{path: "/api/repos/lib/clone", want: "trunk"},
+	} {
+		req := httptest.NewRequest(http.MethodPost, tt.path, nil)
+		w := httptest.NewRecorder()
+		s.handleRepoByName(w, req)
+		if w.Code != http.StatusAccepted {
+			t.Fatalf("%s: status = %d, want 202", tt.path, w.Code)
+		}
+
+		select {
+		case got := <-branches:
+			if got != tt.want {
+				t.Errorf("%s: clone branch = %q, want %q", tt.path, got, tt.want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("%s: timed out waiting for clone", tt.path)
+		}
+	}
+}
+
+func TestBulkClone(t *testing.T) {
+	tmpDir := t.TempDir()
+	scanPath := filepath.Join(tmpDir, "repos")
+	if err := os.MkdirAll(filepath.Join(scanPath, "already-cloned", ".git"), 0o755); err != nil {
+		t.Fatalf("Failed to create repo dir: %v", err)
+	}
+
+	started := make(chan string, 10)
+	original := cloneRepo
+	defer func() { cloneRepo = original }()
+	cloneRepo = func(ctx context.Context, owner, name, scanPath string, opts scanner.CloneOptions) <-chan scanner.CloneStatus {
+		started <- name
+		statusChan := make(chan scanner.CloneStatus, 2)
+		statusChan <- scanner.CloneStatus{Repo: name, State: scanner.CloneStateStarted}
+		statusChan <- scanner.CloneStatus{Repo: name, State: scanner.CloneStateCompleted}
+		close(statusChan)
+		return statusChan
+	}
+
+	cfg := &config.Config{
+		ScanPath:              scanPath,
+		GitHubOwner:           "alexcatdad",
+		Port:                  8080,
+		LocalIntervalSeconds:  30,
+		GitHubIntervalSeconds: 300,
+		StaleDays:             30,
+		AbandonedDays:         90,
+	}
+	s, _ := NewServer(cfg)
+
+	body := `{"names": ["one", "two", "three", "already-cloned", "two"]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/clone", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	s.handleBulkClone(w, req)
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, want 202 (%s)", w.Code, w.Body.String())
+	}
+
+	var resp BulkCloneResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if strings.Join(resp.Queued, ",") != "one,two,three" {
+		t.Errorf("Queued = %v, want [one two three]", resp.Queued)
+	}
+	if strings.Join(resp.Skipped, ",") != "already-cloned" {
+		t.Errorf("Skipped = %v, want [already-cloned]", resp.Skipped)
+	}
+
+	got := make(map[string]bool)
+	for range 3 {
+		select {
+		case name := <-started:
+			got[name] = true
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for clones; started %v", got)
+		}
+	}
+	for _, name := range []string{"one", "two", "three"} {
+		if !got[name] {
+			t.Errorf("clone of %q not started", name)
+		}
+	}
+	select {
+	case name := <-started:
+		t.Errorf("unexpected extra clone of %q", name)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// Names become paths, so separators are rejected
+	req = httptest.NewRequest(http.MethodPost, "/api/clone", strings.NewReader(`{"names": ["../escape"]}`))
+	w = httptest.NewRecorder()
+	s.handleBulkClone(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("path traversal name: status = %d, want 400", w.Code)
+	}
+}
+
+func TestGzipReposList(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalCachePath := cache.GetCachePath()
+	defer cache.SetCachePath(originalCachePath)
+	cache.SetCachePath(filepath.Join(tmpDir, "cache.json"))
+
+	var testRepos []model.Repo
+	for i := range 50 {
+		testRepos = append(testRepos, model.Repo{
+			Name:        fmt.Sprintf("repo-%02d", i),
+			Description: "A highly compressible description",
+			Visibility:  model.VisibilityPublic,
+		})
+	}
+	if err := cache.WriteRepos(testRepos); err != nil {
+		t.Fatalf("WriteRepos failed: %v", err)
+	}
+
+	cfg := &config.Config{
+		ScanPath:              tmpDir,
+		Port:                  8080,
+		LocalIntervalSeconds:  30,
+		GitHubIntervalSeconds: 300,
+		StaleDays:             30,
+		AbandonedDays:         90,
+	}
+	s, _ := NewServer(cfg)
+	handler := withGzip(http.HandlerFunc(s.handleReposList))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/repos", nil)
+	req.Header.Set("Accept-Encoding", "gzip, deflate")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if got := w.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want gzip", got)
+	}
+	zr, err := gzip.NewReader(w.Body)
+	if err != nil {
+		t.Fatalf("body is not gzip: %v", err)
+	}
+	var repos []model.Repo
+	if err := json.NewDecoder(zr).Decode(&repos); err != nil {
+		t.Fatalf("Failed to decode gzipped body: %v", err)
+	}
+	if len(repos) != len(testRepos) {
+		t.Errorf("len(repos) = %d, want %d", len(repos), len(testRepos))
+	}
+
+	// Without Accept-Encoding the body is plain JSON
+	req = httptest.NewRequest(http.MethodGet, "/api/repos", nil)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if got := w.Header().Get("Content-Encoding"); got != "" {
+		t.Errorf("Content-Encoding without Accept-Encoding = %q, want none", got)
+	}
+	if err := json.NewDecoder(w.Body).Decode(&repos); err != nil {
+		t.Errorf("Failed to decode plain body: %v", err)
+	}
+}
+
+func TestGzipSkipsSmallAndSSEResponses(t *testing.T) {
+	large := strings.Repeat("data: {}\n\n", 500)
+	handler := withGzip(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/small" {
+			w.Write([]byte("OK"))
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte(large))
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/events", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if got := w.Header().Get("Content-Encoding"); got != "" {
+		t.Errorf("SSE Content-Encoding = %q, want none", got)
+	}
+	if w.Body.String() != large {
+		t.Error("SSE body was modified")
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/small", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if got := w.Header().Get("Content-Encoding"); got != "" {
+		t.Errorf("small response Content-Encoding = %q, want none", got)
+	}
+	if w.Body.String() != "OK" {
+		t.Errorf("small body = %q, want OK", w.Body.String())
+	}
+}
+
+func TestReposETag(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalCachePath := cache.GetCachePath()
+	defer cache.SetCachePath(originalCachePath)
+	cache.SetCachePath(filepath.Join(tmpDir, "cache.json"))
+
+	if err := cache.WriteRepos([]model.Repo{{Name: "meowtern"}, {Name: "other-repo"}}); err != nil {
+		t.Fatalf("WriteRepos failed: %v", err)
+	}
+
+	cfg := &config.Config{
+		ScanPath:              tmpDir,
+		Port:                  8080,
+		LocalIntervalSeconds:  30,
+		GitHubIntervalSeconds: 300,
+		StaleDays:             30,
+		AbandonedDays:         90,
+	}
+	s, _ := NewServer(cfg)
+
+	for _, path := range []string{"/api/repos", "/api/repos/meowtern"} {
+		get := func(ifNoneMatch string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			if ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", ifNoneMatch)
+			}
+			w := httptest.NewRecorder()
+			if path == "/api/repos" {
+				s.handleReposList(w, req)
+			} else {
+				s.handleRepoByName(w, req)
+			}
+			return w
+		}
+
+		first, second := get(""), get("")
+		etag := first.Header().Get("ETag")
+		if etag == "" {
+			t.Fatalf("%s: no ETag header", path)
+		}
+		if got := second.Header().Get("ETag"); got != etag {
+			t.Errorf("%s: ETag changed across identical requests: %s then %s", path, etag, got)
+		}
+
+		w := get(etag)
+		if w.Code != http.StatusNotModified {
+			t.Errorf("%s: matching If-None-Match status = %d, want 304", path, w.Code)
+		}
+		if w.Body.Len() != 0 {
+			t.Errorf("%s: 304 response has a body: %q", path, w.Body.String())
+		}
+
+		w = get(`W/"stale"`)
+		if w.Code != http.StatusOK {
+			t.Errorf("%s: non-matching If-None-Match status = %d, want 200", path, w.Code)
+		}
+	}
+
+	// A change to the list changes the ETag
+	req := httptest.NewRequest(http.MethodGet, "/api/repos", nil)
+	w := httptest.NewRecorder()
+	s.handleReposList(w, req)
+	before := w.Header().Get("ETag")
+	if err := cache.WriteRepos([]model.Repo{{Name: "meowtern"}}); err != nil {
+		t.Fatalf("WriteRepos failed: %v", err)
+	}
+	w = httptest.NewRecorder()
+	s.handleReposList(w, req)
+	if w.Header().Get("ETag") == before {
+		t.Error("ETag unchanged after the repo list changed")
+	}
+}
+
+func TestStats(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalCachePath := cache.GetCachePath()
+	defer cache.SetCachePath(originalCachePath)
+	cache.SetCachePath(filepath.Join(tmpDir, "cache.json"))
+
+	testRepos := []model.Repo{
+		{Name: "a", Visibility: model.VisibilityPublic, Language: "Go", Lifecycle: model.LifecycleOngoing, Cloned: true, Dirty: true, OpenPRs: 2},
+		{Name: "b", Visibility: model.VisibilityPublic, Language: "Go", Lifecycle: model.LifecycleStale, Cloned: true},
+		{Name: "c", Visibility: model.VisibilityPrivate, Language: "TypeScript", Lifecycle: model.LifecycleOngoing, OpenPRs: 1},
+		{Name: "d", Visibility: model.VisibilityPrivate, Lifecycle: model.LifecycleAbandoned},
+	}
+	if err := cache.WriteRepos(testRepos); err != nil {
+		t.Fatalf("WriteRepos failed: %v", err)
+	}
+
+	cfg := &config.Config{
+		ScanPath:              tmpDir,
+		Port:                  8080,
+		LocalIntervalSeconds:  30,
+		GitHubIntervalSeconds: 300,
+		StaleDays:             30,
+		AbandonedDays:         90,
+	}
+	s, _ := NewServer(cfg)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/stats", nil)
+	w := httptest.NewRecorder()
+	s.handleStats(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+
+	var stats RepoStats
+	if err := json.NewDecoder(w.Body).Decode(&stats); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	counts := map[string][2]int{
+		"total":       {stats.Total, 4},
+		"cloned":      {stats.Cloned, 2},
+		"notCloned":   {stats.NotCloned, 2},
+		"dirty":       {stats.Dirty, 1},
+		"withOpenPRs": {stats.WithOpenPRs, 2},
+	}
+	for name, c := range counts {
+		if c[0] != c[1] {
+			t.Errorf("%s = %d, want %d", name, c[0], c[1])
+		}
+	}
+
+	buckets := map[string]struct {
+		got  map[string]int
+		want map[string]int
+	}{
+		"byLifecycle":  {stats.ByLifecycle, map[string]int{"ongoing": 2, "stale": 1, "abandoned": 1}},
+		"byLanguage":   {stats.ByLanguage, map[string]int{"Go": 2, "TypeScript": 1, "unknown": 1}},
+		"byVisibility": {stats.ByVisibility, map[string]int{"public": 2, "private": 2}},
+	}
+	for name, b := range buckets {
+		if len(b.got) != len(b.want) {
+			t.Errorf("%s = %v, want %v", name, b.got, b.want)
+			continue
+		}
+		for key, want := range b.want {
+			if b.got[key] != want {
+				t.Errorf("%s[%s] = %d, want %d", name, key, b.got[key], want)
+			}
+		}
+	}
+}
+
+// TestRepoStateTagsAndFilter tests that PUT /api/repos/:name/state stores
+// tags and a note on the repo, and that ?tag= filters by them.
+func TestRepoStateTagsAndFilter(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalCachePath := cache.GetCachePath()
+	defer cache.SetCachePath(originalCachePath)
+	cache.SetCachePath(filepath.Join(tmpDir, "cache.json"))
+
+	testRepos := []model.Repo{{Name: "catscan"}, {Name: "dotfiles"}, {Name: "scratch"}}
+	if err := cache.WriteRepos(testRepos); err != nil {
+		t.Fatalf("WriteRepos failed: %v", err)
+	}
+
+	cfg := &config.Config{
+		ScanPath:              tmpDir,
+		Port:                  8080,
+		LocalIntervalSeconds:  30,
+		GitHubIntervalSeconds: 300,
+		StaleDays:             30,
+		AbandonedDays:         90,
+	}
+	s, _ := NewServer(cfg)
+
+	put := func(name, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, "/api/repos/"+name+"/state", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		s.handleRepoByName(w, req)
+		return w
+	}
+
+	if w := put("catscan", `{"tags":["migrate-to-v2"," work ","work",""],"note":"Move to the new API"}`); w.Code != http.StatusOK {
+		t.Fatalf("PUT status = %d, want 200 (%s)", w.Code, w.Body.String())
+	}
+	if w := put("scratch", `{"tags":["archive-me"]}`); w.Code != http.StatusOK {
+		t.Fatalf("PUT status = %d, want 200 (%s)", w.Code, w.Body.String())
+	}
+	if w := put("missing", `{"tags":["x"]}`); w.Code != http.StatusNotFound {
+		t.Errorf("PUT unknown repo status = %d, want 404", w.Code)
+	}
+
+	entry := s.poller.RepoState("catscan")
+	if entry == nil || !slices.Equal(entry.Tags, []string{"migrate-to-v2", "work"}) || entry.Note != "Move to the new API" {
+		t.Fatalf("catscan state = %+v, want tags [migrate-to-v2 work] and note", entry)
+	}
+
+	list := func(query string) []string {
+		req := httptest.NewRequest(http.MethodGet, "/api/repos?"+query, nil)
+		w := httptest.NewRecorder()
+		s.handleReposList(w, req)
+		var repos []model.Repo
+		if err := json.Unmarshal(w.Body.Bytes(), &repos); err != nil {
+			t.Fatalf("Failed to decode repos: %v", err)
+		}
+		var names []string
+		for _, repo := range repos {
+			names = append(names, repo.Name)
+		}
+		return names
+	}
+
+	if got := list("tag=work"); !slices.Equal(got, []string{"catscan"}) {
+		t.Errorf("tag=work = %v, want [catscan]", got)
+	}
+	if got := list("tag=work,archive-me"); !slices.Equal(got, []string{"catscan", "scratch"}) {
+		t.Errorf("tag=work,archive-me = %v, want [catscan scratch]", got)
+	}
+	if got := list("tag=unused"); len(got) != 0 {
+		t.Errorf("tag=unused = %v, want none", got)
+	}
+}
+
+// TestStartupSummary tests that the startup summary names the key config values.
+func TestStartupSummary(t *testing.T) {
+	cfg := &config.Config{
+		ScanPath:              "/home/cat/REPOS",
+		GitHubOwner:           "alexcatdad",
+		IncludeAllOrgs:        true,
+		Port:                  7700,
+		LocalIntervalSeconds:  60,
+		GitHubIntervalSeconds: 300,
+		NotificationMode:      config.NotificationModeWebhook,
+	}
+
+	summary := startupSummary(cfg, true)
+	for _, want := range []string{
+		"scanPath=/home/cat/REPOS",
+		"owner=alexcatdad+orgs",
+		"port=7700",
+		"localInterval=60s",
+		"githubInterval=300s",
+		"notifications=webhook",
+		"gh=true",
+	} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("summary %q missing %q", summary, want)
+		}
+	}
+	if strings.Contains(summary, "\n") {
+		t.Errorf("summary %q spans multiple lines", summary)
+	}
+
+	// An unset notification mode reports the desktop default
+	cfg.NotificationMode = ""
+	if summary := startupSummary(cfg, false); !strings.Contains(summary, "notifications=desktop") || !strings.Contains(summary, "gh=false") {
+		t.Errorf("summary %q, want desktop notifications and gh=false", summary)
+	}
+}
+
+// TestHideRepo tests that a hidden repo leaves the default list, is still
+// listed with includeHidden=true, and comes back when unhidden.
+func TestHideRepo(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalCachePath := cache.GetCachePath()
+	defer cache.SetCachePath(originalCachePath)
+	cache.SetCachePath(filepath.Join(tmpDir, "cache.json"))
+
+	testRepos := []model.Repo{{Name: "catscan"}, {Name: "old-experiment"}}
+	if err := cache.WriteRepos(testRepos); err != nil {
+		t.Fatalf("WriteRepos failed: %v", err)
+	}
+
+	cfg := &config.Config{
+		ScanPath:              tmpDir,
+		Port:                  8080,
+		LocalIntervalSeconds:  30,
+		GitHubIntervalSeconds: 300,
+		StaleDays:             30,
+		AbandonedDays:         90,
+	}
+	s, _ := NewServer(cfg)
+
+	post := func(path string) int {
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		w := httptest.NewRecorder()
+		s.handleRepoByName(w, req)
+		return w.Code
+	}
+	list := func(query string) []string {
+		req := httptest.NewRequest(http.MethodGet, "/api/repos?"+query, nil)
+		w := httptest.NewRecorder()
+		s.handleReposList(w, req)
+		var repos []model.Repo
+		if err := json.Unmarshal(w.Body.Bytes(), &repos); err != nil {
+			t.Fatalf("Failed to decode repos: %v", err)
+		}
+		var names []string
+		for _, repo := range repos {
+			names = append(names, repo.Name)
+		}
+		return names
+	}
+
+	if code := post("/api/repos/old-experiment/hide"); code != http.StatusOK {
+		t.Fatalf("hide status = %d, want 200", code)
+	}
+	if entry := s.poller.RepoState("old-experiment"); entry == nil || !entry.Hidden {
+		t.Error("hidden flag should be persisted in state")
+	}
+	if got := list(""); !slices.Equal(got, []string{"catscan"}) {
+		t.Errorf("default list = %v, want [catscan]", got)
+	}
+	if got := list("includeHidden=true"); !slices.Equal(got, []string{"catscan", "old-experiment"}) {
+		t.Errorf("includeHidden list = %v, want [catscan old-experiment]", got)
+	}
+
+	if code := post("/api/repos/old-experiment/unhide"); code != http.StatusOK {
+		t.Fatalf("unhide status = %d, want 200", code)
+	}
+	if got := list(""); !slices.Equal(got, []string{"catscan", "old-experiment"}) {
+		t.Errorf("list after unhide = %v, want [catscan old-experiment]", got)
+	}
+
+	if code := post("/api/repos/missing/hide"); code != http.StatusNotFound {
+		t.Errorf("hide unknown repo status = %d, want 404", code)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/api/repos/catscan/hide", nil)
+	w := httptest.NewRecorder()
+	s.handleRepoByName(w, req)
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("GET hide status = %d, want 405", w.Code)
+	}
+}
+
+// TestRepoNameValidation tests that repo names that could escape the scan
+// path are rejected with 400 before any clone starts.
+func TestRepoNameValidation(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalCachePath := cache.GetCachePath()
+	defer cache.SetCachePath(originalCachePath)
+	cache.SetCachePath(filepath.Join(tmpDir, "cache.json"))
+
+	if err := cache.WriteRepos([]model.Repo{{Name: "app"}}); err != nil {
+		t.Fatalf("WriteRepos failed: %v", err)
+	}
+
+	original := cloneRepo
+	defer func() { cloneRepo = original }()
+	cloneRepo = func(ctx context.Context, owner, name, scanPath string, opts scanner.CloneOptions) <-chan scanner.CloneStatus {
+		t.Errorf("clone started for %q", name)
+		statusChan := make(chan scanner.CloneStatus)
+		close(statusChan)
+		return statusChan
+	}
+
+	cfg := &config.Config{
+		ScanPath:              filepath.Join(tmpDir, "repos"),
+		GitHubOwner:           "alexcatdad",
+		Port:                  8080,
+		LocalIntervalSeconds:  30,
+		GitHubIntervalSeconds: 300,
+		StaleDays:             30,
+		AbandonedDays:         90,
+	}
+	s, _ := NewServer(cfg)
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+	}{
+		{name: "clone traversal", method: http.MethodPost, target: "/api/repos/..%2Fescape/clone"},
+		{name: "clone with slash", method: http.MethodPost, target: "/api/repos/org%2Fapp/clone"},
+		{name: "clone with backslash", method: http.MethodPost, target: "/api/repos/..%5Cescape/clone"},
+		{name: "get traversal", method: http.MethodGet, target: "/api/repos/..%2Fescape"},
+		{name: "get with slash", method: http.MethodGet, target: "/api/repos/org%2Fapp"},
+		{name: "delete traversal", method: http.MethodDelete, target: "/api/repos/.."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, nil)
+			w := httptest.NewRecorder()
+			s.handleRepoByName(w, req)
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want 400 (%s)", w.Code, w.Body.String())
+			}
+		})
+	}
+
+	// A normal name passes validation
+	req := httptest.NewRequest(http.MethodGet, "/api/repos/app", nil)
+	w := httptest.NewRecorder()
+	s.handleRepoByName(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("GET app status = %d, want 200", w.Code)
+	}
+	for _, name := range []string{"app", ".github", "my_repo-2.0", "foo..bar", "..hidden"} {
+		if err := validateRepoName(name); err != nil {
+			t.Errorf("validateRepoName(%q) = %v, want nil", name, err)
+		}
+	}
+	for _, name := range []string{"", ".", "..", "a/b", `a\b`, "-x", "--upload-pack=evil"} {
+		if err := validateRepoName(name); err == nil {
+			t.Errorf("validateRepoName(%q) = nil, want error", name)
+		}
+	}
+}
+
+// TestRepoNamedAfterSubRoute tests that repos named like a sub-route are
+// routed by position rather than by suffix.
+func TestRepoNamedAfterSubRoute(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalCachePath := cache.GetCachePath()
+	defer cache.SetCachePath(originalCachePath)
+	cache.SetCachePath(filepath.Join(tmpDir, "cache.json"))
+
+	if err := cache.WriteRepos([]model.Repo{{Name: "state"}, {Name: "pull"}}); err != nil {
+		t.Fatalf("WriteRepos failed: %v", err)
+	}
+
+	cfg := &config.Config{
+		ScanPath:              filepath.Join(tmpDir, "repos"),
+		GitHubOwner:           "alexcatdad",
+		Port:                  8080,
+		LocalIntervalSeconds:  30,
+		GitHubIntervalSeconds: 300,
+		StaleDays:             30,
+		AbandonedDays:         90,
+	}
+	s, _ := NewServer(cfg)
+
+	for _, name := range []string{"state", "pull"} {
+		req := httptest.NewRequest(http.MethodGet, "/api/repos/"+name, nil)
+		w := httptest.NewRecorder()
+		s.handleRepoByName(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("GET %s status = %d, want 200 (%s)", name, w.Code, w.Body.String())
+		}
+		var repo model.Repo
+		if err := json.NewDecoder(w.Body).Decode(&repo); err != nil || repo.Name != name {
+			t.Errorf("GET %s = %q (%v), want %s", name, repo.Name, err, name)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodPut, "/api/repos/state/state", strings.NewReader(`{"tags": ["meta"]}`))
+	w := httptest.NewRecorder()
+	s.handleRepoByName(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("PUT state/state status = %d, want 200 (%s)", w.Code, w.Body.String())
+	}
+	if state := s.poller.RepoState("state"); state == nil || !slices.Equal(state.Tags, []string{"meta"}) {
+		t.Errorf("state of repo state = %+v, want tags [meta]", state)
+	}
+
+	// Unknown sub-routes still make the name invalid
+	req = httptest.NewRequest(http.MethodGet, "/api/repos/state/bogus", nil)
+	w = httptest.NewRecorder()
+	s.handleRepoByName(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("GET state/bogus status = %d, want 400", w.Code)
+	}
+}
+
+// TestAuthToken tests that a configured AuthToken is required as a bearer
+// token on API routes, and that the API is open without one.
+func TestAuthToken(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalCachePath := cache.GetCachePath()
+	defer cache.SetCachePath(originalCachePath)
+	cache.SetCachePath(filepath.Join(tmpDir, "cache.json"))
+
+	cfg := &config.Config{
+		ScanPath:              tmpDir,
+		Port:                  8080,
+		LocalIntervalSeconds:  30,
+		GitHubIntervalSeconds: 300,
+		StaleDays:             30,
+		AbandonedDays:         90,
+		AuthToken:             "s3cret",
+	}
+	s, _ := NewServer(cfg)
+	mux := http.NewServeMux()
+	s.setupRoutes(mux)
+	handler := s.withAuth(mux)
+
+	get := func(target, authorization string) int {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	tests := []struct {
+		name          string
+		target        string
+		authorization string
+		want          int
+	}{
+		{name: "missing token", target: "/api/stats", want: http.StatusUnauthorized},
+		{name: "wrong token", target: "/api/stats", authorization: "Bearer nope", want: http.StatusUnauthorized},
+		{name: "not a bearer token", target: "/api/stats", authorization: "s3cret", want: http.StatusUnauthorized},
+		{name: "correct token", target: "/api/stats", authorization: "Bearer s3cret", want: http.StatusOK},
+		{name: "static assets stay open", target: "/", want: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := get(tt.target, tt.authorization); got != tt.want {
+				t.Errorf("status = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	// Without a configured token the API is open
+	s.mu.Lock()
+	s.cfg.AuthToken = ""
+	s.mu.Unlock()
+	if got := get("/api/stats", ""); got != http.StatusOK {
+		t.Errorf("no auth configured: status = %d, want 200", got)
+	}
+}
+
+// TestCloneAlreadyInProgress tests that clone and pull requests for a repo
+// whose clone is still running are rejected, even once its directory exists.
+func TestCloneAlreadyInProgress(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalCachePath := cache.GetCachePath()
+	defer cache.SetCachePath(originalCachePath)
+	cache.SetCachePath(filepath.Join(tmpDir, "cache.json"))
+
+	scanPath := filepath.Join(tmpDir, "repos")
+	started := make(chan struct{})
+	release := make(chan struct{})
+	original := cloneRepo
+	defer func() { cloneRepo = original }()
+	cloneRepo = func(ctx context.Context, owner, name, scanPath string, opts scanner.CloneOptions) <-chan scanner.CloneStatus {
+		statusChan := make(chan scanner.CloneStatus)
+		go func() {
+			defer close(statusChan)
+			os.MkdirAll(filepath.Join(scanPath, name, ".git"), 0o755)
+			close(started)
+			<-release
+		}()
+		return statusChan
+	}
+
+	cfg := &config.Config{
+		ScanPath:              scanPath,
+		GitHubOwner:           "alexcatdad",
+		Port:                  8080,
+		LocalIntervalSeconds:  30,
+		GitHubIntervalSeconds: 300,
+		StaleDays:             30,
+		AbandonedDays:         90,
+	}
+	s, _ := NewServer(cfg)
+
+	clone := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/repos/app/clone", nil)
+		w := httptest.NewRecorder()
+		s.handleRepoByName(w, req)
+		return w
+	}
+
+	if w := clone(); w.Code != http.StatusAccepted {
+		t.Fatalf("first clone status = %d, want 202", w.Code)
+	}
+	<-started
+
+	w := clone()
+	if w.Code != http.StatusConflict || !strings.Contains(w.Body.String(), "in progress") {
+		t.Errorf("second clone = %d %s, want 409 clone already in progress", w.Code, w.Body.String())
+	}
+
+	// The directory exists now, but pulling into it would race the clone
+	req := httptest.NewRequest(http.MethodPost, "/api/repos/app/pull", nil)
+	w = httptest.NewRecorder()
+	s.handleRepoByName(w, req)
+	if w.Code != http.StatusConflict {
+		t.Errorf("pull during clone = %d %s, want 409", w.Code, w.Body.String())
+	}
+
+	close(release)
+	for s.poller.IsCloning("app") {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if w := clone(); w.Code != http.StatusConflict || !strings.Contains(w.Body.String(), "already cloned") {
+		t.Errorf("clone after finish = %d %s, want 409 already cloned", w.Code, w.Body.String())
+	}
+}
+
+// TestConcurrentCloneRequests tests that two clone requests for the same
+// repo fired at once start exactly one clone and reject the other with 409.
+func TestConcurrentCloneRequests(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalCachePath := cache.GetCachePath()
+	defer cache.SetCachePath(originalCachePath)
+	cache.SetCachePath(filepath.Join(tmpDir, "cache.json"))
+
+	var clones atomic.Int32
+	release := make(chan struct{})
+	original := cloneRepo
+	defer func() { cloneRepo = original }()
+	cloneRepo = func(ctx context.Context, owner, name, scanPath string, opts scanner.CloneOptions) <-chan scanner.CloneStatus {
+		clones.Add(1)
+		statusChan := make(chan scanner.CloneStatus)
+		go func() {
+			defer close(statusChan)
+			<-release
+		}()
+		return statusChan
+	}
+
+	cfg := &config.Config{
+		ScanPath:              filepath.Join(tmpDir, "repos"),
+		Port:                  8080,
+		LocalIntervalSeconds:  30,
+		GitHubIntervalSeconds: 300,
+		StaleDays:             30,
+		AbandonedDays:         90,
+	}
+	s, _ := NewServer(cfg)
+
+	var wg sync.WaitGroup
+	codes := make([]int, 2)
+	start := make(chan struct{})
+	for i := range codes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			req := httptest.NewRequest(http.MethodPost, "/api/repos/app/clone", nil)
+			w := httptest.NewRecorder()
+			s.handleRepoByName(w, req)
+			codes[i] = w.Code
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	slices.Sort(codes)
+	if codes[0] != http.StatusAccepted || codes[1] != http.StatusConflict {
+		t.Errorf("status codes = %v, want one 202 and one 409", codes)
+	}
+
+	close(release)
+	for s.poller.IsCloning("app") {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := clones.Load(); got != 1 {
+		t.Errorf("clones run = %d, want 1", got)
+	}
+}
+
+// TestClonesEndpoint tests that GET /api/clones lists a failed clone with
+// its error after the clone finishes.
+func TestClonesEndpoint(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalCachePath := cache.GetCachePath()
+	defer cache.SetCachePath(originalCachePath)
+	cache.SetCachePath(filepath.Join(tmpDir, "cache.json"))
+
+	original := cloneRepo
+	defer func() { cloneRepo = original }()
+	cloneRepo = func(ctx context.Context, owner, name, scanPath string, opts scanner.CloneOptions) <-chan scanner.CloneStatus {
+		statusChan := make(chan scanner.CloneStatus, 2)
+		statusChan <- scanner.CloneStatus{Repo: name, State: scanner.CloneStateStarted}
+		statusChan <- scanner.CloneStatus{Repo: name, State: scanner.CloneStateError, Error: "clone failed: repository not found"}
+		close(statusChan)
+		return statusChan
+	}
+
+	cfg := &config.Config{
+		ScanPath:              filepath.Join(tmpDir, "repos"),
+		Port:                  8080,
+		LocalIntervalSeconds:  30,
+		GitHubIntervalSeconds: 300,
+		StaleDays:             30,
+		AbandonedDays:         90,
+	}
+	s, _ := NewServer(cfg)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/repos/ghost/clone", nil)
+	w := httptest.NewRecorder()
+	s.handleRepoByName(w, req)
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("clone status = %d, want 202", w.Code)
+	}
+	for s.poller.IsCloning("ghost") {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/clones", nil)
+	w = httptest.NewRecorder()
+	s.handleClones(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	var clones []poller.CloneRecord
+	if err := json.NewDecoder(w.Body).Decode(&clones); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(clones) != 1 || clones[0].Repo != "ghost" || clones[0].State != scanner.CloneStateError || !strings.Contains(clones[0].Error, "repository not found") {
+		t.Errorf("clones = %+v, want the failed ghost clone with its error", clones)
+	}
+}
+
+// TestListenHonorsBindHost tests that the listener binds to the configured
+// host, defaulting to loopback.
+func TestListenHonorsBindHost(t *testing.T) {
+	cfg := &config.Config{
+		ScanPath:              t.TempDir(),
+		Port:                  8080,
+		LocalIntervalSeconds:  30,
+		GitHubIntervalSeconds: 300,
+		StaleDays:             30,
+		AbandonedDays:         90,
+	}
+	s, err := NewServer(cfg)
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+
+	tests := []struct {
+		bindHost string
+		want     func(net.IP) bool
+	}{
+		{bindHost: "", want: net.IP.IsLoopback},
+		{bindHost: "0.0.0.0", want: net.IP.IsUnspecified},
+	}
+	for _, tt := range tests {
+		s.cfg.BindHost = tt.bindHost
+		s.cfg.Port = 0
+		listener, err := s.listen()
+		if err != nil {
+			t.Fatalf("listen(%q) failed: %v", tt.bindHost, err)
+		}
+		host, _, _ := net.SplitHostPort(listener.Addr().String())
+		listener.Close()
+		if !tt.want(net.ParseIP(host)) {
+			t.Errorf("bindHost %q: listener host = %s", tt.bindHost, host)
+		}
+	}
+
+	if got := listenAddr(&config.Config{BindHost: "::1", Port: 7700}); got != "[::1]:7700" {
+		t.Errorf("listenAddr(::1) = %s, want [::1]:7700", got)
+	}
+
+	cfg.Port = 8080
+	cfg.BindHost = "my laptop"
+	if err := config.Validate(cfg); err == nil || !strings.Contains(err.Error(), "bindHost") {
+		t.Errorf("Validate(bindHost=%q) = %v, want bindHost error", cfg.BindHost, err)
+	}
+}
+
+// TestInstanceName tests that the configured instance name is reported by
+// health, version, and the SSE connected event.
+func TestInstanceName(t *testing.T) {
+	cfg := &config.Config{
+		ScanPath:              t.TempDir(),
+		Port:                  8080,
+		LocalIntervalSeconds:  30,
+		GitHubIntervalSeconds: 300,
+		StaleDays:             30,
+		AbandonedDays:         90,
+		InstanceName:          "work",
+	}
+	s, _ := NewServer(cfg)
+
+	for _, tt := range []struct {
+		path    string
+		handler http.HandlerFunc
+		field   string
+	}{
+		{path: "/api/health", handler: s.handleHealth, field: "InstanceName"},
+		{path: "/api/version", handler: s.handleVersion, field: "instanceName"},
+	} {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		w := httptest.NewRecorder()
+		tt.handler(w, req)
+		var body map[string]interface{}
+		if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+			t.Fatalf("%s: failed to decode response: %v", tt.path, err)
+		}
+		if body[tt.field] != "work" {
+			t.Errorf("%s %s = %v, want work", tt.path, tt.field, body[tt.field])
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.hub.Run(ctx)
+
+	srv := httptest.NewServer(http.HandlerFunc(s.handleEvents))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("connecting: %v", err)
+	}
+	defer resp.Body.Close()
+
+	typ, payload := readEvent(t, bufio.NewReader(resp.Body))
+	if typ != "connected" {
+		t.Fatalf("first event = %s, want connected", typ)
+	}
+	var connected map[string]string
+	if err := json.Unmarshal([]byte(payload), &connected); err != nil {
+		t.Fatalf("decoding connected event: %v", err)
+	}
+	if connected["instanceName"] != "work" || connected["clientId"] == "" {
+		t.Errorf("connected = %v, want clientId and instanceName work", connected)
 	}
-	return -1
 }
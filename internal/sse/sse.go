@@ -7,41 +7,153 @@ package sse
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 	"sync"
 )
 
 // Event represents a server-sent event.
+// ID is assigned by the hub when the event is broadcast; events sent
+// directly to a single client have no ID and are never replayed.
 type Event struct {
+	ID   uint64      `json:"id,omitempty"`
 	Type string      `json:"type"`
 	Data interface{} `json:"data"`
 }
 
+// DefaultHistorySize is the number of recent broadcast events kept for
+// Last-Event-ID replay when no size is configured.
+const DefaultHistorySize = 100
+
+// DefaultClientBufferSize is the number of events queued per client when no
+// size is configured. A client whose queue fills is dropped.
+const DefaultClientBufferSize = 10
+
+// ErrTooManyClients is returned by Register when the hub already has its
+// maximum number of clients.
+var ErrTooManyClients = errors.New("too many SSE clients")
+
+// ErrHubClosed is returned by Register once the hub's Run loop has stopped.
+var ErrHubClosed = errors.New("SSE hub is shut down")
+
 // Client represents a connected SSE client.
 type Client struct {
 	ID     string
 	Chan   chan Event
 	Ctx    context.Context
 	Cancel context.CancelFunc
+
+	// EventTypes, when non-nil, limits the events delivered to this client
+	// to these types (see Client.filter). Nil delivers every event.
+	EventTypes map[string]bool
+
+	// heartbeat signals the handler to write a keepalive comment.
+	// Kept separate from Chan so heartbeats never fill or drop a client.
+	heartbeat chan struct{}
+}
+
+// changesEvent is the batched event whose entries each carry their own
+// "type"; a client filtering by type can subscribe to those entry types.
+const changesEvent = "changes"
+
+// filter reports whether the client wants event, returning the event to
+// deliver. A changes batch is delivered to a client subscribed to "changes",
+// or narrowed to the entries whose types the client subscribed to.
+func (c *Client) filter(event Event) (Event, bool) {
+	if c.EventTypes == nil || c.EventTypes[event.Type] {
+		return event, true
+	}
+	if event.Type != changesEvent {
+		return event, false
+	}
+
+	entries, ok := event.Data.([]map[string]interface{})
+	if !ok {
+		return event, false
+	}
+	var wanted []map[string]interface{}
+	for _, entry := range entries {
+		if entryType, _ := entry["type"].(string); c.EventTypes[entryType] {
+			wanted = append(wanted, entry)
+		}
+	}
+	if len(wanted) == 0 {
+		return event, false
+	}
+	event.Data = wanted
+	return event, true
 }
 
 // Hub manages connected SSE clients and broadcasts events.
 type Hub struct {
 	clients    map[string]*Client
 	mu         sync.RWMutex
-	register   chan *Client
+	register   chan registration
 	unregister chan string
 	broadcast  chan Event
+
+	// done is closed when Run returns, so Register and Unregister don't
+	// block on a loop that is gone
+	done chan struct{}
+
+	clientBufferSize int
+	maxClients       int
+
+	// Recent broadcast events for Last-Event-ID replay, oldest first
+	lastID      uint64
+	history     []Event
+	historySize int
+}
+
+// registration is a Register call waiting for the event loop's answer.
+type registration struct {
+	client *Client
+	result chan error
 }
 
-// NewHub creates a new SSE hub.
+// HubOptions configures a Hub.
+type HubOptions struct {
+	// HistorySize is the number of recent events kept for replay.
+	// Zero or negative uses DefaultHistorySize.
+	HistorySize int
+
+	// ClientBufferSize is the number of events queued per client created
+	// by NewHandler. Zero or negative uses DefaultClientBufferSize.
+	ClientBufferSize int
+
+	// MaxClients caps the number of connected clients. Zero or negative
+	// means no limit.
+	MaxClients int
+}
+
+// NewHub creates a new SSE hub with default options.
 func NewHub() *Hub {
+	return NewHubWithOptions(HubOptions{})
+}
+
+// NewHubWithOptions creates a new SSE hub with the given options.
+func NewHubWithOptions(opts HubOptions) *Hub {
+	historySize := opts.HistorySize
+	if historySize <= 0 {
+		historySize = DefaultHistorySize
+	}
+	clientBufferSize := opts.ClientBufferSize
+	if clientBufferSize <= 0 {
+		clientBufferSize = DefaultClientBufferSize
+	}
+
 	return &Hub{
-		clients:    make(map[string]*Client),
-		register:   make(chan *Client),
-		unregister: make(chan string),
-		broadcast:  make(chan Event, 100), // Buffered to prevent blocking
+		clients:          make(map[string]*Client),
+		register:         make(chan registration),
+		unregister:       make(chan string),
+		broadcast:        make(chan Event, 100), // Buffered to prevent blocking
+		done:             make(chan struct{}),
+		clientBufferSize: clientBufferSize,
+		maxClients:       max(opts.MaxClients, 0),
+		history:          make([]Event, 0, historySize),
+		historySize:      historySize,
 	}
 }
 
@@ -58,11 +170,17 @@ func (h *Hub) Run(ctx context.Context) {
 			}
 			h.clients = make(map[string]*Client)
 			h.mu.Unlock()
+			close(h.done)
 			return
 
-		case client := <-h.register:
+		case reg := <-h.register:
 			h.mu.Lock()
-			h.clients[client.ID] = client
+			if h.maxClients > 0 && len(h.clients) >= h.maxClients {
+				reg.result <- ErrTooManyClients
+			} else {
+				h.clients[reg.client.ID] = reg.client
+				reg.result <- nil
+			}
 			h.mu.Unlock()
 
 		case id := <-h.unregister:
@@ -79,31 +197,59 @@ func (h *Hub) Run(ctx context.Context) {
 	}
 }
 
-// Register registers a new SSE client.
-func (h *Hub) Register(client *Client) {
-	h.register <- client
+// Register registers a new SSE client. It returns ErrTooManyClients,
+// leaving the client unregistered, when the hub is at MaxClients, and
+// ErrHubClosed once the hub has shut down.
+func (h *Hub) Register(client *Client) error {
+	reg := registration{client: client, result: make(chan error, 1)}
+	select {
+	case h.register <- reg:
+		return <-reg.result
+	case <-h.done:
+		return ErrHubClosed
+	}
 }
 
-// Unregister unregisters an SSE client by ID.
+// Unregister unregisters an SSE client by ID. It is a no-op once the hub
+// has shut down, since shutdown already dropped every client.
 func (h *Hub) Unregister(id string) {
-	h.unregister <- id
+	select {
+	case h.unregister <- id:
+	case <-h.done:
+	}
 }
 
-// Broadcast broadcasts an event to all connected clients.
+// Broadcast broadcasts an event to all connected clients. Once the hub has
+// shut down the event is dropped, so callers never block on a full buffer
+// that nothing drains.
 func (h *Hub) Broadcast(eventType string, data interface{}) {
-	h.broadcast <- Event{
-		Type: eventType,
-		Data: data,
+	select {
+	case h.broadcast <- Event{Type: eventType, Data: data}:
+	case <-h.done:
 	}
 }
 
-// broadcastEvent sends an event to all connected clients.
+// broadcastEvent assigns the event an ID, records it in the replay history,
+// and sends it to all connected clients.
 // It does not block if a client's channel is full.
 func (h *Hub) broadcastEvent(event Event) {
-	h.mu.RLock()
-	defer h.mu.RUnlock()
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.lastID++
+	event.ID = h.lastID
+	if len(h.history) >= h.historySize {
+		// Drop the oldest event
+		copy(h.history, h.history[1:])
+		h.history = h.history[:len(h.history)-1]
+	}
+	h.history = append(h.history, event)
 
 	for id, client := range h.clients {
+		event, ok := client.filter(event)
+		if !ok {
+			continue
+		}
 		select {
 		case client.Chan <- event:
 			// Event sent successfully
@@ -115,6 +261,22 @@ func (h *Hub) broadcastEvent(event Event) {
 	}
 }
 
+// Heartbeat asks every connected client to write a keepalive comment.
+// Heartbeats bypass the event channel and history, and a pending heartbeat
+// is coalesced rather than queued, so a slow client is never dropped for it.
+func (h *Hub) Heartbeat() {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for _, client := range h.clients {
+		select {
+		case client.heartbeat <- struct{}{}:
+		default:
+			// A heartbeat is already pending for this client
+		}
+	}
+}
+
 // ClientCount returns the number of connected clients.
 func (h *Hub) ClientCount() int {
 	h.mu.RLock()
@@ -122,6 +284,33 @@ func (h *Hub) ClientCount() int {
 	return len(h.clients)
 }
 
+// MaxClients returns the client limit, zero when unlimited.
+func (h *Hub) MaxClients() int {
+	return h.maxClients
+}
+
+// LastEventID returns the ID of the most recently broadcast event.
+func (h *Hub) LastEventID() uint64 {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.lastID
+}
+
+// EventsSince returns the buffered events with an ID greater than lastID,
+// oldest first. Events older than the history window are not returned.
+func (h *Hub) EventsSince(lastID uint64) []Event {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	var events []Event
+	for _, event := range h.history {
+		if event.ID > lastID {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
 // SendToClient sends an event to a specific client.
 // Returns false if the client is not found or the channel is full.
 func (h *Hub) SendToClient(id string, event Event) bool {
@@ -129,6 +318,10 @@ func (h *Hub) SendToClient(id string, event Event) bool {
 	client, ok := h.clients[id]
 	h.mu.RUnlock()
 
+	if !ok {
+		return false
+	}
+	event, ok = client.filter(event)
 	if !ok {
 		return false
 	}
@@ -148,14 +341,26 @@ func formatEvent(event Event) string {
 		data = []byte(`{"error":"failed to marshal data"}`)
 	}
 
+	if event.ID != 0 {
+		return fmt.Sprintf("id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, string(data))
+	}
 	return fmt.Sprintf("event: %s\ndata: %s\n\n", event.Type, string(data))
 }
 
+// heartbeatComment is an SSE comment line; clients ignore it per the spec.
+const heartbeatComment = ": keepalive\n\n"
+
 // Handler wraps an SSE client to provide an http.Handler.
 // It handles the SSE connection lifecycle.
 type Handler struct {
 	hub    *Hub
 	client *Client
+
+	// snapshot produces the current state for a newly connected client
+	snapshot func() []Event
+
+	// instanceName is sent in the connected event
+	instanceName string
 }
 
 // NewHandler creates a new SSE handler for the given hub.
@@ -165,16 +370,68 @@ func NewHandler(hub *Hub, clientID string) *Handler {
 	return &Handler{
 		hub: hub,
 		client: &Client{
-			ID:     clientID,
-			Chan:   make(chan Event, 10), // Buffered for client
-			Ctx:    ctx,
-			Cancel: cancel,
+			ID:        clientID,
+			Chan:      make(chan Event, hub.clientBufferSize),
+			Ctx:       ctx,
+			Cancel:    cancel,
+			heartbeat: make(chan struct{}, 1),
 		},
 	}
 }
 
+// SetSnapshot sets a function that produces the initial state events for the
+// client. It is called after the client registers with the hub, and the events
+// are written directly to the connection in order rather than queued on the
+// client channel, so they are delivered even if that channel is full.
+// Returning no events skips the snapshot.
+func (h *Handler) SetSnapshot(fn func() []Event) {
+	h.snapshot = fn
+}
+
+// SetEventTypes limits the client to the given event types. The connected
+// event and heartbeats are always sent. An empty list delivers every event.
+func (h *Handler) SetEventTypes(types []string) {
+	if len(types) == 0 {
+		h.client.EventTypes = nil
+		return
+	}
+	h.client.EventTypes = make(map[string]bool, len(types))
+	for _, t := range types {
+		h.client.EventTypes[t] = true
+	}
+}
+
+// SetInstanceName sets the instance name sent in the connected event.
+func (h *Handler) SetInstanceName(name string) {
+	h.instanceName = name
+}
+
 // ServeHTTP implements http.Handler for SSE connections.
 func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Streaming requires flushing. Check before writing SSE headers or
+	// registering, so a wrapped writer (e.g. compression middleware) gets a
+	// plain 500 and no client is left registered with the hub.
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		h.client.Cancel()
+		http.Error(w, "SSE not supported: response writer cannot flush", http.StatusInternalServerError)
+		return
+	}
+
+	// Register client with hub before writing SSE headers, so a full hub
+	// can still answer with a plain 503
+	if err := h.hub.Register(h.client); err != nil {
+		h.client.Cancel()
+		w.Header().Set("Retry-After", "30")
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	defer h.hub.Unregister(h.client.ID)
+
+	// However the connection ends, cancel the client so the disconnect
+	// watcher below never outlives this call
+	defer h.client.Cancel()
+
 	// Set SSE headers
 	w.Header().Set("Content-Type", "text/event-stream")
 	w.Header().Set("Cache-Control", "no-cache")
@@ -182,30 +439,52 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 
 	// Flush headers to ensure connection is established
-	flusher, ok := w.(http.Flusher)
-	if !ok {
-		http.Error(w, "SSE not supported", http.StatusInternalServerError)
-		return
-	}
 	flusher.Flush()
 
-	// Register client with hub
-	h.hub.Register(h.client)
-	defer h.hub.Unregister(h.client.ID)
-
 	// Send initial connection message
 	h.sendEvent(w, Event{
 		Type: "connected",
-		Data: map[string]string{"clientId": h.client.ID},
+		Data: map[string]string{"clientId": h.client.ID, "instanceName": h.instanceName},
 	}, flusher)
 
+	// Replay events missed since the client's last received event.
+	// lastSent guards against duplicates broadcast between Register and replay.
+	// An ID ahead of the hub means the server restarted and IDs were reset.
+	var lastSent uint64
+	if lastID, err := strconv.ParseUint(r.Header.Get("Last-Event-ID"), 10, 64); err == nil && lastID <= h.hub.LastEventID() {
+		lastSent = lastID
+		for _, event := range h.hub.EventsSince(lastID) {
+			lastSent = event.ID
+			event, ok := h.client.filter(event)
+			if !ok {
+				continue
+			}
+			if !h.sendEvent(w, event, flusher) {
+				return
+			}
+		}
+	}
+
+	// Send the current state last so it supersedes any replayed events
+	if h.snapshot != nil {
+		for _, event := range h.snapshot() {
+			event, ok := h.client.filter(event)
+			if !ok {
+				continue
+			}
+			if !h.sendEvent(w, event, flusher) {
+				return
+			}
+		}
+	}
+
 	// Cancel client context when HTTP request disconnects
 	go func() {
 		select {
 		case <-r.Context().Done():
 			h.client.Cancel()
 		case <-h.client.Ctx.Done():
-			// Client was cancelled externally (e.g., hub shutdown)
+			// Client was cancelled externally or ServeHTTP returned
 		}
 	}()
 
@@ -216,10 +495,20 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			return
 		case <-r.Context().Done():
 			return
-		case event := <-h.client.Chan:
+		case event, ok := <-h.client.Chan:
+			if !ok {
+				// The hub shut down or dropped this client
+				return
+			}
+			if event.ID != 0 && event.ID <= lastSent {
+				continue
+			}
 			if !h.sendEvent(w, event, flusher) {
 				return
 			}
+		case <-h.client.heartbeat:
+			fmt.Fprint(w, heartbeatComment)
+			flusher.Flush()
 		}
 	}
 }
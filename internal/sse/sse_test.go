@@ -1,8 +1,14 @@
 package sse_test
 
 import (
+	"bufio"
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -269,3 +275,448 @@ func TestSSEHubConcurrentAccess(t *testing.T) {
 		t.Errorf("ClientCount = %d, want 5", count)
 	}
 }
+
+// TestSSEHandlerReplaysMissedEvents tests Last-Event-ID resume after a reconnect.
+func TestSSEHandlerReplaysMissedEvents(t *testing.T) {
+	hub := sse.NewHubWithOptions(sse.HubOptions{HistorySize: 10})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go hub.Run(ctx)
+
+	clientNum := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		clientNum++
+		sse.NewHandler(hub, fmt.Sprintf("client-%d", clientNum)).ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	// First connection receives an event and records its ID
+	resp := connectSSE(t, srv.URL, "")
+	reader := bufio.NewReader(resp.Body)
+	if _, typ := readSSEEvent(t, reader); typ != "connected" {
+		t.Fatalf("first event = %s, want connected", typ)
+	}
+	waitForClients(t, hub, 1)
+
+	hub.Broadcast("repos_updated", "first")
+	lastID, typ := readSSEEvent(t, reader)
+	if typ != "repos_updated" || lastID == "" {
+		t.Fatalf("got id=%q type=%s, want repos_updated with an id", lastID, typ)
+	}
+
+	// Disconnect and broadcast while the client is away
+	resp.Body.Close()
+	waitForClients(t, hub, 0)
+
+	hub.Broadcast("new_release", "missed-1")
+	hub.Broadcast("repos_updated", "missed-2")
+	for hub.LastEventID() < 3 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// Reconnect with the last seen ID
+	resp = connectSSE(t, srv.URL, lastID)
+	defer resp.Body.Close()
+	reader = bufio.NewReader(resp.Body)
+	if _, typ := readSSEEvent(t, reader); typ != "connected" {
+		t.Fatalf("first event after reconnect = %s, want connected", typ)
+	}
+
+	wantTypes := []string{"new_release", "repos_updated"}
+	for i, want := range wantTypes {
+		id, typ := readSSEEvent(t, reader)
+		if typ != want {
+			t.Errorf("replayed event %d type = %s, want %s", i, typ, want)
+		}
+		if id == "" {
+			t.Errorf("replayed event %d has no id", i)
+		}
+	}
+}
+
+// TestSSEHandlerSnapshotWithFullBuffer tests that the initial snapshot is
+// delivered even when the client's event buffer is full at connect time.
+func TestSSEHandlerSnapshotWithFullBuffer(t *testing.T) {
+	hub := sse.NewHub()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go hub.Run(ctx)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler := sse.NewHandler(hub, "client-1")
+
+		// Fill the client buffer before the connection is served
+		client := handler.GetClient()
+		for len(client.Chan) < cap(client.Chan) {
+			client.Chan <- sse.Event{Type: "filler"}
+		}
+
+		handler.SetSnapshot(func() []sse.Event {
+			return []sse.Event{{Type: "repos_updated", Data: []string{"catscan"}}}
+		})
+		handler.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	resp := connectSSE(t, srv.URL, "")
+	defer resp.Body.Close()
+	reader := bufio.NewReader(resp.Body)
+
+	if _, typ := readSSEEvent(t, reader); typ != "connected" {
+		t.Fatalf("first event = %s, want connected", typ)
+	}
+	if _, typ := readSSEEvent(t, reader); typ != "repos_updated" {
+		t.Errorf("second event = %s, want repos_updated", typ)
+	}
+}
+
+// TestSSEHeartbeatIsComment tests that heartbeats are written as SSE comments.
+func TestSSEHeartbeatIsComment(t *testing.T) {
+	hub := sse.NewHub()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go hub.Run(ctx)
+
+	srv := httptest.NewServer(sse.NewHandler(hub, "heartbeat-client"))
+	defer srv.Close()
+
+	resp := connectSSE(t, srv.URL, "")
+	defer resp.Body.Close()
+	reader := bufio.NewReader(resp.Body)
+	if _, typ := readSSEEvent(t, reader); typ != "connected" {
+		t.Fatalf("first event = %s, want connected", typ)
+	}
+	waitForClients(t, hub, 1)
+
+	hub.Heartbeat()
+
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		t.Fatalf("reading heartbeat: %v", err)
+	}
+	if !strings.HasPrefix(line, ":") {
+		t.Errorf("heartbeat line = %q, want comment starting with ':'", line)
+	}
+	if next, _ := reader.ReadString('\n'); next != "\n" {
+		t.Errorf("line after heartbeat = %q, want blank line", next)
+	}
+}
+
+// nonFlushingWriter is a ResponseWriter that does not implement http.Flusher.
+type nonFlushingWriter struct {
+	header http.Header
+	status int
+	body   strings.Builder
+}
+
+func (w *nonFlushingWriter) Header() http.Header         { return w.header }
+func (w *nonFlushingWriter) Write(b []byte) (int, error) { return w.body.Write(b) }
+func (w *nonFlushingWriter) WriteHeader(status int)      { w.status = status }
+
+// TestSSEHandlerWithoutFlusher tests that a non-flushing writer gets a clean 500 and no client is registered.
+func TestSSEHandlerWithoutFlusher(t *testing.T) {
+	hub := sse.NewHub()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go hub.Run(ctx)
+
+	handler := sse.NewHandler(hub, "no-flush")
+	w := &nonFlushingWriter{header: make(http.Header)}
+	req := httptest.NewRequest(http.MethodGet, "/api/events", nil)
+
+	done := make(chan struct{})
+	go func() {
+		handler.ServeHTTP(w, req)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("ServeHTTP did not return for a non-flushing writer")
+	}
+
+	if w.status != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", w.status, http.StatusInternalServerError)
+	}
+	if ct := w.header.Get("Content-Type"); strings.HasPrefix(ct, "text/event-stream") {
+		t.Errorf("Content-Type = %s, want a non-SSE error response", ct)
+	}
+	if hub.ClientCount() != 0 {
+		t.Errorf("ClientCount = %d, want 0 (no leaked client)", hub.ClientCount())
+	}
+	if handler.GetClient().Ctx.Err() == nil {
+		t.Error("client context not cancelled")
+	}
+}
+
+// TestSSEClientBufferSize tests that handler clients get the hub's
+// configured buffer size, or the default when none is set.
+func TestSSEClientBufferSize(t *testing.T) {
+	if got := cap(sse.NewHandler(sse.NewHub(), "default").GetClient().Chan); got != sse.DefaultClientBufferSize {
+		t.Errorf("default buffer = %d, want %d", got, sse.DefaultClientBufferSize)
+	}
+
+	hub := sse.NewHubWithOptions(sse.HubOptions{ClientBufferSize: 64})
+	if got := cap(sse.NewHandler(hub, "sized").GetClient().Chan); got != 64 {
+		t.Errorf("configured buffer = %d, want 64", got)
+	}
+}
+
+// TestSSEMaxClients tests that the hub rejects clients past MaxClients,
+// that the handler answers them with 503, and that a slot frees up when a
+// client leaves.
+func TestSSEMaxClients(t *testing.T) {
+	hub := sse.NewHubWithOptions(sse.HubOptions{MaxClients: 2})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go hub.Run(ctx)
+
+	if hub.MaxClients() != 2 {
+		t.Errorf("MaxClients = %d, want 2", hub.MaxClients())
+	}
+	for i := range 2 {
+		client := &sse.Client{ID: fmt.Sprintf("client-%d", i), Chan: make(chan sse.Event, 10), Ctx: ctx, Cancel: cancel}
+		if err := hub.Register(client); err != nil {
+			t.Fatalf("Register(client-%d) = %v, want nil", i, err)
+		}
+	}
+
+	extra := &sse.Client{ID: "client-2", Chan: make(chan sse.Event, 10), Ctx: ctx, Cancel: cancel}
+	if err := hub.Register(extra); !errors.Is(err, sse.ErrTooManyClients) {
+		t.Errorf("Register(client-2) = %v, want ErrTooManyClients", err)
+	}
+	if hub.ClientCount() != 2 {
+		t.Errorf("ClientCount = %d, want 2", hub.ClientCount())
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sse.NewHandler(hub, "http-client").ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	resp := connectSSE(t, srv.URL, "")
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("status over the limit = %d, want 503", resp.StatusCode)
+	}
+
+	hub.Unregister("client-0")
+	waitForClients(t, hub, 1)
+	if err := hub.Register(extra); err != nil {
+		t.Errorf("Register after a client left = %v, want nil", err)
+	}
+}
+
+// TestSSEHandlerRequestCancelCleansUp tests that ending the request
+// unregisters the client, cancels its context, and leaves no goroutine
+// behind.
+func TestSSEHandlerRequestCancelCleansUp(t *testing.T) {
+	hub := sse.NewHub()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go hub.Run(ctx)
+	baseline := runtime.NumGoroutine()
+
+	handler := sse.NewHandler(hub, "leaving")
+	reqCtx, disconnect := context.WithCancel(context.Background())
+	req := httptest.NewRequest(http.MethodGet, "/api/events", nil).WithContext(reqCtx)
+
+	done := make(chan struct{})
+	go func() {
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		close(done)
+	}()
+	waitForClients(t, hub, 1)
+
+	disconnect()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("ServeHTTP did not return after the request was cancelled")
+	}
+
+	waitForClients(t, hub, 0)
+	if handler.GetClient().Ctx.Err() == nil {
+		t.Error("client context not cancelled")
+	}
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > baseline {
+		if time.Now().After(deadline) {
+			t.Fatalf("goroutines = %d, want at most %d after disconnect", runtime.NumGoroutine(), baseline)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// TestSSEHandlerReturnsOnHubShutdown tests that a connected handler returns
+// when the hub shuts down, and that a handler connecting afterwards is
+// refused instead of blocking.
+func TestSSEHandlerReturnsOnHubShutdown(t *testing.T) {
+	hub := sse.NewHub()
+	ctx, cancel := context.WithCancel(context.Background())
+	go hub.Run(ctx)
+
+	handler := sse.NewHandler(hub, "connected")
+	req := httptest.NewRequest(http.MethodGet, "/api/events", nil)
+	done := make(chan struct{})
+	go func() {
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		close(done)
+	}()
+	waitForClients(t, hub, 1)
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("ServeHTTP did not return after hub shutdown")
+	}
+	if handler.GetClient().Ctx.Err() == nil {
+		t.Error("client context not cancelled")
+	}
+
+	w := httptest.NewRecorder()
+	sse.NewHandler(hub, "late").ServeHTTP(w, req)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("status after shutdown = %d, want 503", w.Code)
+	}
+}
+
+// TestSSEClientEventTypes tests that a client with EventTypes only receives
+// its subscribed types, and that a changes batch is narrowed to them.
+func TestSSEClientEventTypes(t *testing.T) {
+	hub := sse.NewHub()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go hub.Run(ctx)
+
+	handler := sse.NewHandler(hub, "filtered")
+	handler.SetEventTypes([]string{"new_release", "actions_changed"})
+	filtered := handler.GetClient()
+	all := &sse.Client{
+		ID:     "all",
+		Chan:   make(chan sse.Event, 10),
+		Ctx:    ctx,
+		Cancel: cancel,
+	}
+	hub.Register(filtered)
+	hub.Register(all)
+	waitForClients(t, hub, 2)
+
+	hub.Broadcast("repo_updated", map[string]string{"name": "meowtern"})
+	hub.Broadcast("new_release", map[string]string{"tag": "v1.0.0"})
+	hub.Broadcast("changes", []map[string]interface{}{
+		{"type": "repo_updated", "name": "meowtern"},
+		{"type": "actions_changed", "name": "meowtern"},
+	})
+	hub.Broadcast("repo_updated", map[string]string{"name": "other-repo"})
+	time.Sleep(10 * time.Millisecond)
+
+	var got []sse.Event
+	for len(filtered.Chan) > 0 {
+		got = append(got, <-filtered.Chan)
+	}
+	if len(got) != 2 {
+		t.Fatalf("filtered client received %d events, want 2: %+v", len(got), got)
+	}
+	if got[0].Type != "new_release" {
+		t.Errorf("first event = %s, want new_release", got[0].Type)
+	}
+	entries, ok := got[1].Data.([]map[string]interface{})
+	if got[1].Type != "changes" || !ok || len(entries) != 1 || entries[0]["type"] != "actions_changed" {
+		t.Errorf("second event = %s %+v, want changes with only actions_changed", got[1].Type, got[1].Data)
+	}
+
+	if len(all.Chan) != 4 {
+		t.Errorf("unfiltered client received %d events, want 4", len(all.Chan))
+	}
+	if hub.SendToClient(filtered.ID, sse.Event{Type: "repo_updated"}) {
+		t.Error("SendToClient delivered an unsubscribed type")
+	}
+}
+
+// TestSSEHubBroadcastAfterShutdown tests that Broadcast doesn't block once
+// the hub has shut down, even after its buffer would have filled.
+func TestSSEHubBroadcastAfterShutdown(t *testing.T) {
+	hub := sse.NewHub()
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		hub.Run(ctx)
+		close(stopped)
+	}()
+	cancel()
+	<-stopped
+
+	done := make(chan struct{})
+	go func() {
+		for i := range 200 {
+			hub.Broadcast("test_event", i)
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Broadcast blocked after hub shutdown")
+	}
+}
+
+// connectSSE opens an SSE connection, optionally resuming from lastEventID.
+func connectSSE(t *testing.T, url, lastEventID string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatalf("creating request: %v", err)
+	}
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("connecting: %v", err)
+	}
+	return resp
+}
+
+// readSSEEvent reads a single SSE event and returns its id and type.
+func readSSEEvent(t *testing.T, reader *bufio.Reader) (id, eventType string) {
+	t.Helper()
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("reading event: %v", err)
+		}
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case line == "":
+			if eventType != "" {
+				return id, eventType
+			}
+		case strings.HasPrefix(line, "id: "):
+			id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "event: "):
+			eventType = strings.TrimPrefix(line, "event: ")
+		}
+	}
+}
+
+// waitForClients waits until the hub has the given number of clients.
+func waitForClients(t *testing.T, hub *sse.Hub, want int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for hub.ClientCount() != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("ClientCount = %d, want %d", hub.ClientCount(), want)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}